@@ -847,4 +847,10 @@ const (
 
 	ReconcileBlockedType   = "ReconcileBlocked"
 	ReconcileBlockedReason = "AnnotationPresent"
+
+	MigratedConditionType                 = "MigratedToBroker"
+	MigratedConditionSuccessReason        = "Migrated"
+	MigratedConditionDryRunReason         = "DryRun"
+	MigratedConditionNotConvertibleReason = "NotConvertible"
+	MigratedConditionBrokerExistsReason   = "BrokerExists"
 )
@@ -147,6 +147,27 @@ type ActiveMQArtemisAddressStatus struct {
 	//+patchStrategy=merge
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
+
+	// The state of the address or queue on each target broker pod
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Brokers"
+	Brokers []AddressBrokerStatus `json:"brokers,omitempty"`
+}
+
+// AddressBrokerStatus is the state of the address or queue on a broker pod
+type AddressBrokerStatus struct {
+	// The broker pod
+	Pod string `json:"pod"`
+	// Whether the address or queue was applied to the broker
+	Applied bool `json:"applied"`
+	// The error of the last failed attempt
+	Error string `json:"error,omitempty"`
+	// The generation of the ActiveMQArtemisAddress that was applied
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// When the address or queue was last applied
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+	// When the address or queue was last recreated after it was removed from the broker out of band
+	LastRepairTime *metav1.Time `json:"lastRepairTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
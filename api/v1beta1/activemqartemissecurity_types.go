@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -52,6 +53,108 @@ type LoginModulesType struct {
 	// Specifies the Keycloak login modules
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Keycloak Login Modules"
 	KeycloakLoginModules []KeycloakLoginModuleType `json:"keycloakLoginModules,omitempty"`
+	// Specifies the LDAP login modules, only supported by restricted brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="LDAP Login Modules"
+	LdapLoginModules []LdapLoginModuleType `json:"ldapLoginModules,omitempty"`
+	// Specifies the text file certificate login modules, only supported by restricted brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Certificate Login Modules"
+	CertificateLoginModules []CertificateLoginModuleType `json:"certificateLoginModules,omitempty"`
+	// Specifies the Kerberos login modules, only supported by restricted brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Kerberos Login Modules"
+	KerberosLoginModules []KerberosLoginModuleType `json:"kerberosLoginModules,omitempty"`
+}
+
+type LdapLoginModuleType struct {
+	// Name for LDAPLoginModule
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name,omitempty"`
+	// URL of the LDAP server, ldaps:// for TLS
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection URL",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ConnectionURL *string `json:"connectionURL,omitempty"`
+	// Name of a secret with the username and password keys used to bind to the LDAP server
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Bind Credentials Secret",xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	BindCredentialsSecret *string `json:"bindCredentialsSecret,omitempty"`
+	// DN used to bind to the LDAP server, the username of the bind credentials secret when set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Username",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ConnectionUsername *string `json:"connectionUsername,omitempty"`
+	// Password used to bind to the LDAP server, the password of the bind credentials secret when set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Password",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:password"}
+	ConnectionPassword *string `json:"connectionPassword,omitempty"`
+	// Security protocol of the connection, ssl for TLS
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Connection Protocol",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ConnectionProtocol *string `json:"connectionProtocol,omitempty"`
+	// Name of a ca bundle secret with the certificates of the LDAP server, the certificates of the first .pem key are added to the trust store of the broker JVM
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Trust Secret",xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	TrustSecret *string `json:"trustSecret,omitempty"`
+	// Authentication method used to bind, simple or none
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Authentication",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Authentication *string `json:"authentication,omitempty"`
+	// DN of the entry the users are searched from
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="User Base",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	UserBase *string `json:"userBase,omitempty"`
+	// Search filter of the users, {0} is replaced by the user name
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="User Search Matching",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	UserSearchMatching *string `json:"userSearchMatching,omitempty"`
+	// If to search the users in the whole subtree of the user base
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="User Search Subtree",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	UserSearchSubtree *bool `json:"userSearchSubtree,omitempty"`
+	// DN of the entry the roles are searched from
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role Base",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RoleBase *string `json:"roleBase,omitempty"`
+	// Attribute of a role entry that holds the name of the role
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RoleName *string `json:"roleName,omitempty"`
+	// Search filter of the roles, {0} is replaced by the user DN and {1} by the user name
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role Search Matching",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RoleSearchMatching *string `json:"roleSearchMatching,omitempty"`
+	// If to search the roles in the whole subtree of the role base
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Role Search Subtree",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	RoleSearchSubtree *bool `json:"roleSearchSubtree,omitempty"`
+	// If to bind as the user to verify its password
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Authenticate User",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	AuthenticateUser *bool `json:"authenticateUser,omitempty"`
+	// How referrals are handled, ignore, follow or throw
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Referral",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Referral *string `json:"referral,omitempty"`
+	// If to add the roles of the roles
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expand Roles",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	ExpandRoles *bool `json:"expandRoles,omitempty"`
+	// Search filter of the roles of a role, {0} is replaced by the role DN
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expand Roles Matching",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ExpandRolesMatching *string `json:"expandRolesMatching,omitempty"`
+}
+
+type CertificateLoginModuleType struct {
+	// Name for TextFileCertificateLoginModule
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name,omitempty"`
+	// Specifies the users authenticated by the subject DN of their client certificate
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Users"
+	Users []CertificateUserType `json:"users,omitempty"`
+}
+
+type CertificateUserType struct {
+	// User name the certificate maps to
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name,omitempty"`
+	// Subject DN of the certificate, a regular expression when enclosed in slashes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="DN",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	DN string `json:"dn,omitempty"`
+	// Roles of the user
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Roles"
+	Roles []string `json:"roles,omitempty"`
+}
+
+type KerberosLoginModuleType struct {
+	// Name for Krb5LoginModule
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name,omitempty"`
+	// Service principal of the broker used to accept GSSAPI connections, like amqp/broker.example.com@EXAMPLE.COM
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Principal",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Principal *string `json:"principal,omitempty"`
+	// Name of a secret with the keytab key of the service principal, the secret is mounted in the broker pods
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="KeyTab Secret",xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	KeyTabSecret *string `json:"keyTabSecret,omitempty"`
 }
 
 type PropertiesLoginModuleType struct {
@@ -61,6 +164,9 @@ type PropertiesLoginModuleType struct {
 	// Specifies the users
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Users"
 	Users []UserType `json:"users,omitempty"`
+	// Store the passwords as one-way PBKDF2 hashes, ENC(), in the users file
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Hash Passwords",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	HashPasswords *bool `json:"hashPasswords,omitempty"`
 }
 
 type UserType struct {
@@ -70,6 +176,9 @@ type UserType struct {
 	// Password to be defined in properties login module
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Password",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:password"}
 	Password *string `json:"password,omitempty"`
+	// Reference to the secret key of the password, used when password is not set. A rotation of the secret is applied to the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Password Secret"
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty"`
 	// Roles to be defined in properties login module
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Roles"
 	Roles []string `json:"roles,omitempty"`
@@ -208,6 +208,13 @@ func (in *ActiveMQArtemisAddressStatus) DeepCopyInto(out *ActiveMQArtemisAddress
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]AddressBrokerStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveMQArtemisAddressStatus.
@@ -555,6 +562,29 @@ func (in *ActiveMQArtemisUpgrades) DeepCopy() *ActiveMQArtemisUpgrades {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddressBrokerStatus) DeepCopyInto(out *AddressBrokerStatus) {
+	*out = *in
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRepairTime != nil {
+		in, out := &in.LastRepairTime, &out.LastRepairTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddressBrokerStatus.
+func (in *AddressBrokerStatus) DeepCopy() *AddressBrokerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AddressBrokerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddressSettingType) DeepCopyInto(out *AddressSettingType) {
 	*out = *in
@@ -1057,6 +1087,48 @@ func (in *BrokerSecuritySettingType) DeepCopy() *BrokerSecuritySettingType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateLoginModuleType) DeepCopyInto(out *CertificateLoginModuleType) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]CertificateUserType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateLoginModuleType.
+func (in *CertificateLoginModuleType) DeepCopy() *CertificateLoginModuleType {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateLoginModuleType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateUserType) DeepCopyInto(out *CertificateUserType) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateUserType.
+func (in *CertificateUserType) DeepCopy() *CertificateUserType {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateUserType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectorConfigType) DeepCopyInto(out *ConnectorConfigType) {
 	*out = *in
@@ -1428,6 +1500,31 @@ func (in *GuestLoginModuleType) DeepCopy() *GuestLoginModuleType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KerberosLoginModuleType) DeepCopyInto(out *KerberosLoginModuleType) {
+	*out = *in
+	if in.Principal != nil {
+		in, out := &in.Principal, &out.Principal
+		*out = new(string)
+		**out = **in
+	}
+	if in.KeyTabSecret != nil {
+		in, out := &in.KeyTabSecret, &out.KeyTabSecret
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KerberosLoginModuleType.
+func (in *KerberosLoginModuleType) DeepCopy() *KerberosLoginModuleType {
+	if in == nil {
+		return nil
+	}
+	out := new(KerberosLoginModuleType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyValueType) DeepCopyInto(out *KeyValueType) {
 	*out = *in
@@ -1693,6 +1790,111 @@ func (in *KeycloakModuleConfigurationType) DeepCopy() *KeycloakModuleConfigurati
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LdapLoginModuleType) DeepCopyInto(out *LdapLoginModuleType) {
+	*out = *in
+	if in.ConnectionURL != nil {
+		in, out := &in.ConnectionURL, &out.ConnectionURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.BindCredentialsSecret != nil {
+		in, out := &in.BindCredentialsSecret, &out.BindCredentialsSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConnectionUsername != nil {
+		in, out := &in.ConnectionUsername, &out.ConnectionUsername
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConnectionPassword != nil {
+		in, out := &in.ConnectionPassword, &out.ConnectionPassword
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConnectionProtocol != nil {
+		in, out := &in.ConnectionProtocol, &out.ConnectionProtocol
+		*out = new(string)
+		**out = **in
+	}
+	if in.TrustSecret != nil {
+		in, out := &in.TrustSecret, &out.TrustSecret
+		*out = new(string)
+		**out = **in
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(string)
+		**out = **in
+	}
+	if in.UserBase != nil {
+		in, out := &in.UserBase, &out.UserBase
+		*out = new(string)
+		**out = **in
+	}
+	if in.UserSearchMatching != nil {
+		in, out := &in.UserSearchMatching, &out.UserSearchMatching
+		*out = new(string)
+		**out = **in
+	}
+	if in.UserSearchSubtree != nil {
+		in, out := &in.UserSearchSubtree, &out.UserSearchSubtree
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RoleBase != nil {
+		in, out := &in.RoleBase, &out.RoleBase
+		*out = new(string)
+		**out = **in
+	}
+	if in.RoleName != nil {
+		in, out := &in.RoleName, &out.RoleName
+		*out = new(string)
+		**out = **in
+	}
+	if in.RoleSearchMatching != nil {
+		in, out := &in.RoleSearchMatching, &out.RoleSearchMatching
+		*out = new(string)
+		**out = **in
+	}
+	if in.RoleSearchSubtree != nil {
+		in, out := &in.RoleSearchSubtree, &out.RoleSearchSubtree
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AuthenticateUser != nil {
+		in, out := &in.AuthenticateUser, &out.AuthenticateUser
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Referral != nil {
+		in, out := &in.Referral, &out.Referral
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExpandRoles != nil {
+		in, out := &in.ExpandRoles, &out.ExpandRoles
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExpandRolesMatching != nil {
+		in, out := &in.ExpandRolesMatching, &out.ExpandRolesMatching
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LdapLoginModuleType.
+func (in *LdapLoginModuleType) DeepCopy() *LdapLoginModuleType {
+	if in == nil {
+		return nil
+	}
+	out := new(LdapLoginModuleType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoginModuleReferenceType) DeepCopyInto(out *LoginModuleReferenceType) {
 	*out = *in
@@ -1752,6 +1954,27 @@ func (in *LoginModulesType) DeepCopyInto(out *LoginModulesType) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LdapLoginModules != nil {
+		in, out := &in.LdapLoginModules, &out.LdapLoginModules
+		*out = make([]LdapLoginModuleType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CertificateLoginModules != nil {
+		in, out := &in.CertificateLoginModules, &out.CertificateLoginModules
+		*out = make([]CertificateLoginModuleType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KerberosLoginModules != nil {
+		in, out := &in.KerberosLoginModules, &out.KerberosLoginModules
+		*out = make([]KerberosLoginModuleType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoginModulesType.
@@ -1870,6 +2093,11 @@ func (in *PropertiesLoginModuleType) DeepCopyInto(out *PropertiesLoginModuleType
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HashPasswords != nil {
+		in, out := &in.HashPasswords, &out.HashPasswords
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PropertiesLoginModuleType.
@@ -2205,6 +2433,11 @@ func (in *UserType) DeepCopyInto(out *UserType) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Roles != nil {
 		in, out := &in.Roles, &out.Roles
 		*out = make([]string, len(*in))
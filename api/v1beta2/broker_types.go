@@ -68,6 +68,9 @@ type BrokerSpec struct {
 	// The default ingress domain. It is required when any acceptor, connector or console uses the ingress mode and does not specify an IngressHost.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ingress Domain",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	IngressDomain string `json:"ingressDomain,omitempty"`
+	// The Gateway API Gateway that the routes attach to. It is required when any acceptor, connector or console uses the gateway mode.
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Gateway"
+	Gateway *GatewayType `json:"gateway,omitempty"`
 	// Specifies the template for various resources that the operator controls
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Resource Templates"
 	ResourceTemplates []ResourceTemplate `json:"resourceTemplates,omitempty"`
@@ -75,6 +78,30 @@ type BrokerSpec struct {
 	// Restricted deployment, mtls jolokia agent with RBAC
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Restricted"
 	Restricted *bool `json:"restricted,omitempty"`
+
+	// Specifies high availability, the operator deploys primary/backup pairs and configures their HA policy
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="High Availability"
+	HA *HAType `json:"ha,omitempty"`
+
+	// Specifies AMQP broker connections from this broker to other brokers, for mirroring or federation
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker Connections"
+	BrokerConnections []BrokerConnectionType `json:"brokerConnections,omitempty"`
+
+	// Specifies autoscaling, the operator adjusts DeploymentPlan.Size from the message and consumer counts of the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Autoscaling"
+	Autoscaling *AutoscalingType `json:"autoscaling,omitempty"`
+
+	// Specifies how changes to the broker pods are rolled out, by default all the pods are updated by the StatefulSet
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Rollout"
+	Rollout *RolloutType `json:"rollout,omitempty"`
+
+	// Specifies when changes that restart the brokers can be applied, changes to the pod template are held outside the window
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Maintenance Window"
+	MaintenanceWindow *MaintenanceWindowType `json:"maintenanceWindow,omitempty"`
+
+	// Specifies when a newer broker version that matches .Spec.Version is applied, by default it is applied when detected
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Update Policy"
+	UpdatePolicy *UpdatePolicyType `json:"updatePolicy,omitempty"`
 }
 
 type AddressSettingsType struct {
@@ -499,15 +526,227 @@ type StorageType struct {
 	StorageClassName string `json:"storageClassName,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=ingress;route
+// +kubebuilder:validation:Enum=replication;sharedStore
+type HAPolicy string
+
+var HAPolicies = struct {
+	Replication HAPolicy
+	SharedStore HAPolicy
+}{
+	Replication: "replication",
+	SharedStore: "sharedStore",
+}
+
+type HAType struct {
+	// The HA policy of the pairs, replication or sharedStore. Brokers with an even ordinal are primaries, the next ordinal is their backup
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Policy"
+	Policy HAPolicy `json:"policy"`
+	// Allow a primary to take over again from its backup when it restarts, default is true
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Allow Fail Back",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	AllowFailBack *bool `json:"allowFailBack,omitempty"`
+	// The name of a ReadWriteMany PersistentVolumeClaim that holds the journal of every pair, required by the sharedStore policy
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Shared Store Claim Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	SharedStoreClaimName string `json:"sharedStoreClaimName,omitempty"`
+	// The topology key used to keep the members of a pair apart, default is kubernetes.io/hostname. Ignored when DeploymentPlan.Affinity.PodAntiAffinity is set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Anti Affinity Topology Key",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	AntiAffinityTopologyKey string `json:"antiAffinityTopologyKey,omitempty"`
+	// Require rather than prefer the members of a pair to be scheduled apart, default is false
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Required Anti Affinity",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	RequiredAntiAffinity bool `json:"requiredAntiAffinity,omitempty"`
+}
+
+type BrokerConnectionType struct {
+	// The broker connection name
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
+	// A reference to the target Broker CR, the operator connects to each of its brokers in turn. One of BrokerRef or URI is required
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker Reference"
+	BrokerRef *BrokerReference `json:"brokerRef,omitempty"`
+	// The URI of the target broker, tcp://host:port, for a target outside of the cluster. One of BrokerRef or URI is required
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="URI",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	URI string `json:"uri,omitempty"`
+	// The acceptor port of the target Broker CR, default is 61616
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Port",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	Port int32 `json:"port,omitempty"`
+	// The name of a secret with user and password keys, the credentials used to connect to the target
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Credentials Secret",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+	// Use mutual TLS, the broker presents the operand certificate and trusts the operator CA
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mutual TLS",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	MTLS bool `json:"mtls,omitempty"`
+	// The interval in milliseconds between reconnect attempts, default is 5000
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Retry Interval",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	RetryInterval *int32 `json:"retryInterval,omitempty"`
+	// The number of reconnect attempts, -1 for unlimited which is the default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Reconnect Attempts",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	ReconnectAttempts *int32 `json:"reconnectAttempts,omitempty"`
+	// Mirror the messages, acknowledgements and queues of this broker to the target
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mirror"
+	Mirror *BrokerConnectionMirrorType `json:"mirror,omitempty"`
+	// Federate addresses and queues from the target to this broker when there is local demand
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Federation"
+	Federation *BrokerConnectionFederationType `json:"federation,omitempty"`
+}
+
+type BrokerReference struct {
+	// The name of the Broker CR
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
+	// The namespace of the Broker CR, default is the namespace of this CR
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type BrokerConnectionMirrorType struct {
+	// Mirror message acknowledgements, default is true
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Message Acknowledgements",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	MessageAcknowledgements *bool `json:"messageAcknowledgements,omitempty"`
+	// Mirror queue creation, default is true
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queue Creation",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	QueueCreation *bool `json:"queueCreation,omitempty"`
+	// Mirror queue removal, default is true
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queue Removal",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	QueueRemoval *bool `json:"queueRemoval,omitempty"`
+	// A comma separated list of address prefixes to mirror, prefixes starting with ! are excluded
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Address Filter",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	AddressFilter string `json:"addressFilter,omitempty"`
+	// Wait for the target to store a message before the local send completes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Sync",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	Sync bool `json:"sync,omitempty"`
+}
+
+type BrokerConnectionFederationType struct {
+	// Address match patterns to federate from the target
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Addresses"
+	Addresses []string `json:"addresses,omitempty"`
+	// Queue match patterns to federate from the target
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queues"
+	Queues []string `json:"queues,omitempty"`
+}
+
+type AutoscalingType struct {
+	// The lower bound of DeploymentPlan.Size, default is 1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Min Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	MinSize *int32 `json:"minSize,omitempty"`
+	// The upper bound of DeploymentPlan.Size
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Max Size",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	MaxSize int32 `json:"maxSize"`
+	// The number of pending messages each broker should hold, the total message count of all brokers drives the size
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Target Message Count",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	TargetMessageCount int64 `json:"targetMessageCount,omitempty"`
+	// The number of consumers each broker should serve, the total consumer count of all brokers drives the size
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Target Consumer Count",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	TargetConsumerCount int64 `json:"targetConsumerCount,omitempty"`
+	// The time to wait after a scale event before scaling up, default is 60
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scale Up Cooldown Seconds",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	ScaleUpCooldownSeconds *int32 `json:"scaleUpCooldownSeconds,omitempty"`
+	// The time to wait after a scale event before scaling down, default is 300
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scale Down Cooldown Seconds",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	ScaleDownCooldownSeconds *int32 `json:"scaleDownCooldownSeconds,omitempty"`
+	// The interval between reads of the broker metrics, default is 30
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Poll Interval Seconds",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	PollIntervalSeconds *int32 `json:"pollIntervalSeconds,omitempty"`
+}
+
+type RolloutType struct {
+	// Update one broker at a time from the highest ordinal, each broker must report the expected version
+	// and the applied broker properties before the next one is updated
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Canary",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Canary bool `json:"canary,omitempty"`
+	// Hold the rollout at the current broker, set and unset to resume a rollout paused on failure
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Paused",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	Paused bool `json:"paused,omitempty"`
+	// The time a broker has to become ready on the new revision before the rollout is paused, default is 600
+	//+kubebuilder:validation:Minimum=1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Progress Deadline Seconds",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type MaintenanceWindowDay string
+
+type MaintenanceWindowType struct {
+	// The days of the week the window opens on, every day when empty
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Days"
+	Days []MaintenanceWindowDay `json:"days,omitempty"`
+	// The time of day the window opens, in the 24 hour HH:MM format
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Start Time",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	StartTime string `json:"startTime"`
+	// The length of the window, for example 2h or 90m
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Duration",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Duration string `json:"duration"`
+	// The IANA time zone of the start time, default is UTC
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Time Zone",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=auto;approvalRequired;schedule
+type UpdatePolicyMode string
+
+const (
+	UpdatePolicyModeAuto             UpdatePolicyMode = "auto"
+	UpdatePolicyModeApprovalRequired UpdatePolicyMode = "approvalRequired"
+	UpdatePolicyModeSchedule         UpdatePolicyMode = "schedule"
+)
+
+type UpdatePolicyType struct {
+	// How a newer version is applied, auto applies it when detected, approvalRequired waits for the
+	// arkmq.org/approve-upgrade annotation with the version and schedule waits for the schedule to open, default is auto
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mode"
+	Mode UpdatePolicyMode `json:"mode,omitempty"`
+	// The window a newer version is applied in, required with the schedule mode
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Schedule"
+	Schedule *MaintenanceWindowType `json:"schedule,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=ingress;route;gateway;loadBalancer;nodePort
 type ExposeMode string
 
 var ExposeModes = struct {
-	Ingress ExposeMode
-	Route   ExposeMode
+	Ingress      ExposeMode
+	Route        ExposeMode
+	Gateway      ExposeMode
+	LoadBalancer ExposeMode
+	NodePort     ExposeMode
 }{
-	Ingress: "ingress",
-	Route:   "route",
+	Ingress:      "ingress",
+	Route:        "route",
+	Gateway:      "gateway",
+	LoadBalancer: "loadBalancer",
+	NodePort:     "nodePort",
+}
+
+type ExposeServiceType struct {
+	// Annotations of the Services that expose the acceptor, for example to configure the load balancer of a cloud provider
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Annotations",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// The client source ranges that can reach the load balancers of the loadBalancer mode, default is any source
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Load Balancer Source Ranges",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// The node ports of the nodePort mode, one for each broker in the order of the ordinals, a broker without a node port gets one assigned by Kubernetes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Node Ports",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	NodePorts []int32 `json:"nodePorts,omitempty"`
+	// The host that clients outside the cluster use to reach a broker. It supports the following variables: $(CR_NAME), $(CR_NAMESPACE), $(BROKER_ORDINAL), $(ITEM_NAME) and $(INGRESS_DOMAIN). Default is the address assigned to the load balancer of the loadBalancer mode, the nodePort mode has no default
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Advertised Host",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	AdvertisedHost string `json:"advertisedHost,omitempty"`
+	// The name of a cluster connection of the brokers that advertises the external address of each broker to the clients that follow the cluster topology, the brokers connect to each other on that address too. The operator adds a <acceptor name>-external connector to each broker and sets it as the connector of the cluster connection. Default is none, the external address is not advertised
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Cluster Connection",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	ClusterConnection string `json:"clusterConnection,omitempty"`
+}
+
+type GatewayType struct {
+	// The name of the Gateway
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name"`
+	// The namespace of the Gateway, default is the namespace of the CR
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Namespace",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Namespace string `json:"namespace,omitempty"`
+	// The listener of the Gateway for the TLS passthrough routes of the acceptors, the connectors and a console with SSL enabled, default is any listener that accepts TLSRoutes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TLS Listener",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	TLSListener string `json:"tlsListener,omitempty"`
+	// The listener of the Gateway for the HTTP route of a console without SSL, default is any listener that accepts HTTPRoutes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="HTTP Listener",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	HTTPListener string `json:"httpListener,omitempty"`
 }
 
 type AcceptorType struct {
@@ -547,12 +786,18 @@ type AcceptorType struct {
 	// A regular expression used to match the server_name extension on incoming SSL connections. If the name doesn't match then the connection to the acceptor will be rejected.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="SNI Host",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	SNIHost string `json:"sniHost,omitempty"`
+	// Reload a renewed certificate of a PEM SSL secret, with the tls.crt and tls.key keys, without restarting the brokers. Default is false, a renewed certificate restarts the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="SSL Auto Reload",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
+	SSLAutoReload bool `json:"sslAutoReload,omitempty"`
 	// Whether or not to expose this acceptor
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	Expose bool `json:"expose,omitempty"`
-	// Mode to expose the acceptor. Currently the supported modes are `route` and `ingress`. It is ignored when the field `Expose` is false. Default is `route` on OpenShift and `ingress` on Kubernetes. \n\n* `route` mode uses OpenShift Routes to expose the acceptor.\n* `ingress` mode uses Kubernetes Nginx Ingress to expose the acceptor with TLS passthrough.\n"
+	// Mode to expose the acceptor. Currently the supported modes are `route`, `ingress`, `gateway`, `loadBalancer` and `nodePort`. It is ignored when the field `Expose` is false. Default is `route` on OpenShift and `ingress` on Kubernetes. \n\n* `route` mode uses OpenShift Routes to expose the acceptor.\n* `ingress` mode uses Kubernetes Nginx Ingress to expose the acceptor with TLS passthrough.\n* `gateway` mode uses a Gateway API TLSRoute with TLS passthrough, it requires SSL, attached to the Spec.Gateway to expose the acceptor.\n* `loadBalancer` mode uses a Service of type LoadBalancer for each broker to expose the acceptor.\n* `nodePort` mode uses a Service of type NodePort for each broker to expose the acceptor.\n"
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose Mode",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	ExposeMode *ExposeMode `json:"exposeMode,omitempty"`
+	// The settings of the Services of the loadBalancer and nodePort expose modes
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose Service"
+	ExposeService *ExposeServiceType `json:"exposeService,omitempty"`
 	// To indicate which kind of routing type to use.
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Anycast Prefix",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	AnycastPrefix string `json:"anycastPrefix,omitempty"`
@@ -634,7 +879,7 @@ type ConnectorType struct {
 	// Whether or not to expose this connector
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	Expose bool `json:"expose,omitempty"`
-	// Mode to expose the connector. Currently the supported modes are `route` and `ingress`. It is ignored when the field `Expose` is false. Default is `route` on OpenShift and `ingress` on Kubernetes. \n\n* `route` mode uses OpenShift Routes to expose the connector.\n* `ingress` mode uses Kubernetes Nginx Ingress to expose the connector with TLS passthrough.\n"
+	// Mode to expose the connector. Currently the supported modes are `route`, `ingress` and `gateway`. It is ignored when the field `Expose` is false. Default is `route` on OpenShift and `ingress` on Kubernetes. \n\n* `route` mode uses OpenShift Routes to expose the connector.\n* `ingress` mode uses Kubernetes Nginx Ingress to expose the connector with TLS passthrough.\n* `gateway` mode uses a Gateway API TLSRoute with TLS passthrough, it requires SSL, attached to the Spec.Gateway to expose the connector.\n"
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose Mode",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	ExposeMode *ExposeMode `json:"exposeMode,omitempty"`
 	// Provider used for the keystore; "SUN", "SunJCE", etc. Default is null
@@ -661,7 +906,7 @@ type ConsoleType struct {
 	// Whether or not to expose this port
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch"}
 	Expose bool `json:"expose,omitempty"`
-	// Mode to expose the console. Currently the supported modes are `route` and `ingress`. It is ignored when the field `Expose` is false. Default is `route` on OpenShift and `ingress` on Kubernetes. \n\n* `route` mode uses OpenShift Routes to expose the console.\n* `ingress` mode uses Kubernetes Nginx Ingress to expose the console with TLS passthrough.\n"
+	// Mode to expose the console. Currently the supported modes are `route`, `ingress` and `gateway`. It is ignored when the field `Expose` is false. Default is `route` on OpenShift and `ingress` on Kubernetes. \n\n* `route` mode uses OpenShift Routes to expose the console.\n* `ingress` mode uses Kubernetes Nginx Ingress to expose the console with TLS passthrough.\n* `gateway` mode uses a Gateway API TLSRoute with TLS passthrough, or an HTTPRoute without SSL, attached to the Spec.Gateway to expose the console.\n"
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Expose Mode",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	ExposeMode *ExposeMode `json:"exposeMode,omitempty"`
 	// Whether or not to enable SSL on this port
@@ -726,6 +971,116 @@ type BrokerStatus struct {
 
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Upgrade Status"
 	Upgrade UpgradeStatus `json:"upgrade,omitempty"`
+
+	// Current state of the broker connections
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Broker Connections Status"
+	BrokerConnections []BrokerConnectionStatus `json:"brokerConnections,omitempty"`
+
+	// Current state of the autoscaler
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Autoscaling Status"
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// The changes that a reconcile would apply to the deployed resources, set while the plan annotation is present
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Plan"
+	Plan *PlanStatus `json:"plan,omitempty"`
+
+	// The addresses of the acceptors exposed with the loadBalancer and nodePort modes
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="External Addresses"
+	ExternalAddresses []ExternalAddressStatus `json:"externalAddresses,omitempty"`
+}
+
+type ExternalAddressStatus struct {
+	// The name of the acceptor
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Acceptor",xDescriptors="urn:alm:descriptor:text"
+	Acceptor string `json:"acceptor"`
+	// The ordinal of the broker
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Ordinal",xDescriptors="urn:alm:descriptor:text"
+	Ordinal int32 `json:"ordinal"`
+	// The name of the Service that exposes the acceptor of the broker
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Service",xDescriptors="urn:alm:descriptor:text"
+	Service string `json:"service"`
+	// The host that clients outside the cluster use, empty while the load balancer is not assigned
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Host",xDescriptors="urn:alm:descriptor:text"
+	Host string `json:"host,omitempty"`
+	// The port that clients outside the cluster use
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Port",xDescriptors="urn:alm:descriptor:text"
+	Port int32 `json:"port,omitempty"`
+}
+
+type PlanStatus struct {
+	// The generation of the CR that the plan is computed from
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed Generation",xDescriptors="urn:alm:descriptor:text"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The most disruptive impact of the changes, Restart, Reload or None
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Impact",xDescriptors="urn:alm:descriptor:text"
+	Impact PlanImpact `json:"impact"`
+	// The planned change of each resource
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Resources"
+	Resources []PlannedResource `json:"resources,omitempty"`
+}
+
+type PlannedResource struct {
+	// The kind of the resource
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Kind",xDescriptors="urn:alm:descriptor:text"
+	Kind string `json:"kind"`
+	// The name of the resource
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Name",xDescriptors="urn:alm:descriptor:text"
+	Name string `json:"name"`
+	// The change to the resource, None, Create, Update or Delete
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Action",xDescriptors="urn:alm:descriptor:text"
+	Action PlanAction `json:"action"`
+	// The impact of the change on the brokers, Restart for a change to the pod template, Reload for a change to
+	// the broker properties and None otherwise
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Impact",xDescriptors="urn:alm:descriptor:text"
+	Impact PlanImpact `json:"impact"`
+	// The changed fields, or the changed keys of a secret
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Fields",xDescriptors="urn:alm:descriptor:text"
+	Fields []string `json:"fields,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=None;Create;Update;Delete
+type PlanAction string
+
+const (
+	PlanActionNone   PlanAction = "None"
+	PlanActionCreate PlanAction = "Create"
+	PlanActionUpdate PlanAction = "Update"
+	PlanActionDelete PlanAction = "Delete"
+)
+
+// +kubebuilder:validation:Enum=None;Reload;Restart
+type PlanImpact string
+
+const (
+	PlanImpactNone    PlanImpact = "None"
+	PlanImpactReload  PlanImpact = "Reload"
+	PlanImpactRestart PlanImpact = "Restart"
+)
+
+type AutoscalingStatus struct {
+	// The size computed from the last read of the broker metrics
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Desired Size",xDescriptors="urn:alm:descriptor:text"
+	DesiredSize int32 `json:"desiredSize,omitempty"`
+	// The total message count of the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Message Count",xDescriptors="urn:alm:descriptor:text"
+	MessageCount int64 `json:"messageCount,omitempty"`
+	// The total consumer count of the brokers
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Consumer Count",xDescriptors="urn:alm:descriptor:text"
+	ConsumerCount int64 `json:"consumerCount,omitempty"`
+	// The time of the last change to DeploymentPlan.Size by the autoscaler
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Last Scale Time",xDescriptors="urn:alm:descriptor:text"
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+}
+
+type BrokerConnectionStatus struct {
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Name",xDescriptors="urn:alm:descriptor:text"
+	Name string `json:"name"`
+	// The broker pods with an open connection to the target
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Connected",xDescriptors="urn:alm:descriptor:text"
+	Connected []string `json:"connected,omitempty"`
+	// The broker pods without an open connection to the target
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Disconnected",xDescriptors="urn:alm:descriptor:text"
+	Disconnected []string `json:"disconnected,omitempty"`
 }
 
 type VersionStatus struct {
@@ -749,6 +1104,38 @@ type UpgradeStatus struct {
 	MinorUpdates bool `json:"minorUpdates"` // false if version = x.y
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="PatchUpdates",xDescriptors="urn:alm:descriptor:text"
 	PatchUpdates bool `json:"patchUpdates"` // false if version = x.y.z
+
+	// The progress of a canary rollout
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Rollout"
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// The newer version that the update policy holds, the brokers stay on .Status.Version.BrokerVersion
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Pending Version",xDescriptors="urn:alm:descriptor:text"
+	PendingVersion string `json:"pendingVersion,omitempty"`
+}
+
+type RolloutStatus struct {
+	// The StatefulSet revision that is rolled out
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Revision",xDescriptors="urn:alm:descriptor:text"
+	Revision string `json:"revision,omitempty"`
+	// The StatefulSet partition, the brokers with an ordinal greater than or equal to the partition are updated
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Partition",xDescriptors="urn:alm:descriptor:text"
+	Partition int32 `json:"partition"`
+	// The number of brokers on the revision
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Updated Brokers",xDescriptors="urn:alm:descriptor:text"
+	UpdatedBrokers int32 `json:"updatedBrokers"`
+	// The rollout is on hold
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Paused",xDescriptors="urn:alm:descriptor:text"
+	Paused bool `json:"paused,omitempty"`
+	// The rollout is on hold because a broker was not updated within the progress deadline
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Failed",xDescriptors="urn:alm:descriptor:text"
+	Failed bool `json:"failed,omitempty"`
+	// The state of the broker that is updated
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Message",xDescriptors="urn:alm:descriptor:text"
+	Message string `json:"message,omitempty"`
+	// The time the rollout last moved to the next broker
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Last Progress Time",xDescriptors="urn:alm:descriptor:text"
+	LastProgressTime *metav1.Time `json:"lastProgressTime,omitempty"`
 }
 
 type ExternalConfigStatus struct {
@@ -809,6 +1196,7 @@ const (
 	DeployedConditionMatchedServiceNotFoundReason = "MatchedServiceNotFound"
 	DeployedConditionProvisioningPendingReason    = "ProvisioningPending"
 	DeployedConditionProvisionedReason            = "Provisioned"
+	DeployedConditionNoMatchingBrokerReason       = "NoMatchingBroker"
 
 	AppsProvisionedConditionType           = "AppsProvisioned"
 	AppsProvisionedConditionSyncedReason   = "Synced"
@@ -834,6 +1222,13 @@ const (
 	ValidConditionInvalidCertSecretReason            = "InvalidCertSecret"
 	ValidConditionFailedDuplicateBrokerPropertiesKey = "DuplicateBrokerPropertiesKey"
 	ValidConditionInvalidInternalVarUsage            = "InvalidInternalVarUsage"
+	ValidConditionFailedInvalidHAConfig              = "InvalidHAConfig"
+	ValidConditionFailedInvalidBrokerConnection      = "InvalidBrokerConnection"
+	ValidConditionFailedInvalidAutoscaling           = "InvalidAutoscaling"
+	ValidConditionFailedInvalidMaintenanceWindow     = "InvalidMaintenanceWindow"
+	ValidConditionFailedInvalidUpdatePolicy          = "InvalidUpdatePolicy"
+	ValidConditionFailedInvalidBrokerProperties      = "InvalidBrokerProperties"
+	ValidConditionFailedUnsupportedLoginModules      = "UnsupportedLoginModules"
 
 	ReadyConditionType      = "Ready"
 	ReadyConditionReason    = "ResourceReady"
@@ -858,6 +1253,40 @@ const (
 	ScaleDownPendingConditionPendingEmptyReason         = "PendingEmpty" // no messages
 	ScaleDownPendingConditionPendingDeleteReason        = "PendingDelete"
 
+	HAReplicatingConditionType              = "Replicating"
+	HAReplicatingConditionInSyncReason      = "ReplicaInSync"
+	HAReplicatingConditionPendingReason     = "ReplicaPendingSync"
+	HAReplicatingConditionFailedOverReason  = "NoReplica"
+	HAFailedOverConditionType               = "FailedOver"
+	HAFailedOverConditionBackupActiveReason = "BackupActive"
+	HAConditionUnknownReason                = "UnableToRetrieveHAStatus"
+
+	BrokerConnectionsConnectedConditionType          = "BrokerConnectionsConnected"
+	BrokerConnectionsConnectedConditionReason        = "Connected"
+	BrokerConnectionsConnectedConditionPendingReason = "NotConnected"
+	BrokerConnectionsConnectedConditionUnknownReason = "UnableToRetrieveBrokerConnectionsStatus"
+
+	PendingRestartConditionType                = "PendingRestart"
+	PendingRestartConditionOutsideWindowReason = "OutsideMaintenanceWindow"
+
+	PendingUpgradeConditionType                   = "PendingUpgrade"
+	PendingUpgradeConditionAwaitingApprovalReason = "AwaitingApproval"
+	PendingUpgradeConditionOutsideScheduleReason  = "OutsideSchedule"
+
+	GatewayRoutesAcceptedConditionType           = "GatewayRoutesAccepted"
+	GatewayRoutesAcceptedConditionReason         = "Accepted"
+	GatewayRoutesAcceptedConditionPendingReason  = "Pending"
+	GatewayRoutesAcceptedConditionRejectedReason = "NotAccepted"
+
+	CertificatesValidConditionType           = "CertificatesValid"
+	CertificatesValidConditionReason         = "Valid"
+	CertificatesValidConditionExpiringReason = "ExpiringSoon"
+	CertificatesValidConditionExpiredReason  = "Expired"
+
+	BrokersReachableConditionType              = "BrokersReachable"
+	BrokersReachableConditionReason            = "Reachable"
+	BrokersReachableConditionCircuitOpenReason = "CircuitOpen"
+
 	ReconcileBlockedType   = "ReconcileBlocked"
 	ReconcileBlockedReason = "AnnotationPresent"
 )
@@ -0,0 +1,129 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=ANYCAST;MULTICAST
+type RoutingType string
+
+const (
+	RoutingTypeAnycast   RoutingType = "ANYCAST"
+	RoutingTypeMulticast RoutingType = "MULTICAST"
+)
+
+type BrokerAddressSpec struct {
+	// The label selector of the Brokers in the same namespace the address is configured on, all the Brokers when empty
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker Selector"
+	BrokerSelector *metav1.LabelSelector `json:"brokerSelector,omitempty"`
+
+	// The name of the address
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Address"
+	Address string `json:"address"`
+
+	// The routing types of the address, the routing types of its queues are added, MULTICAST when there are none
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Routing Types"
+	RoutingTypes []RoutingType `json:"routingTypes,omitempty"`
+
+	// The queues of the address
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queues"
+	Queues []BrokerQueueType `json:"queues,omitempty"`
+}
+
+type BrokerQueueType struct {
+	// The name of the queue
+	Name string `json:"name"`
+	// The routing type of the queue, MULTICAST when not set
+	RoutingType RoutingType `json:"routingType,omitempty"`
+	// The filter of the messages routed to the queue
+	FilterString string `json:"filterString,omitempty"`
+	// Whether the messages of the queue are persisted
+	Durable *bool `json:"durable,omitempty"`
+	// The maximum number of consumers of the queue, -1 for unlimited
+	MaxConsumers *int32 `json:"maxConsumers,omitempty"`
+	// Whether a single consumer receives all the messages of the queue
+	Exclusive *bool `json:"exclusive,omitempty"`
+	// The property of the messages that identifies the last value
+	LastValueKey string `json:"lastValueKey,omitempty"`
+	// Whether the consumers of the queue do not remove the messages
+	NonDestructive *bool `json:"nonDestructive,omitempty"`
+	// Whether the messages of the queue are removed when it has no consumers
+	PurgeOnNoConsumers *bool `json:"purgeOnNoConsumers,omitempty"`
+	// The number of consumers the queue waits for before dispatching messages
+	ConsumersBeforeDispatch *int32 `json:"consumersBeforeDispatch,omitempty"`
+	// The milliseconds the queue waits for the consumers before dispatching messages
+	DelayBeforeDispatch *int64 `json:"delayBeforeDispatch,omitempty"`
+	// The maximum number of messages of a ring queue
+	RingSize *int64 `json:"ringSize,omitempty"`
+}
+
+// MatchedBrokerStatus is the state of a resource rendered into the broker properties of a matched Broker
+type MatchedBrokerStatus struct {
+	// The name of the Broker
+	Name string `json:"name"`
+	// Whether the broker properties of the Broker with the resource are applied by all its brokers
+	Applied bool `json:"applied"`
+	// Why the resource is not applied yet
+	Message string `json:"message,omitempty"`
+}
+
+type BrokerAddressStatus struct {
+
+	// Current state of the resource
+	// Conditions represent the latest available observations of an object's state
+	//+optional
+	//+patchMergeKey=type
+	//+patchStrategy=merge
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
+
+	// The state of the address on each matched Broker
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Brokers"
+	Brokers []MatchedBrokerStatus `json:"brokers,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=brokeraddresses,shortName=badd
+//+kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.spec.address`
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// An address and its queues configured with broker properties on the Brokers matched by a label selector
+// +operator-sdk:csv:customresourcedefinitions:displayName="Broker Address"
+type BrokerAddress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BrokerAddressSpec   `json:"spec,omitempty"`
+	Status BrokerAddressStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type BrokerAddressList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BrokerAddress `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BrokerAddress{}, &BrokerAddressList{})
+}
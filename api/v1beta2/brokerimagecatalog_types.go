@@ -0,0 +1,111 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type BrokerImageCatalogSpec struct {
+	// The broker versions with their images, a version that the operator supports uses the images of the catalog
+	// in place of the images of the operator
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Versions"
+	Versions []BrokerImageCatalogVersion `json:"versions,omitempty"`
+
+	// The registries that replace the registry of the broker and init images, for clusters without access to the
+	// public registries
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mirrors"
+	Mirrors []RegistryMirror `json:"mirrors,omitempty"`
+}
+
+type BrokerImageCatalogVersion struct {
+	// The full broker version, like 2.53.1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Version"
+	Version string `json:"version"`
+
+	// The broker image of the version
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image"
+	Image string `json:"image"`
+
+	// The digest of the broker image, like sha256:<hex>, the image is pulled by digest when set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image Digest"
+	ImageDigest string `json:"imageDigest,omitempty"`
+
+	// The init image of the version, the init image of the operator for the version is used when empty
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Init Image"
+	InitImage string `json:"initImage,omitempty"`
+
+	// The digest of the init image, like sha256:<hex>, the image is pulled by digest when set
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Init Image Digest"
+	InitImageDigest string `json:"initImageDigest,omitempty"`
+}
+
+type RegistryMirror struct {
+	// The registry or repository prefix of the images to replace, like quay.io/arkmq-org
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Source"
+	Source string `json:"source"`
+
+	// The registry or repository prefix that replaces the source, like registry.example.com/arkmq-org
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Mirror"
+	Mirror string `json:"mirror"`
+}
+
+type BrokerImageCatalogStatus struct {
+	// Current state of the catalog
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// The versions of the catalog that the brokers can use
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Versions"
+	Versions []string `json:"versions,omitempty"`
+}
+
+const (
+	BrokerImageCatalogValidConditionInvalidVersionReason = "InvalidVersion"
+	BrokerImageCatalogValidConditionInvalidImageReason   = "InvalidImage"
+	BrokerImageCatalogValidConditionInvalidMirrorReason  = "InvalidMirror"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=brokerimagecatalogs,scope=Cluster,shortName=bic
+//+kubebuilder:printcolumn:name="Valid",type=string,JSONPath=`.status.conditions[?(@.type=="Valid")].status`
+//+kubebuilder:printcolumn:name="Versions",type=string,JSONPath=`.status.versions`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// A cluster wide catalog of broker versions and images that the operator resolves the version of a Broker with
+// +operator-sdk:csv:customresourcedefinitions:displayName="Broker Image Catalog"
+type BrokerImageCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BrokerImageCatalogSpec   `json:"spec,omitempty"`
+	Status BrokerImageCatalogStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type BrokerImageCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BrokerImageCatalog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BrokerImageCatalog{}, &BrokerImageCatalogList{})
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=purge;pause;resume;retryDLQ;moveMessages;closeConnections
+type BrokerOperationName string
+
+const (
+	BrokerOperationPurge            BrokerOperationName = "purge"
+	BrokerOperationPause            BrokerOperationName = "pause"
+	BrokerOperationResume           BrokerOperationName = "resume"
+	BrokerOperationRetryDLQ         BrokerOperationName = "retryDLQ"
+	BrokerOperationMoveMessages     BrokerOperationName = "moveMessages"
+	BrokerOperationCloseConnections BrokerOperationName = "closeConnections"
+)
+
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="a BrokerOperation is executed once, its spec can not be changed"
+type BrokerOperationSpec struct {
+	// The Broker CR in the same namespace the operation is executed on
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker"
+	Broker corev1.LocalObjectReference `json:"broker"`
+
+	// The ordinals of the brokers the operation is executed on, all the brokers when empty
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ordinals"
+	Ordinals []int32 `json:"ordinals,omitempty"`
+
+	// The operation to execute, one of purge, pause, resume, retryDLQ, moveMessages or closeConnections
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Operation"
+	Operation BrokerOperationName `json:"operation"`
+
+	// The queue of purge, pause, resume, moveMessages and the dead letter queue of retryDLQ
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Queue"
+	Queue string `json:"queue,omitempty"`
+
+	// The address of pause and resume, all the queues of the address are paused
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Address"
+	Address string `json:"address,omitempty"`
+
+	// The filter that selects the messages of moveMessages, all the messages when empty
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Filter"
+	Filter string `json:"filter,omitempty"`
+
+	// The queue that receives the messages of moveMessages
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Target Queue"
+	TargetQueue string `json:"targetQueue,omitempty"`
+
+	// The user whose connections are closed by closeConnections
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="User"
+	User string `json:"user,omitempty"`
+}
+
+type BrokerOperationResult struct {
+	// The ordinal of the broker
+	Ordinal int32 `json:"ordinal"`
+	// The pod of the broker
+	Pod string `json:"pod"`
+	// Whether the operation succeeded on the broker
+	Succeeded bool `json:"succeeded"`
+	// The number of messages affected by purge, retryDLQ and moveMessages
+	Count *int64 `json:"count,omitempty"`
+	// The outcome of the operation
+	Message string `json:"message,omitempty"`
+	// When the operation was executed on the broker
+	Time metav1.Time `json:"time"`
+	// The operation is claimed for the broker and its outcome is not recorded yet, it is never sent twice
+	InProgress bool `json:"inProgress,omitempty"`
+}
+
+type BrokerOperationStatus struct {
+
+	// Current state of the resource
+	// Conditions represent the latest available observations of an object's state
+	//+optional
+	//+patchMergeKey=type
+	//+patchStrategy=merge
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
+
+	// When the operation was first executed
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Start Time"
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// When the operation was executed on all the target brokers or was rejected
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Completion Time"
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// The result of the operation on each target broker
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Results"
+	Results []BrokerOperationResult `json:"results,omitempty"`
+}
+
+const (
+	BrokerOperationCompletedConditionType           = "Completed"
+	BrokerOperationCompletedConditionSucceeded      = "Succeeded"
+	BrokerOperationCompletedConditionFailed         = "Failed"
+	BrokerOperationCompletedConditionInvalid        = "Invalid"
+	BrokerOperationCompletedConditionPending        = "Pending"
+	BrokerOperationCompletedConditionBrokerNotFound = "BrokerNotFound"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=brokeroperations,shortName=bop
+//+kubebuilder:printcolumn:name="Broker",type=string,JSONPath=`.spec.broker.name`
+//+kubebuilder:printcolumn:name="Operation",type=string,JSONPath=`.spec.operation`
+//+kubebuilder:printcolumn:name="Completed",type=string,JSONPath=`.status.conditions[?(@.type=="Completed")].reason`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// A one shot management operation on the queues or connections of a Broker, the result on each broker is recorded in the status
+// +operator-sdk:csv:customresourcedefinitions:displayName="Broker Operation"
+type BrokerOperation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BrokerOperationSpec   `json:"spec,omitempty"`
+	Status BrokerOperationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type BrokerOperationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BrokerOperation `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BrokerOperation{}, &BrokerOperationList{})
+}
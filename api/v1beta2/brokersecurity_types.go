@@ -0,0 +1,92 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum=send;consume;createAddress;deleteAddress;createDurableQueue;deleteDurableQueue;createNonDurableQueue;deleteNonDurableQueue;manage;browse;view;edit
+type SecurityOperation string
+
+type BrokerSecuritySpec struct {
+	// The label selector of the Brokers in the same namespace the security settings are configured on, all the Brokers when empty
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Broker Selector"
+	BrokerSelector *metav1.LabelSelector `json:"brokerSelector,omitempty"`
+
+	// The role based access control of the addresses and of the management operations
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Security Settings"
+	SecuritySettings []BrokerSecuritySettingType `json:"securitySettings,omitempty"`
+}
+
+type BrokerSecuritySettingType struct {
+	// The address match pattern, or a management match pattern starting with mops.
+	Match string `json:"match"`
+	// The roles granted each operation
+	Permissions []BrokerPermissionType `json:"permissions,omitempty"`
+}
+
+type BrokerPermissionType struct {
+	// The operation granted to the roles
+	Operation SecurityOperation `json:"operation"`
+	// The roles granted the operation
+	Roles []string `json:"roles"`
+}
+
+type BrokerSecurityStatus struct {
+
+	// Current state of the resource
+	// Conditions represent the latest available observations of an object's state
+	//+optional
+	//+patchMergeKey=type
+	//+patchStrategy=merge
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Conditions",xDescriptors="urn:alm:descriptor:io.kubernetes.conditions"
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,2,rep,name=conditions"`
+
+	// The state of the security settings on each matched Broker
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Brokers"
+	Brokers []MatchedBrokerStatus `json:"brokers,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:storageversion
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=brokersecurities,shortName=bsec
+//+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Security settings configured with broker properties on the Brokers matched by a label selector
+// +operator-sdk:csv:customresourcedefinitions:displayName="Broker Security"
+type BrokerSecurity struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BrokerSecuritySpec   `json:"spec,omitempty"`
+	Status BrokerSecurityStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type BrokerSecurityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BrokerSecurity `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BrokerSecurity{}, &BrokerSecurityList{})
+}
@@ -35,6 +35,11 @@ func (in *AcceptorType) DeepCopyInto(out *AcceptorType) {
 		*out = new(ExposeMode)
 		**out = **in
 	}
+	if in.ExposeService != nil {
+		in, out := &in.ExposeService, &out.ExposeService
+		*out = new(ExposeServiceType)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SupportAdvisory != nil {
 		in, out := &in.SupportAdvisory, &out.SupportAdvisory
 		*out = new(bool)
@@ -519,6 +524,60 @@ func (in *AppCapabilityType) DeepCopy() *AppCapabilityType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingStatus) DeepCopyInto(out *AutoscalingStatus) {
+	*out = *in
+	if in.LastScaleTime != nil {
+		in, out := &in.LastScaleTime, &out.LastScaleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingStatus.
+func (in *AutoscalingStatus) DeepCopy() *AutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingType) DeepCopyInto(out *AutoscalingType) {
+	*out = *in
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleUpCooldownSeconds != nil {
+		in, out := &in.ScaleUpCooldownSeconds, &out.ScaleUpCooldownSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ScaleDownCooldownSeconds != nil {
+		in, out := &in.ScaleDownCooldownSeconds, &out.ScaleDownCooldownSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PollIntervalSeconds != nil {
+		in, out := &in.PollIntervalSeconds, &out.PollIntervalSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingType.
+func (in *AutoscalingType) DeepCopy() *AutoscalingType {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Broker) DeepCopyInto(out *Broker) {
 	*out = *in
@@ -546,6 +605,124 @@ func (in *Broker) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerAddress) DeepCopyInto(out *BrokerAddress) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerAddress.
+func (in *BrokerAddress) DeepCopy() *BrokerAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerAddress) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerAddressList) DeepCopyInto(out *BrokerAddressList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BrokerAddress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerAddressList.
+func (in *BrokerAddressList) DeepCopy() *BrokerAddressList {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerAddressList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerAddressList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerAddressSpec) DeepCopyInto(out *BrokerAddressSpec) {
+	*out = *in
+	if in.BrokerSelector != nil {
+		in, out := &in.BrokerSelector, &out.BrokerSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RoutingTypes != nil {
+		in, out := &in.RoutingTypes, &out.RoutingTypes
+		*out = make([]RoutingType, len(*in))
+		copy(*out, *in)
+	}
+	if in.Queues != nil {
+		in, out := &in.Queues, &out.Queues
+		*out = make([]BrokerQueueType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerAddressSpec.
+func (in *BrokerAddressSpec) DeepCopy() *BrokerAddressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerAddressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerAddressStatus) DeepCopyInto(out *BrokerAddressStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]MatchedBrokerStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerAddressStatus.
+func (in *BrokerAddressStatus) DeepCopy() *BrokerAddressStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerAddressStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BrokerApp) DeepCopyInto(out *BrokerApp) {
 	*out = *in
@@ -662,39 +839,127 @@ func (in *BrokerAppStatus) DeepCopy() *BrokerAppStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BrokerList) DeepCopyInto(out *BrokerList) {
+func (in *BrokerConnectionFederationType) DeepCopyInto(out *BrokerConnectionFederationType) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Broker, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Queues != nil {
+		in, out := &in.Queues, &out.Queues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerList.
-func (in *BrokerList) DeepCopy() *BrokerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerConnectionFederationType.
+func (in *BrokerConnectionFederationType) DeepCopy() *BrokerConnectionFederationType {
 	if in == nil {
 		return nil
 	}
-	out := new(BrokerList)
+	out := new(BrokerConnectionFederationType)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *BrokerList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerConnectionMirrorType) DeepCopyInto(out *BrokerConnectionMirrorType) {
+	*out = *in
+	if in.MessageAcknowledgements != nil {
+		in, out := &in.MessageAcknowledgements, &out.MessageAcknowledgements
+		*out = new(bool)
+		**out = **in
 	}
-	return nil
+	if in.QueueCreation != nil {
+		in, out := &in.QueueCreation, &out.QueueCreation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.QueueRemoval != nil {
+		in, out := &in.QueueRemoval, &out.QueueRemoval
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerConnectionMirrorType.
+func (in *BrokerConnectionMirrorType) DeepCopy() *BrokerConnectionMirrorType {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerConnectionMirrorType)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BrokerService) DeepCopyInto(out *BrokerService) {
+func (in *BrokerConnectionStatus) DeepCopyInto(out *BrokerConnectionStatus) {
+	*out = *in
+	if in.Connected != nil {
+		in, out := &in.Connected, &out.Connected
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Disconnected != nil {
+		in, out := &in.Disconnected, &out.Disconnected
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerConnectionStatus.
+func (in *BrokerConnectionStatus) DeepCopy() *BrokerConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerConnectionType) DeepCopyInto(out *BrokerConnectionType) {
+	*out = *in
+	if in.BrokerRef != nil {
+		in, out := &in.BrokerRef, &out.BrokerRef
+		*out = new(BrokerReference)
+		**out = **in
+	}
+	if in.RetryInterval != nil {
+		in, out := &in.RetryInterval, &out.RetryInterval
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReconnectAttempts != nil {
+		in, out := &in.ReconnectAttempts, &out.ReconnectAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(BrokerConnectionMirrorType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Federation != nil {
+		in, out := &in.Federation, &out.Federation
+		*out = new(BrokerConnectionFederationType)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerConnectionType.
+func (in *BrokerConnectionType) DeepCopy() *BrokerConnectionType {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerConnectionType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerImageCatalog) DeepCopyInto(out *BrokerImageCatalog) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -702,18 +967,18 @@ func (in *BrokerService) DeepCopyInto(out *BrokerService) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerService.
-func (in *BrokerService) DeepCopy() *BrokerService {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerImageCatalog.
+func (in *BrokerImageCatalog) DeepCopy() *BrokerImageCatalog {
 	if in == nil {
 		return nil
 	}
-	out := new(BrokerService)
+	out := new(BrokerImageCatalog)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *BrokerService) DeepCopyObject() runtime.Object {
+func (in *BrokerImageCatalog) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -721,31 +986,31 @@ func (in *BrokerService) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BrokerServiceList) DeepCopyInto(out *BrokerServiceList) {
+func (in *BrokerImageCatalogList) DeepCopyInto(out *BrokerImageCatalogList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]BrokerService, len(*in))
+		*out = make([]BrokerImageCatalog, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerServiceList.
-func (in *BrokerServiceList) DeepCopy() *BrokerServiceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerImageCatalogList.
+func (in *BrokerImageCatalogList) DeepCopy() *BrokerImageCatalogList {
 	if in == nil {
 		return nil
 	}
-	out := new(BrokerServiceList)
+	out := new(BrokerImageCatalogList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *BrokerServiceList) DeepCopyObject() runtime.Object {
+func (in *BrokerImageCatalogList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -753,35 +1018,32 @@ func (in *BrokerServiceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BrokerServiceSpec) DeepCopyInto(out *BrokerServiceSpec) {
+func (in *BrokerImageCatalogSpec) DeepCopyInto(out *BrokerImageCatalogSpec) {
 	*out = *in
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Env != nil {
-		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]BrokerImageCatalogVersion, len(*in))
+		copy(*out, *in)
 	}
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(string)
-		**out = **in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]RegistryMirror, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerServiceSpec.
-func (in *BrokerServiceSpec) DeepCopy() *BrokerServiceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerImageCatalogSpec.
+func (in *BrokerImageCatalogSpec) DeepCopy() *BrokerImageCatalogSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(BrokerServiceSpec)
+	out := new(BrokerImageCatalogSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BrokerServiceStatus) DeepCopyInto(out *BrokerServiceStatus) {
+func (in *BrokerImageCatalogStatus) DeepCopyInto(out *BrokerImageCatalogStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -790,39 +1052,563 @@ func (in *BrokerServiceStatus) DeepCopyInto(out *BrokerServiceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.ProvisionedApps != nil {
-		in, out := &in.ProvisionedApps, &out.ProvisionedApps
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerServiceStatus.
-func (in *BrokerServiceStatus) DeepCopy() *BrokerServiceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerImageCatalogStatus.
+func (in *BrokerImageCatalogStatus) DeepCopy() *BrokerImageCatalogStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(BrokerServiceStatus)
+	out := new(BrokerImageCatalogStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BrokerSpec) DeepCopyInto(out *BrokerSpec) {
+func (in *BrokerImageCatalogVersion) DeepCopyInto(out *BrokerImageCatalogVersion) {
 	*out = *in
-	in.DeploymentPlan.DeepCopyInto(&out.DeploymentPlan)
-	if in.Acceptors != nil {
-		in, out := &in.Acceptors, &out.Acceptors
-		*out = make([]AcceptorType, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerImageCatalogVersion.
+func (in *BrokerImageCatalogVersion) DeepCopy() *BrokerImageCatalogVersion {
+	if in == nil {
+		return nil
 	}
-	if in.Connectors != nil {
-		in, out := &in.Connectors, &out.Connectors
-		*out = make([]ConnectorType, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	out := new(BrokerImageCatalogVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerList) DeepCopyInto(out *BrokerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Broker, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerList.
+func (in *BrokerList) DeepCopy() *BrokerList {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerOperation) DeepCopyInto(out *BrokerOperation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerOperation.
+func (in *BrokerOperation) DeepCopy() *BrokerOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerOperation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerOperationList) DeepCopyInto(out *BrokerOperationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BrokerOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerOperationList.
+func (in *BrokerOperationList) DeepCopy() *BrokerOperationList {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerOperationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerOperationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerOperationResult) DeepCopyInto(out *BrokerOperationResult) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int64)
+		**out = **in
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerOperationResult.
+func (in *BrokerOperationResult) DeepCopy() *BrokerOperationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerOperationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerOperationSpec) DeepCopyInto(out *BrokerOperationSpec) {
+	*out = *in
+	out.Broker = in.Broker
+	if in.Ordinals != nil {
+		in, out := &in.Ordinals, &out.Ordinals
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerOperationSpec.
+func (in *BrokerOperationSpec) DeepCopy() *BrokerOperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerOperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerOperationStatus) DeepCopyInto(out *BrokerOperationStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]BrokerOperationResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerOperationStatus.
+func (in *BrokerOperationStatus) DeepCopy() *BrokerOperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerOperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerPermissionType) DeepCopyInto(out *BrokerPermissionType) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerPermissionType.
+func (in *BrokerPermissionType) DeepCopy() *BrokerPermissionType {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerPermissionType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerQueueType) DeepCopyInto(out *BrokerQueueType) {
+	*out = *in
+	if in.Durable != nil {
+		in, out := &in.Durable, &out.Durable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxConsumers != nil {
+		in, out := &in.MaxConsumers, &out.MaxConsumers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Exclusive != nil {
+		in, out := &in.Exclusive, &out.Exclusive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NonDestructive != nil {
+		in, out := &in.NonDestructive, &out.NonDestructive
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PurgeOnNoConsumers != nil {
+		in, out := &in.PurgeOnNoConsumers, &out.PurgeOnNoConsumers
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConsumersBeforeDispatch != nil {
+		in, out := &in.ConsumersBeforeDispatch, &out.ConsumersBeforeDispatch
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DelayBeforeDispatch != nil {
+		in, out := &in.DelayBeforeDispatch, &out.DelayBeforeDispatch
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RingSize != nil {
+		in, out := &in.RingSize, &out.RingSize
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerQueueType.
+func (in *BrokerQueueType) DeepCopy() *BrokerQueueType {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerQueueType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerReference) DeepCopyInto(out *BrokerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerReference.
+func (in *BrokerReference) DeepCopy() *BrokerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerSecurity) DeepCopyInto(out *BrokerSecurity) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSecurity.
+func (in *BrokerSecurity) DeepCopy() *BrokerSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerSecurity) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerSecurityList) DeepCopyInto(out *BrokerSecurityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BrokerSecurity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSecurityList.
+func (in *BrokerSecurityList) DeepCopy() *BrokerSecurityList {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerSecurityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerSecurityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerSecuritySettingType) DeepCopyInto(out *BrokerSecuritySettingType) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make([]BrokerPermissionType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSecuritySettingType.
+func (in *BrokerSecuritySettingType) DeepCopy() *BrokerSecuritySettingType {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerSecuritySettingType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerSecuritySpec) DeepCopyInto(out *BrokerSecuritySpec) {
+	*out = *in
+	if in.BrokerSelector != nil {
+		in, out := &in.BrokerSelector, &out.BrokerSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecuritySettings != nil {
+		in, out := &in.SecuritySettings, &out.SecuritySettings
+		*out = make([]BrokerSecuritySettingType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSecuritySpec.
+func (in *BrokerSecuritySpec) DeepCopy() *BrokerSecuritySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerSecuritySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerSecurityStatus) DeepCopyInto(out *BrokerSecurityStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
+		*out = make([]MatchedBrokerStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSecurityStatus.
+func (in *BrokerSecurityStatus) DeepCopy() *BrokerSecurityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerSecurityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerService) DeepCopyInto(out *BrokerService) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerService.
+func (in *BrokerService) DeepCopy() *BrokerService {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerService) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerServiceList) DeepCopyInto(out *BrokerServiceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BrokerService, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerServiceList.
+func (in *BrokerServiceList) DeepCopy() *BrokerServiceList {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerServiceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerServiceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerServiceSpec) DeepCopyInto(out *BrokerServiceSpec) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerServiceSpec.
+func (in *BrokerServiceSpec) DeepCopy() *BrokerServiceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerServiceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerServiceStatus) DeepCopyInto(out *BrokerServiceStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProvisionedApps != nil {
+		in, out := &in.ProvisionedApps, &out.ProvisionedApps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerServiceStatus.
+func (in *BrokerServiceStatus) DeepCopy() *BrokerServiceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerServiceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerSpec) DeepCopyInto(out *BrokerSpec) {
+	*out = *in
+	in.DeploymentPlan.DeepCopyInto(&out.DeploymentPlan)
+	if in.Acceptors != nil {
+		in, out := &in.Acceptors, &out.Acceptors
+		*out = make([]AcceptorType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Connectors != nil {
+		in, out := &in.Connectors, &out.Connectors
+		*out = make([]ConnectorType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	in.Console.DeepCopyInto(&out.Console)
@@ -840,6 +1626,11 @@ func (in *BrokerSpec) DeepCopyInto(out *BrokerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayType)
+		**out = **in
+	}
 	if in.ResourceTemplates != nil {
 		in, out := &in.ResourceTemplates, &out.ResourceTemplates
 		*out = make([]ResourceTemplate, len(*in))
@@ -852,6 +1643,38 @@ func (in *BrokerSpec) DeepCopyInto(out *BrokerSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.HA != nil {
+		in, out := &in.HA, &out.HA
+		*out = new(HAType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BrokerConnections != nil {
+		in, out := &in.BrokerConnections, &out.BrokerConnections
+		*out = make([]BrokerConnectionType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdatePolicy != nil {
+		in, out := &in.UpdatePolicy, &out.UpdatePolicy
+		*out = new(UpdatePolicyType)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerSpec.
@@ -881,7 +1704,29 @@ func (in *BrokerStatus) DeepCopyInto(out *BrokerStatus) {
 		copy(*out, *in)
 	}
 	out.Version = in.Version
-	out.Upgrade = in.Upgrade
+	in.Upgrade.DeepCopyInto(&out.Upgrade)
+	if in.BrokerConnections != nil {
+		in, out := &in.BrokerConnections, &out.BrokerConnections
+		*out = make([]BrokerConnectionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(PlanStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalAddresses != nil {
+		in, out := &in.ExternalAddresses, &out.ExternalAddresses
+		*out = make([]ExternalAddressStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerStatus.
@@ -1095,6 +1940,53 @@ func (in *DeploymentPlanType) DeepCopy() *DeploymentPlanType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExposeServiceType) DeepCopyInto(out *ExposeServiceType) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodePorts != nil {
+		in, out := &in.NodePorts, &out.NodePorts
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExposeServiceType.
+func (in *ExposeServiceType) DeepCopy() *ExposeServiceType {
+	if in == nil {
+		return nil
+	}
+	out := new(ExposeServiceType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAddressStatus) DeepCopyInto(out *ExternalAddressStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAddressStatus.
+func (in *ExternalAddressStatus) DeepCopy() *ExternalAddressStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAddressStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalConfigStatus) DeepCopyInto(out *ExternalConfigStatus) {
 	*out = *in
@@ -1135,6 +2027,76 @@ func (in *ExtraMountsType) DeepCopy() *ExtraMountsType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayType) DeepCopyInto(out *GatewayType) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayType.
+func (in *GatewayType) DeepCopy() *GatewayType {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HAType) DeepCopyInto(out *HAType) {
+	*out = *in
+	if in.AllowFailBack != nil {
+		in, out := &in.AllowFailBack, &out.AllowFailBack
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HAType.
+func (in *HAType) DeepCopy() *HAType {
+	if in == nil {
+		return nil
+	}
+	out := new(HAType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowType) DeepCopyInto(out *MaintenanceWindowType) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]MaintenanceWindowDay, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowType.
+func (in *MaintenanceWindowType) DeepCopy() *MaintenanceWindowType {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchedBrokerStatus) DeepCopyInto(out *MatchedBrokerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchedBrokerStatus.
+func (in *MatchedBrokerStatus) DeepCopy() *MatchedBrokerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchedBrokerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectMeta) DeepCopyInto(out *ObjectMeta) {
 	*out = *in
@@ -1164,6 +2126,48 @@ func (in *ObjectMeta) DeepCopy() *ObjectMeta {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlanStatus) DeepCopyInto(out *PlanStatus) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]PlannedResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanStatus.
+func (in *PlanStatus) DeepCopy() *PlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedResource) DeepCopyInto(out *PlannedResource) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedResource.
+func (in *PlannedResource) DeepCopy() *PlannedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodSecurityType) DeepCopyInto(out *PodSecurityType) {
 	*out = *in
@@ -1189,6 +2193,21 @@ func (in *PodSecurityType) DeepCopy() *PodSecurityType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
 	*out = *in
@@ -1257,6 +2276,45 @@ func (in *ResourceTemplate) DeepCopy() *ResourceTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.LastProgressTime != nil {
+		in, out := &in.LastProgressTime, &out.LastProgressTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutType) DeepCopyInto(out *RolloutType) {
+	*out = *in
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutType.
+func (in *RolloutType) DeepCopy() *RolloutType {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageType) DeepCopyInto(out *StorageType) {
 	*out = *in
@@ -1272,9 +2330,34 @@ func (in *StorageType) DeepCopy() *StorageType {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdatePolicyType) DeepCopyInto(out *UpdatePolicyType) {
+	*out = *in
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(MaintenanceWindowType)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdatePolicyType.
+func (in *UpdatePolicyType) DeepCopy() *UpdatePolicyType {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdatePolicyType)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
 	*out = *in
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
@@ -360,7 +360,7 @@ var _ = Describe("BrokerProperties Address tests", func() {
 
 				"# addresses and queues",
 				"addressConfigurations.myAddress0.routingTypes=ANYCAST",
-				"addressConfigurations.myAddress0.queueConfigs.myQueue0.rouingType=ANYCAST",
+				"addressConfigurations.myAddress0.queueConfigs.myQueue0.routingType=ANYCAST",
 				"addressConfigurations.myAddress0.queueConfigs.myQueue0.address=myAddress0",
 			}
 			if os.Getenv("USE_EXISTING_CLUSTER") == "true" {
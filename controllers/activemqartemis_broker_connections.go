@@ -0,0 +1,382 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/namer"
+)
+
+const (
+	brokerConnectionsPropertiesName   = "broker-connections" + PropertiesSuffix
+	brokerConnectionsPemCfgKey        = UncheckedPrefix + "broker-connections.pemcfg"
+	brokerConnectionDefaultPort       = 61616
+	brokerConnectionDefaultRetry      = 5000
+	brokerConnectionDefaultReconnects = -1
+	brokerConnectionUserKey           = "user"
+	brokerConnectionPasswordKey       = "password"
+)
+
+var brokerConnectionNameRegex = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+
+func validateBrokerConnections(customResource *v1beta2.Broker, client rtclient.Client) (*metav1.Condition, bool) {
+	names := map[string]bool{}
+	for _, bc := range customResource.Spec.BrokerConnections {
+		var message string
+		switch {
+		case !brokerConnectionNameRegex.MatchString(bc.Name):
+			message = fmt.Sprintf(".Spec.BrokerConnections name %q is invalid, it must only contain letters, digits, '-' or '_'", bc.Name)
+		case names[bc.Name]:
+			message = fmt.Sprintf(".Spec.BrokerConnections name %q is duplicated", bc.Name)
+		case (bc.BrokerRef == nil) == (bc.URI == ""):
+			message = fmt.Sprintf(".Spec.BrokerConnections %q requires exactly one of BrokerRef or URI", bc.Name)
+		case bc.Mirror == nil && bc.Federation == nil:
+			message = fmt.Sprintf(".Spec.BrokerConnections %q requires a Mirror or a Federation", bc.Name)
+		case bc.Federation != nil && len(bc.Federation.Addresses) == 0 && len(bc.Federation.Queues) == 0:
+			message = fmt.Sprintf(".Spec.BrokerConnections %q Federation requires Addresses or Queues", bc.Name)
+		}
+		if message != "" {
+			return &metav1.Condition{
+				Type:    v1beta2.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  v1beta2.ValidConditionFailedInvalidBrokerConnection,
+				Message: message,
+			}, false
+		}
+		names[bc.Name] = true
+
+		retry := true
+		if bc.BrokerRef != nil {
+			target := &v1beta2.Broker{}
+			if err := client.Get(context.TODO(), brokerConnectionTargetName(customResource, &bc), target); err != nil {
+				return &metav1.Condition{
+					Type:    v1beta2.ValidConditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  v1beta2.ValidConditionMissingResourcesReason,
+					Message: fmt.Sprintf(".Spec.BrokerConnections %q failed to locate the target Broker, %v", bc.Name, err),
+				}, retry
+			}
+		}
+		if bc.CredentialsSecret != "" {
+			secret, err := common.GetNamespacedSecret(client, bc.CredentialsSecret, customResource.Namespace)
+			if err != nil {
+				return &metav1.Condition{
+					Type:    v1beta2.ValidConditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  v1beta2.ValidConditionMissingResourcesReason,
+					Message: fmt.Sprintf(".Spec.BrokerConnections %q failed to locate the credentials secret, %v", bc.Name, err),
+				}, retry
+			}
+			missingKeys := []string{}
+			for _, key := range []string{brokerConnectionUserKey, brokerConnectionPasswordKey} {
+				if _, found := secret.Data[key]; !found {
+					missingKeys = append(missingKeys, key)
+				}
+			}
+			if len(missingKeys) > 0 {
+				return &metav1.Condition{
+					Type:    v1beta2.ValidConditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  v1beta2.ValidConditionMissingResourcesReason,
+					Message: fmt.Sprintf(".Spec.BrokerConnections %q credentials secret %s is missing the keys %s", bc.Name, bc.CredentialsSecret, strings.Join(missingKeys, ", ")),
+				}, retry
+			}
+		}
+		if bc.MTLS {
+			if _, err := common.GetOperatorCASecret(client); err != nil {
+				return &metav1.Condition{
+					Type:    v1beta2.ValidConditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  v1beta2.ValidConditionMissingResourcesReason,
+					Message: fmt.Sprintf(".Spec.BrokerConnections %q uses mtls but operator failed to locate necessary operator ca secret, %v", bc.Name, err),
+				}, retry
+			}
+			operandCertSecretName := common.GetOperandCertSecretName(customResource, client)
+			if _, err := common.GetNamespacedSecret(client, operandCertSecretName, customResource.Namespace); err != nil {
+				return &metav1.Condition{
+					Type:    v1beta2.ValidConditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  v1beta2.ValidConditionMissingResourcesReason,
+					Message: fmt.Sprintf(".Spec.BrokerConnections %q uses mtls but operator failed to locate necessary operand cert secret, %v", bc.Name, err),
+				}, retry
+			}
+		}
+	}
+	return nil, false
+}
+
+func brokerConnectionTargetName(customResource *v1beta2.Broker, bc *v1beta2.BrokerConnectionType) types.NamespacedName {
+	target := types.NamespacedName{Name: bc.BrokerRef.Name, Namespace: bc.BrokerRef.Namespace}
+	if target.Namespace == "" {
+		target.Namespace = customResource.Namespace
+	}
+	return target
+}
+
+func hasMTLSBrokerConnection(customResource *v1beta2.Broker) bool {
+	for _, bc := range customResource.Spec.BrokerConnections {
+		if bc.MTLS {
+			return true
+		}
+	}
+	return false
+}
+
+// credentials are passed as env vars from the secret and substituted by the broker when it loads the properties
+func brokerConnectionEnvVarName(connectionName string, key string) string {
+	sanitized := strings.ToUpper(strings.ReplaceAll(connectionName, "-", "_"))
+	return fmt.Sprintf("BROKER_CONNECTION_%s_%s", sanitized, strings.ToUpper(key))
+}
+
+func MakeEnvVarArrayForBrokerConnections(customResource *v1beta2.Broker) []corev1.EnvVar {
+	envVars := []corev1.EnvVar{}
+	for _, bc := range customResource.Spec.BrokerConnections {
+		if bc.CredentialsSecret == "" {
+			continue
+		}
+		for _, key := range []string{brokerConnectionUserKey, brokerConnectionPasswordKey} {
+			envVars = append(envVars, corev1.EnvVar{
+				Name: brokerConnectionEnvVarName(bc.Name, key),
+				ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: bc.CredentialsSecret},
+						Key:                  key,
+					},
+				},
+			})
+		}
+	}
+	return envVars
+}
+
+// ProcessBrokerConnectionsProperties adds the AMQPConnections configuration of the broker connections
+func (r *ActiveMQArtemisReconcilerImpl) ProcessBrokerConnectionsProperties(m map[string][]byte, client rtclient.Client) error {
+	if len(r.customResource.Spec.BrokerConnections) == 0 {
+		return nil
+	}
+
+	tlsParams := ""
+	if hasMTLSBrokerConnection(r.customResource) {
+		caSecret, err := common.GetOperatorCASecret(client)
+		if err != nil {
+			return fmt.Errorf("failed to get operator ca secret, %w", err)
+		}
+		caSecretKey, err := common.GetOperatorCASecretKey(client, caSecret)
+		if err != nil {
+			return fmt.Errorf("failed to get operator ca secret key, %w", err)
+		}
+		operandCertSecretName := common.GetOperandCertSecretName(r.customResource, client)
+
+		pemCfg := NewPropsWithHeader()
+		fmt.Fprintf(pemCfg, "source.cert=%s%s/tls.crt\n", common.SecretPathBase, operandCertSecretName)
+		fmt.Fprintf(pemCfg, "source.key=%s%s/tls.key\n", common.SecretPathBase, operandCertSecretName)
+		m[brokerConnectionsPemCfgKey] = pemCfg.Bytes()
+
+		tlsParams = fmt.Sprintf("?sslEnabled=true;keyStoreType=PEMCFG;keyStorePath=%s%s/%s;trustStoreType=PEMCA;trustStorePath=%s%s/%s",
+			common.SecretPathBase, getPropertiesResourceNsName(r.customResource).Name, brokerConnectionsPemCfgKey,
+			common.SecretPathBase, caSecret.Name, caSecretKey)
+	}
+
+	buf := NewPropsWithHeader()
+	for _, bc := range r.customResource.Spec.BrokerConnections {
+		params := ""
+		if bc.MTLS {
+			params = tlsParams
+		}
+		uri, err := brokerConnectionURI(r.customResource, &bc, params, client)
+		if err != nil {
+			return err
+		}
+		for _, property := range brokerConnectionProperties(&bc, uri) {
+			fmt.Fprintln(buf, property)
+		}
+	}
+	m[brokerConnectionsPropertiesName] = buf.Bytes()
+	return nil
+}
+
+func brokerConnectionURI(customResource *v1beta2.Broker, bc *v1beta2.BrokerConnectionType, params string, client rtclient.Client) (string, error) {
+	if bc.BrokerRef == nil {
+		if params != "" && strings.Contains(bc.URI, "?") {
+			params = ";" + strings.TrimPrefix(params, "?")
+		}
+		return bc.URI + params, nil
+	}
+
+	targetName := brokerConnectionTargetName(customResource, bc)
+	target := &v1beta2.Broker{}
+	if err := client.Get(context.TODO(), targetName, target); err != nil {
+		return "", fmt.Errorf("failed to get target Broker %s of broker connection %s, %w", targetName, bc.Name, err)
+	}
+
+	port := bc.Port
+	if port == 0 {
+		port = brokerConnectionDefaultPort
+	}
+	size := common.GetDeploymentSize(target)
+	if size < 1 {
+		size = 1
+	}
+
+	// a failover list of each target broker, the connection moves to the next when one is not reachable
+	uris := make([]string, 0, size)
+	for i := int32(0); i < size; i++ {
+		uris = append(uris, fmt.Sprintf("tcp://%s:%d%s", common.OrdinalFQDNS(target.Name, target.Namespace, i), port, params))
+	}
+	return strings.Join(uris, "#"), nil
+}
+
+func brokerConnectionProperties(bc *v1beta2.BrokerConnectionType, uri string) []string {
+	prefix := "AMQPConnections." + bc.Name + "."
+
+	retryInterval := int32(brokerConnectionDefaultRetry)
+	if bc.RetryInterval != nil {
+		retryInterval = *bc.RetryInterval
+	}
+	reconnectAttempts := int32(brokerConnectionDefaultReconnects)
+	if bc.ReconnectAttempts != nil {
+		reconnectAttempts = *bc.ReconnectAttempts
+	}
+
+	props := []string{
+		prefix + "uri=" + uri,
+		prefix + "retryInterval=" + strconv.Itoa(int(retryInterval)),
+		prefix + "reconnectAttempts=" + strconv.Itoa(int(reconnectAttempts)),
+		prefix + "autostart=true",
+	}
+	if bc.CredentialsSecret != "" {
+		props = append(props,
+			prefix+"user=${"+brokerConnectionEnvVarName(bc.Name, brokerConnectionUserKey)+"}",
+			prefix+"password=${"+brokerConnectionEnvVarName(bc.Name, brokerConnectionPasswordKey)+"}")
+	}
+
+	if mirror := bc.Mirror; mirror != nil {
+		element := prefix + "connectionElements.mirror."
+		props = append(props,
+			element+"type=MIRROR",
+			element+"messageAcknowledgements="+strconv.FormatBool(mirror.MessageAcknowledgements == nil || *mirror.MessageAcknowledgements),
+			element+"queueCreation="+strconv.FormatBool(mirror.QueueCreation == nil || *mirror.QueueCreation),
+			element+"queueRemoval="+strconv.FormatBool(mirror.QueueRemoval == nil || *mirror.QueueRemoval),
+			element+"sync="+strconv.FormatBool(mirror.Sync))
+		if mirror.AddressFilter != "" {
+			props = append(props, element+"addressFilter="+mirror.AddressFilter)
+		}
+	}
+
+	if federation := bc.Federation; federation != nil {
+		element := prefix + "federations." + bc.Name + "."
+		for i, address := range federation.Addresses {
+			props = append(props, fmt.Sprintf("%slocalAddressPolicies.addresses.includes.m%d.addressMatch=%s", element, i, address))
+		}
+		for i, queue := range federation.Queues {
+			props = append(props, fmt.Sprintf("%slocalQueuePolicies.queues.includes.m%d.queueMatch=%s", element, i, queue))
+		}
+	}
+	return props
+}
+
+// brokerConnectionsSecretsToMount adds the operand cert and operator ca secrets needed by mtls broker connections
+func brokerConnectionsSecretsToMount(customResource *v1beta2.Broker, secretsToMount []string, client rtclient.Client) []string {
+	if !hasMTLSBrokerConnection(customResource) {
+		return secretsToMount
+	}
+	for _, secret := range []string{common.GetOperandCertSecretName(customResource, client), common.GetOperatorCASecretName()} {
+		found := false
+		for _, existing := range secretsToMount {
+			if existing == secret {
+				found = true
+				break
+			}
+		}
+		if !found {
+			secretsToMount = append(secretsToMount, secret)
+		}
+	}
+	return secretsToMount
+}
+
+// ProcessBrokerConnectionsStatus reports the state of each broker connection from the brokers
+func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessBrokerConnectionsStatus(cr *v1beta2.Broker, client rtclient.Client) (retry bool) {
+	if len(cr.Spec.BrokerConnections) == 0 {
+		cr.Status.BrokerConnections = nil
+		meta.RemoveStatusCondition(&cr.Status.Conditions, v1beta2.BrokerConnectionsConnectedConditionType)
+		return false
+	}
+
+	reconciler.resolveJolokiaEndpoints(cr, client)
+
+	statuses := []v1beta2.BrokerConnectionStatus{}
+	disconnected := []string{}
+	unknown := []string{}
+	for _, bc := range cr.Spec.BrokerConnections {
+		status := v1beta2.BrokerConnectionStatus{Name: bc.Name}
+		for _, jk := range reconciler.jolokiaEndpoints {
+			podName := namer.CrToSS(cr.Name) + "-" + jk.Ordinal
+			connected, err := jk.Artemis.IsBrokerConnectionConnected(bc.Name)
+			if err != nil {
+				reconciler.log.V(1).Info("unable to retrieve broker connection state", "pod", podName, "connection", bc.Name, "error", err)
+				unknown = append(unknown, podName)
+			} else if connected {
+				status.Connected = append(status.Connected, podName)
+			} else {
+				status.Disconnected = append(status.Disconnected, podName)
+			}
+		}
+		if len(status.Disconnected) > 0 {
+			disconnected = append(disconnected, bc.Name)
+		}
+		statuses = append(statuses, status)
+	}
+	cr.Status.BrokerConnections = statuses
+
+	condition := metav1.Condition{
+		Type:   v1beta2.BrokerConnectionsConnectedConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta2.BrokerConnectionsConnectedConditionReason,
+	}
+	switch {
+	case len(reconciler.jolokiaEndpoints) == 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = v1beta2.BrokerConnectionsConnectedConditionUnknownReason
+		condition.Message = "Waiting for Jolokia Clients to become available"
+	case len(unknown) > 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = v1beta2.BrokerConnectionsConnectedConditionUnknownReason
+		condition.Message = fmt.Sprintf("unable to retrieve broker connections status from: %s", strings.Join(unknown, ", "))
+	case len(disconnected) > 0:
+		// a target that is not reachable does not make this deployment unready
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = v1beta2.BrokerConnectionsConnectedConditionPendingReason
+		condition.Message = fmt.Sprintf("broker connections are not connected: %s", strings.Join(disconnected, ", "))
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, condition)
+
+	// a remote target that is down is reported and checked again on the next resync, only retry
+	// while the state of the local brokers can not be read
+	return condition.Reason == v1beta2.BrokerConnectionsConnectedConditionUnknownReason
+}
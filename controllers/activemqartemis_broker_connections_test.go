@@ -0,0 +1,226 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	artemis_client "github.com/arkmq-org/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
+)
+
+func TestValidateBrokerConnections(t *testing.T) {
+	size := int32(2)
+	target := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "dr", Namespace: "other"},
+		Spec:       v1beta2.BrokerSpec{DeploymentPlan: v1beta2.DeploymentPlanType{Size: &size}},
+	}
+	cl := newTestClient(target)
+
+	cr := &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "test"}}
+	condition, _ := validateBrokerConnections(cr, cl)
+	assert.Nil(t, condition)
+
+	cr.Spec.BrokerConnections = []v1beta2.BrokerConnectionType{
+		{Name: "dr", BrokerRef: &v1beta2.BrokerReference{Name: "dr", Namespace: "other"}, Mirror: &v1beta2.BrokerConnectionMirrorType{}},
+	}
+	condition, _ = validateBrokerConnections(cr, cl)
+	assert.Nil(t, condition)
+
+	cr.Spec.BrokerConnections[0].URI = "tcp://somewhere:61616"
+	condition, _ = validateBrokerConnections(cr, cl)
+	assert.NotNil(t, condition)
+	assert.Equal(t, v1beta2.ValidConditionFailedInvalidBrokerConnection, condition.Reason)
+	assert.Contains(t, condition.Message, "exactly one of BrokerRef or URI")
+
+	cr.Spec.BrokerConnections[0].URI = ""
+	cr.Spec.BrokerConnections = append(cr.Spec.BrokerConnections, cr.Spec.BrokerConnections[0])
+	condition, _ = validateBrokerConnections(cr, cl)
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "duplicated")
+
+	cr.Spec.BrokerConnections = []v1beta2.BrokerConnectionType{
+		{Name: "dr.a", URI: "tcp://somewhere:61616", Mirror: &v1beta2.BrokerConnectionMirrorType{}},
+	}
+	condition, _ = validateBrokerConnections(cr, cl)
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "invalid")
+
+	cr.Spec.BrokerConnections = []v1beta2.BrokerConnectionType{
+		{Name: "fed", URI: "tcp://somewhere:61616", Federation: &v1beta2.BrokerConnectionFederationType{}},
+	}
+	condition, _ = validateBrokerConnections(cr, cl)
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "Addresses or Queues")
+
+	cr.Spec.BrokerConnections = []v1beta2.BrokerConnectionType{
+		{Name: "dr", BrokerRef: &v1beta2.BrokerReference{Name: "dr"}, Mirror: &v1beta2.BrokerConnectionMirrorType{}},
+	}
+	condition, retry := validateBrokerConnections(cr, cl)
+	assert.NotNil(t, condition)
+	assert.True(t, retry)
+	assert.Equal(t, v1beta2.ValidConditionMissingResourcesReason, condition.Reason)
+
+	cr.Spec.BrokerConnections = []v1beta2.BrokerConnectionType{
+		{Name: "dr", URI: "tcp://somewhere:61616", CredentialsSecret: "creds", Mirror: &v1beta2.BrokerConnectionMirrorType{}},
+	}
+	condition, retry = validateBrokerConnections(cr, cl)
+	assert.NotNil(t, condition)
+	assert.True(t, retry)
+	assert.Contains(t, condition.Message, "credentials secret")
+
+	creds := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: cr.Namespace},
+		Data:       map[string][]byte{"username": []byte("dr")},
+	}
+	assert.NoError(t, cl.Create(context.TODO(), creds))
+	condition, retry = validateBrokerConnections(cr, cl)
+	assert.NotNil(t, condition)
+	assert.True(t, retry)
+	assert.Equal(t, v1beta2.ValidConditionMissingResourcesReason, condition.Reason)
+	assert.Contains(t, condition.Message, "credentials secret creds is missing the keys user, password")
+
+	creds.Data = map[string][]byte{"user": []byte("dr"), "password": []byte("secret")}
+	assert.NoError(t, cl.Update(context.TODO(), creds))
+	condition, _ = validateBrokerConnections(cr, cl)
+	assert.Nil(t, condition)
+}
+
+func TestProcessBrokerConnectionsProperties(t *testing.T) {
+	size := int32(2)
+	target := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "dr", Namespace: "other"},
+		Spec:       v1beta2.BrokerSpec{DeploymentPlan: v1beta2.DeploymentPlanType{Size: &size}},
+	}
+	cl := newTestClient(target)
+
+	queueRemoval := false
+	cr := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "test"},
+		Spec: v1beta2.BrokerSpec{
+			BrokerConnections: []v1beta2.BrokerConnectionType{
+				{
+					Name:              "dr-site",
+					BrokerRef:         &v1beta2.BrokerReference{Name: "dr", Namespace: "other"},
+					Port:              61617,
+					CredentialsSecret: "creds",
+					Mirror:            &v1beta2.BrokerConnectionMirrorType{QueueRemoval: &queueRemoval, AddressFilter: "orders,!orders.tmp"},
+				},
+				{
+					Name:       "upstream",
+					URI:        "tcp://remote:61616",
+					Federation: &v1beta2.BrokerConnectionFederationType{Addresses: []string{"news.#"}, Queues: []string{"work"}},
+				},
+			},
+		},
+	}
+
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	data := map[string][]byte{}
+	assert.NoError(t, ri.ProcessBrokerConnectionsProperties(data, cl))
+
+	props := string(data[brokerConnectionsPropertiesName])
+	assert.Contains(t, props, "AMQPConnections.dr-site.uri=tcp://"+common.OrdinalFQDNS("dr", "other", 0)+":61617#tcp://"+common.OrdinalFQDNS("dr", "other", 1)+":61617\n")
+	assert.Contains(t, props, "AMQPConnections.dr-site.user=${BROKER_CONNECTION_DR_SITE_USER}\n")
+	assert.Contains(t, props, "AMQPConnections.dr-site.password=${BROKER_CONNECTION_DR_SITE_PASSWORD}\n")
+	assert.Contains(t, props, "AMQPConnections.dr-site.connectionElements.mirror.type=MIRROR\n")
+	assert.Contains(t, props, "AMQPConnections.dr-site.connectionElements.mirror.queueRemoval=false\n")
+	assert.Contains(t, props, "AMQPConnections.dr-site.connectionElements.mirror.messageAcknowledgements=true\n")
+	assert.Contains(t, props, "AMQPConnections.dr-site.connectionElements.mirror.addressFilter=orders,!orders.tmp\n")
+	assert.Contains(t, props, "AMQPConnections.upstream.uri=tcp://remote:61616\n")
+	assert.Contains(t, props, "AMQPConnections.upstream.reconnectAttempts=-1\n")
+	assert.Contains(t, props, "AMQPConnections.upstream.federations.upstream.localAddressPolicies.addresses.includes.m0.addressMatch=news.#\n")
+	assert.Contains(t, props, "AMQPConnections.upstream.federations.upstream.localQueuePolicies.queues.includes.m0.queueMatch=work\n")
+	assert.NotContains(t, props, "AMQPConnections.upstream.user")
+	assert.NotContains(t, data, brokerConnectionsPemCfgKey)
+
+	envVars := MakeEnvVarArrayForBrokerConnections(cr)
+	assert.Len(t, envVars, 2)
+	assert.Equal(t, "BROKER_CONNECTION_DR_SITE_USER", envVars[0].Name)
+	assert.Equal(t, "creds", envVars[0].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, "password", envVars[1].ValueFrom.SecretKeyRef.Key)
+}
+
+func TestBrokerConnectionURIWithMTLSParams(t *testing.T) {
+	cr := &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "test"}}
+	bc := &v1beta2.BrokerConnectionType{Name: "dr", URI: "tcp://remote:61616?verifyHost=true"}
+
+	uri, err := brokerConnectionURI(cr, bc, "?sslEnabled=true", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://remote:61616?verifyHost=true;sslEnabled=true", uri)
+
+	bc.URI = "tcp://remote:61616"
+	uri, err = brokerConnectionURI(cr, bc, "?sslEnabled=true", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://remote:61616?sslEnabled=true", uri)
+
+	cr.Spec.BrokerConnections = []v1beta2.BrokerConnectionType{*bc}
+	assert.Equal(t, []string{"x"}, brokerConnectionsSecretsToMount(cr, []string{"x"}, nil))
+}
+
+func TestProcessBrokerConnectionsStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cr := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "test"},
+		Spec: v1beta2.BrokerSpec{
+			BrokerConnections: []v1beta2.BrokerConnectionType{
+				{Name: "dr", URI: "tcp://remote:61616", Mirror: &v1beta2.BrokerConnectionMirrorType{}},
+			},
+		},
+	}
+
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	endpoint := func(ordinal string, connected string) *jolokia_client.JkInfo {
+		j := jolokia.NewMockIJolokia(mockCtrl)
+		j.EXPECT().
+			Read(gomock.Eq("org.apache.activemq.artemis:broker=\"a\",component=broker-connections,name=\"dr\"/Connected")).
+			Return(&jolokia.ResponseData{Status: 200, Value: connected}, nil).
+			AnyTimes()
+		return &jolokia_client.JkInfo{Artemis: artemis_client.GetArtemisWithJolokia(j, "a"), IP: "IP", Ordinal: ordinal}
+	}
+
+	ri.jolokiaEndpoints = []*jolokia_client.JkInfo{endpoint("0", "true"), endpoint("1", "false")}
+	assert.False(t, ri.ProcessBrokerConnectionsStatus(cr, nil))
+	assert.Equal(t, []v1beta2.BrokerConnectionStatus{{Name: "dr", Connected: []string{"a-ss-0"}, Disconnected: []string{"a-ss-1"}}}, cr.Status.BrokerConnections)
+	condition := meta.FindStatusCondition(cr.Status.Conditions, v1beta2.BrokerConnectionsConnectedConditionType)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, v1beta2.BrokerConnectionsConnectedConditionPendingReason, condition.Reason)
+
+	ri.jolokiaEndpoints = []*jolokia_client.JkInfo{endpoint("0", "true"), endpoint("1", "true")}
+	assert.False(t, ri.ProcessBrokerConnectionsStatus(cr, nil))
+	assert.True(t, meta.IsStatusConditionTrue(cr.Status.Conditions, v1beta2.BrokerConnectionsConnectedConditionType))
+
+	cr.Spec.BrokerConnections = nil
+	assert.False(t, ri.ProcessBrokerConnectionsStatus(cr, nil))
+	assert.Nil(t, cr.Status.BrokerConnections)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, v1beta2.BrokerConnectionsConnectedConditionType))
+}
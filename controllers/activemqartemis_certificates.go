@@ -0,0 +1,287 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"hash/adler32"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	brokermetrics "github.com/arkmq-org/activemq-artemis-operator/pkg/metrics"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/namer"
+)
+
+// the CertificatesValid condition warns of the certificates that expire within this period
+const certificateExpiryWarningPeriod = 30 * 24 * time.Hour
+
+type certificateExpiry struct {
+	secret   types.NamespacedName
+	subject  string
+	notAfter time.Time
+}
+
+// a certificate secret that an acceptor reloads from the mounted PEM files
+type servedCertificate struct {
+	secret   types.NamespacedName
+	acceptor string
+}
+
+// trackCertificateSecret records a secret that the brokers consume for TLS, its certificates are checked for expiry
+// and a change of its data rolls the brokers when they only read it on start
+func (reconciler *ActiveMQArtemisReconcilerImpl) trackCertificateSecret(secret *corev1.Secret, restart bool) {
+	if secret == nil || len(secret.Data) == 0 {
+		return
+	}
+	if reconciler.certificateSecrets == nil {
+		reconciler.certificateSecrets = map[types.NamespacedName]*corev1.Secret{}
+		reconciler.restartCertificateSecrets = map[types.NamespacedName]bool{}
+	}
+	key := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
+	reconciler.certificateSecrets[key] = secret
+	if restart {
+		reconciler.restartCertificateSecrets[key] = true
+	}
+}
+
+// trackServedCertificate records an acceptor that reloads the certificate of a secret, the served certificate is
+// checked before the secret is reported as applied
+func (reconciler *ActiveMQArtemisReconcilerImpl) trackServedCertificate(secret types.NamespacedName, acceptor v1beta2.AcceptorType) {
+	reconciler.servedCertificates = append(reconciler.servedCertificates, servedCertificate{secret: secret, acceptor: acceptor.Name})
+}
+
+func (reconciler *ActiveMQArtemisReconcilerImpl) sortedCertificateSecrets() []types.NamespacedName {
+	keys := make([]types.NamespacedName, 0, len(reconciler.certificateSecrets))
+	for key := range reconciler.certificateSecrets {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	return keys
+}
+
+// writeCertificateSecrets adds the checksum of the certificate secrets that the brokers only read on start to the
+// checksum of the roll count once it differs from the baseline annotation of the StatefulSet, a secret that is
+// reloaded by the acceptors does not roll the brokers. The baseline is the checksum when the StatefulSet is first
+// reconciled with certificate secrets, so an existing StatefulSet keeps its roll count till a secret changes
+func (reconciler *ActiveMQArtemisReconcilerImpl) writeCertificateSecrets(digest hash.Hash32, statefulSet *appsv1.StatefulSet) {
+	certificates := adler32.New()
+	found := false
+	for _, key := range reconciler.sortedCertificateSecrets() {
+		if !reconciler.restartCertificateSecrets[key] {
+			continue
+		}
+		secret := reconciler.certificateSecrets[key]
+		for _, k := range sortedKeysStringKeyByteValue(secret.Data) {
+			certificates.Write(secret.Data[k])
+		}
+		found = true
+	}
+	if !found {
+		return
+	}
+	checkSum := hex.EncodeToString(certificates.Sum(nil))
+	baseline, seeded := statefulSet.Annotations[common.CertificatesChecksumAnnotation]
+	if !seeded {
+		common.ApplyAnnotations(&statefulSet.ObjectMeta, map[string]string{common.CertificatesChecksumAnnotation: checkSum})
+		return
+	}
+	if baseline != checkSum {
+		digest.Write([]byte(checkSum))
+	}
+}
+
+// certificateOf parses the first certificate of the tls.crt key of a secret, that the acceptors serve
+func certificateOf(secret *corev1.Secret) *x509.Certificate {
+	rest := secret.Data[corev1.TLSCertKey]
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil
+		}
+		if block.Type == "CERTIFICATE" {
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil
+			}
+			return cert
+		}
+	}
+}
+
+// processServedCertificatesStatus reports a secret that the acceptors reload in the external configs of the status
+// once each broker that is available with jolokia serves its certificate, the brokers report the certificates of
+// their acceptors in the tls info of their status. A broker that serves the previous certificate is retried, a broker
+// that does not report the tls info can not confirm the reload and the secret is not reported
+func (reconciler *ActiveMQArtemisReconcilerImpl) processServedCertificatesStatus(cr *v1beta2.Broker, client rtclient.Client) (retry bool) {
+	checked := map[types.NamespacedName]bool{}
+	for _, served := range reconciler.servedCertificates {
+		if checked[served.secret] {
+			continue
+		}
+		checked[served.secret] = true
+
+		secret := reconciler.certificateSecrets[served.secret]
+		if secret == nil || externalConfigApplied(cr, secret.Name, secret.ResourceVersion) {
+			continue
+		}
+		expected := certificateOf(secret)
+		if expected == nil {
+			continue
+		}
+		fingerprint := sha256.Sum256(expected.Raw)
+
+		unknown := false
+		err := reconciler.CheckStatus(cr, client, func(status *brokerStatus, jk *jolokia_client.JkInfo) ArtemisError {
+			for _, acceptor := range reconciler.servedCertificates {
+				if acceptor.secret != served.secret {
+					continue
+				}
+				pod := namer.CrToSS(cr.Name) + "-" + jk.Ordinal
+				tls, found := status.ServerStatus.TLS[acceptor.acceptor]
+				if !found {
+					unknown = true
+					return NewStatusOutOfSyncError(fmt.Errorf("broker %s does not report the certificate of acceptor %s", pod, acceptor.acceptor))
+				}
+				if !strings.EqualFold(strings.ReplaceAll(tls.Fingerprint, ":", ""), hex.EncodeToString(fingerprint[:])) {
+					return NewStatusOutOfSyncError(fmt.Errorf("acceptor %s on %s serves %s in place of %s from secret %s",
+						acceptor.acceptor, pod, tls.Subject, expected.Subject, secret.Name))
+				}
+			}
+			return nil
+		})
+		if unknown {
+			reconciler.log.V(1).Info("unable to confirm the certificate reload", "secret", secret.Name, "reason", err.Error())
+			continue
+		}
+		if err != nil {
+			reconciler.log.V(1).Info("certificate not reloaded yet", "secret", secret.Name, "reason", err.Error())
+			retry = true
+			continue
+		}
+		updateExtraConfigStatus(cr, &projection{Name: secret.Name, ResourceVersion: secret.ResourceVersion})
+	}
+	return retry
+}
+
+func externalConfigApplied(cr *v1beta2.Broker, name string, resourceVersion string) bool {
+	for _, config := range cr.Status.ExternalConfigs {
+		if config.Name == name {
+			return config.ResourceVersion == resourceVersion
+		}
+	}
+	return false
+}
+
+// certificateExpiryOf finds the certificate of a secret that expires first, in any key with PEM certificates,
+// secrets with keystores in other formats have no expiry
+func certificateExpiryOf(secret *corev1.Secret) *certificateExpiry {
+	var earliest *certificateExpiry
+	for _, key := range sortedKeysStringKeyByteValue(secret.Data) {
+		rest := secret.Data[key]
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				continue
+			}
+			if earliest == nil || cert.NotAfter.Before(earliest.notAfter) {
+				earliest = &certificateExpiry{
+					secret:   types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name},
+					subject:  cert.Subject.String(),
+					notAfter: cert.NotAfter,
+				}
+			}
+		}
+	}
+	return earliest
+}
+
+// processCertificatesStatus reports the expiry of the tracked certificates in the CertificatesValid condition and
+// the broker_certificate_expiry_days metric, a certificate that expires soon is a warning that keeps the brokers ready
+func (reconciler *ActiveMQArtemisReconcilerImpl) processCertificatesStatus(cr *v1beta2.Broker, now time.Time) {
+	if len(reconciler.requestedResources) == 0 {
+		// the resources were not processed, keep the last status
+		return
+	}
+
+	var expiries []*certificateExpiry
+	var metrics []brokermetrics.CertificateExpiry
+	for _, key := range reconciler.sortedCertificateSecrets() {
+		if expiry := certificateExpiryOf(reconciler.certificateSecrets[key]); expiry != nil {
+			expiries = append(expiries, expiry)
+			metrics = append(metrics, brokermetrics.CertificateExpiry{
+				Secret:          key.Name,
+				SecretNamespace: key.Namespace,
+				Days:            expiry.notAfter.Sub(now).Hours() / 24,
+			})
+		}
+	}
+	brokermetrics.UpdateCertificateMetrics(cr.Name, cr.Namespace, metrics)
+
+	if len(expiries) == 0 {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, v1beta2.CertificatesValidConditionType)
+		return
+	}
+
+	var expired, expiring []string
+	for _, expiry := range expiries {
+		if !now.Before(expiry.notAfter) {
+			expired = append(expired, fmt.Sprintf("%s %s expired on %s", expiry.secret, expiry.subject, expiry.notAfter.UTC().Format(time.RFC3339)))
+		} else if expiry.notAfter.Sub(now) < certificateExpiryWarningPeriod {
+			expiring = append(expiring, fmt.Sprintf("%s %s expires on %s", expiry.secret, expiry.subject, expiry.notAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:   v1beta2.CertificatesValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta2.CertificatesValidConditionReason,
+	}
+	if len(expired) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1beta2.CertificatesValidConditionExpiredReason
+		condition.Message = strings.Join(append(expired, expiring...), ", ")
+	} else if len(expiring) > 0 {
+		condition.Reason = v1beta2.CertificatesValidConditionExpiringReason
+		condition.Message = strings.Join(expiring, ", ")
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, condition)
+}
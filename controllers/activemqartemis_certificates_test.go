@@ -0,0 +1,220 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"hash/adler32"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	brokermetrics "github.com/arkmq-org/activemq-artemis-operator/pkg/metrics"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/environments"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/certutil"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
+)
+
+func certificatePEM(t *testing.T, commonName string, notAfter time.Time) (cert []byte, key []byte) {
+	privateKey, err := NewPriveKey()
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(crand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+}
+
+func TestCertificateExpiryAndRenewal(t *testing.T) {
+	cert, key := certificatePEM(t, "soon", time.Now().Add(10*24*time.Hour))
+	tlsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "amqp-tls", Namespace: "test", Annotations: map[string]string{certutil.Cert_annotation_key: "ca-issuer"}},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: cert, corev1.TLSPrivateKeyKey: key},
+	}
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "certs", Namespace: "test", UID: types.UID("certs-uid")},
+		Spec: v1beta2.BrokerSpec{
+			Acceptors: []v1beta2.AcceptorType{{Name: "amqp", Port: 5672, SSLEnabled: true, SSLSecret: tlsSecret.Name, SSLAutoReload: true}},
+		},
+	}
+	scheme := renderScheme()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(broker, tlsSecret).WithStatusSubresource(broker).Build()
+	reconciler := &BrokerReconciler{Client: client, Scheme: scheme, log: ctrl.Log}
+	brokerKey := types.NamespacedName{Name: "certs", Namespace: "test"}
+	defer brokermetrics.DeleteCertificateMetrics("certs", "test")
+
+	reconcile := func() (*v1beta2.Broker, string) {
+		result, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: brokerKey})
+		assert.NoError(t, err)
+		assert.NotZero(t, result.RequeueAfter)
+		current := &v1beta2.Broker{}
+		assert.NoError(t, client.Get(context.TODO(), brokerKey, current))
+		ss := &appsv1.StatefulSet{}
+		assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "certs-ss", Namespace: "test"}, ss))
+		return current, environments.Retrieve(ss.Spec.Template.Spec.Containers, "TRIGGERED_ROLL_COUNT").Value
+	}
+
+	current, rollCount := reconcile()
+
+	netty := &corev1.Secret{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "certs-netty-secret", Namespace: "test"}, netty))
+	assert.Contains(t, string(netty.Data["AMQ_ACCEPTORS"]), "sslAutoReload=true")
+
+	condition := meta.FindStatusCondition(current.Status.Conditions, v1beta2.CertificatesValidConditionType)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, v1beta2.CertificatesValidConditionExpiringReason, condition.Reason)
+		assert.Contains(t, condition.Message, "test/amqp-tls CN=soon expires on")
+	}
+	days := testutil.ToFloat64(brokermetrics.BrokerCertificateExpiryDays.With(prometheus.Labels{
+		"broker": "certs", "namespace": "test", "secret": "amqp-tls", "secret_namespace": "test",
+	}))
+	assert.InDelta(t, 10, days, 0.1)
+
+	// a renewed secret is reloaded by the acceptor, the brokers are not rolled
+	cert, key = certificatePEM(t, "renewed", time.Now().AddDate(1, 0, 0))
+	tlsSecret.Data = map[string][]byte{corev1.TLSCertKey: cert, corev1.TLSPrivateKeyKey: key}
+	assert.NoError(t, client.Update(context.TODO(), tlsSecret))
+	current, renewedRollCount := reconcile()
+
+	assert.Equal(t, rollCount, renewedRollCount)
+	condition = meta.FindStatusCondition(current.Status.Conditions, v1beta2.CertificatesValidConditionType)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, v1beta2.CertificatesValidConditionReason, condition.Reason)
+	}
+
+	// an expired certificate is not valid
+	cert, key = certificatePEM(t, "expired", time.Now().Add(-time.Hour))
+	tlsSecret.Data = map[string][]byte{corev1.TLSCertKey: cert, corev1.TLSPrivateKeyKey: key}
+	assert.NoError(t, client.Update(context.TODO(), tlsSecret))
+	current, _ = reconcile()
+
+	condition = meta.FindStatusCondition(current.Status.Conditions, v1beta2.CertificatesValidConditionType)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, v1beta2.CertificatesValidConditionExpiredReason, condition.Reason)
+	}
+	assert.False(t, meta.IsStatusConditionTrue(current.Status.Conditions, v1beta2.ReadyConditionType))
+}
+
+func TestWriteCertificateSecretsBaseline(t *testing.T) {
+	keystore := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "amqp-ks", Namespace: "test"},
+		Data:       map[string][]byte{"broker.ks": []byte("keystore")},
+	}
+	reconciler := &ActiveMQArtemisReconcilerImpl{}
+	reconciler.trackCertificateSecret(keystore, true)
+
+	checkSum := func(ss *appsv1.StatefulSet) uint32 {
+		digest := adler32.New()
+		reconciler.writeCertificateSecrets(digest, ss)
+		return digest.Sum32()
+	}
+	unchanged := adler32.New().Sum32()
+
+	// an existing StatefulSet is seeded with the certificates, its roll count does not change
+	ss := &appsv1.StatefulSet{}
+	assert.Equal(t, unchanged, checkSum(ss))
+	baseline := ss.Annotations[common.CertificatesChecksumAnnotation]
+	assert.NotEmpty(t, baseline)
+	assert.Equal(t, unchanged, checkSum(ss))
+	assert.Equal(t, baseline, ss.Annotations[common.CertificatesChecksumAnnotation])
+
+	// a renewed keystore changes the roll count, the baseline is kept
+	keystore.Data = map[string][]byte{"broker.ks": []byte("renewed")}
+	assert.NotEqual(t, unchanged, checkSum(ss))
+	assert.Equal(t, baseline, ss.Annotations[common.CertificatesChecksumAnnotation])
+
+	// a reloaded secret is not in the checksum
+	reconciler = &ActiveMQArtemisReconcilerImpl{}
+	reconciler.trackCertificateSecret(keystore, false)
+	ss = &appsv1.StatefulSet{}
+	assert.Equal(t, unchanged, checkSum(ss))
+	assert.NotContains(t, ss.Annotations, common.CertificatesChecksumAnnotation)
+}
+
+func TestProcessServedCertificatesStatus(t *testing.T) {
+	previous, _ := certificatePEM(t, "previous", time.Now().AddDate(0, 0, 10))
+	renewed, key := certificatePEM(t, "renewed", time.Now().AddDate(1, 0, 0))
+	tlsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "amqp-tls", Namespace: "test", ResourceVersion: "2"},
+		Data:       map[string][]byte{corev1.TLSCertKey: renewed, corev1.TLSPrivateKeyKey: key},
+	}
+
+	served := func(data []byte) brokerStatus {
+		block, _ := pem.Decode(data)
+		fingerprint := sha256.Sum256(block.Bytes)
+		return brokerStatus{ServerStatus: serverStatus{TLS: map[string]tlsStatus{
+			// the broker reports the fingerprint with colons
+			"amqp": {Subject: "CN=served", Fingerprint: strings.ToUpper(hex.EncodeToString(fingerprint[:1]) + ":" + hex.EncodeToString(fingerprint[1:]))},
+		}}}
+	}
+
+	cr := &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: "certs", Namespace: "test"}}
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+	ri.trackCertificateSecret(tlsSecret, false)
+	ri.trackServedCertificate(types.NamespacedName{Name: "amqp-tls", Namespace: "test"}, v1beta2.AcceptorType{Name: "amqp", Port: 5672})
+	ri.jolokiaEndpoints = []*jolokia_client.JkInfo{
+		{IP: "certs-ss-0.certs-hdls-svc.test.svc.cluster.local", Ordinal: "0"},
+		{IP: "certs-ss-1.certs-hdls-svc.test.svc.cluster.local", Ordinal: "1"},
+	}
+
+	// a broker still serves the previous certificate
+	ri.cachedBrokerStatus["0"] = served(renewed)
+	ri.cachedBrokerStatus["1"] = served(previous)
+	assert.True(t, ri.processServedCertificatesStatus(cr, nil))
+	assert.Empty(t, cr.Status.ExternalConfigs)
+
+	// a broker that does not report the certificate can not confirm the reload
+	ri.cachedBrokerStatus["1"] = brokerStatus{}
+	assert.False(t, ri.processServedCertificatesStatus(cr, nil))
+	assert.Empty(t, cr.Status.ExternalConfigs)
+
+	ri.cachedBrokerStatus["1"] = served(renewed)
+	assert.False(t, ri.processServedCertificatesStatus(cr, nil))
+	assert.Equal(t, []v1beta2.ExternalConfigStatus{{Name: "amqp-tls", ResourceVersion: "2"}}, cr.Status.ExternalConfigs)
+
+	// an applied version is not checked again
+	ri.cachedBrokerStatus["0"] = served(previous)
+	assert.False(t, ri.processServedCertificatesStatus(cr, nil))
+}
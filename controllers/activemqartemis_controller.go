@@ -41,6 +41,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	brokermetrics "github.com/arkmq-org/activemq-artemis-operator/pkg/metrics"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/certutil"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/namer"
@@ -136,6 +137,7 @@ func NewActiveMQArtemisReconciler(cluster cluster.Cluster, logger logr.Logger, i
 //+kubebuilder:rbac:groups=apps,namespace=arkmq-org-broker-operator,resources=deployments;daemonsets;replicasets;statefulsets,verbs=get;list;watch;create;delete;update
 //+kubebuilder:rbac:groups=networking.k8s.io,namespace=arkmq-org-broker-operator,resources=ingresses,verbs=get;list;watch;create;delete;update
 //+kubebuilder:rbac:groups=route.openshift.io,namespace=arkmq-org-broker-operator,resources=routes;routes/custom-host;routes/status,verbs=get;list;watch;create;delete;update
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,namespace=arkmq-org-broker-operator,resources=tlsroutes;httproutes,verbs=get;list;watch;create;delete;update
 //+kubebuilder:rbac:groups=monitoring.coreos.com,namespace=arkmq-org-broker-operator,resources=servicemonitors,verbs=get;create
 //+kubebuilder:rbac:groups=apps,namespace=arkmq-org-broker-operator,resources=deployments/finalizers,verbs=update
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,namespace=arkmq-org-broker-operator,resources=roles;rolebindings,verbs=create;get;delete
@@ -161,12 +163,31 @@ func (r *ActiveMQArtemisReconciler) Reconcile(ctx context.Context, request ctrl.
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			reqLogger.V(1).Info("ActiveMQArtemis Controller Reconcile encountered a IsNotFound, for request NamespacedName " + request.NamespacedName.String())
+			brokermetrics.DeleteCertificateMetrics(request.Name, request.Namespace)
 			return result, nil
 		}
 		reqLogger.Error(err, "unable to retrieve the ActiveMQArtemis")
 		return result, err
 	}
 
+	ensureImageCatalog(ctx, r.Client, reqLogger)
+
+	if brokerName, migrated := artemisResource.Annotations[common.MigratedToBrokerAnnotation]; migrated {
+		reqLogger.V(1).Info("ActiveMQArtemis migrated, it is reconciled as a Broker", "Broker", brokerName)
+		return result, nil
+	}
+
+	if mode, requested := isMigrationRequested(artemisResource); requested {
+		if migrated, err := r.migrateToBroker(artemisResource, mode); migrated || err != nil {
+			if err != nil {
+				reqLogger.Error(err, "failed to migrate ActiveMQArtemis to a Broker")
+			}
+			return result, err
+		}
+	} else {
+		meta.RemoveStatusCondition(&artemisResource.Status.Conditions, brokerv1beta1.MigratedConditionType)
+	}
+
 	customResource, err := ConvertArtemisToBroker(artemisResource)
 	if err != nil {
 		reqLogger.Error(err, "failed to convert ActiveMQArtemis to internal Broker representation")
@@ -220,6 +241,11 @@ func (r *ActiveMQArtemisReconciler) Reconcile(ctx context.Context, request ctrl.
 		requeueRequest = true
 	}
 
+	if !requeueRequest && !reconcileBlocked && len(reconciler.certificateSecrets) > 0 {
+		reqLogger.V(1).Info("resource has certificates, requeuing")
+		requeueRequest = true
+	}
+
 	if requeueRequest && err == nil {
 		reqLogger.V(1).Info("requeue reconcile")
 		result = ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}
@@ -262,6 +288,13 @@ func (r *ActiveMQArtemisReconcilerImpl) validate(customResource *v1beta2.Broker,
 		}
 	}
 
+	if validationCondition.Status != metav1.ConditionFalse {
+		condition, retry = validateBrokerPropertiesSchema(customResource, client)
+		if condition != nil {
+			validationCondition = *condition
+		}
+	}
+
 	if validationCondition.Status != metav1.ConditionFalse {
 		condition, retry = r.validateStorage()
 		if condition != nil {
@@ -304,6 +337,41 @@ func (r *ActiveMQArtemisReconcilerImpl) validate(customResource *v1beta2.Broker,
 		}
 	}
 
+	if validationCondition.Status != metav1.ConditionFalse {
+		condition, retry = validateHA(customResource)
+		if condition != nil {
+			validationCondition = *condition
+		}
+	}
+
+	if validationCondition.Status != metav1.ConditionFalse {
+		condition, retry = validateBrokerConnections(customResource, client)
+		if condition != nil {
+			validationCondition = *condition
+		}
+	}
+
+	if validationCondition.Status != metav1.ConditionFalse {
+		condition, retry = validateAutoscaling(customResource)
+		if condition != nil {
+			validationCondition = *condition
+		}
+	}
+
+	if validationCondition.Status != metav1.ConditionFalse {
+		condition, retry = validateMaintenanceWindow(customResource)
+		if condition != nil {
+			validationCondition = *condition
+		}
+	}
+
+	if validationCondition.Status != metav1.ConditionFalse {
+		condition, retry = validateUpdatePolicy(customResource)
+		if condition != nil {
+			validationCondition = *condition
+		}
+	}
+
 	if validationCondition.Status != metav1.ConditionFalse {
 		condition, retry = r.validateEnvVars(customResource)
 		if condition != nil {
@@ -317,11 +385,39 @@ func (r *ActiveMQArtemisReconcilerImpl) validate(customResource *v1beta2.Broker,
 			validationCondition = *condition
 		}
 	}
+
+	if validationCondition.Status != metav1.ConditionFalse {
+		condition := validateSecurityLoginModules(customResource)
+		if condition != nil {
+			validationCondition = *condition
+		}
+	}
 	common.SetStatusConditionWithGeneration(customResource, validationCondition)
 
 	return validationCondition.Status != metav1.ConditionFalse, retry
 }
 
+// validateSecurityLoginModules rejects the login modules that the init container of a broker can not configure, they
+// are only rendered as broker properties of restricted brokers
+func validateSecurityLoginModules(customResource *v1beta2.Broker) *metav1.Condition {
+	if common.IsRestricted(customResource) {
+		return nil
+	}
+	handler := GetBrokerConfigHandler(types.NamespacedName{Name: customResource.Name, Namespace: customResource.Namespace})
+	if handler == nil {
+		return nil
+	}
+	if modules := handler.BrokerPropertiesOnlyModules(); len(modules) > 0 {
+		return &metav1.Condition{
+			Type:    v1beta2.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta2.ValidConditionFailedUnsupportedLoginModules,
+			Message: fmt.Sprintf("the broker domain of ActiveMQArtemisSecurity %s references the login modules %s, ldap, certificate and kerberos login modules require .Spec.Restricted", handler.GetCRName(), strings.Join(modules, ",")),
+		}
+	}
+	return nil
+}
+
 func validateNoDupKeysInBrokerProperties(customResource *v1beta2.Broker) (*metav1.Condition, bool) {
 	if len(customResource.Spec.BrokerProperties) > 0 {
 		if duplicateKey := DuplicateKeyIn(customResource.Spec.BrokerProperties); duplicateKey != "" {
@@ -421,7 +517,50 @@ func (r *ActiveMQArtemisReconcilerImpl) validateExposeModes(customResource *v1be
 	}
 
 	for _, acceptor := range customResource.Spec.Acceptors {
-		if acceptor.Expose && (acceptor.ExposeMode != nil && *acceptor.ExposeMode == v1beta2.ExposeModes.Ingress || !r.isOnOpenShift) &&
+		if acceptor.Expose && isGatewayExposeMode(acceptor.ExposeMode) {
+			if condition := validateGatewayExposure(customResource, fmt.Sprintf(".Spec.Acceptors %q", acceptor.Name), acceptor.SSLEnabled); condition != nil {
+				return condition, false
+			}
+		}
+	}
+
+	for _, connector := range customResource.Spec.Connectors {
+		if connector.Expose && isGatewayExposeMode(connector.ExposeMode) {
+			if condition := validateGatewayExposure(customResource, fmt.Sprintf(".Spec.Connectors %q", connector.Name), connector.SSLEnabled); condition != nil {
+				return condition, false
+			}
+		}
+	}
+
+	if console := customResource.Spec.Console; console.Expose && isGatewayExposeMode(console.ExposeMode) {
+		// a console without ssl is exposed with an http route
+		if condition := validateGatewayExposure(customResource, ".Spec.Console", true); condition != nil {
+			return condition, false
+		}
+	}
+
+	for _, connector := range customResource.Spec.Connectors {
+		if connector.Expose && isServiceExposeMode(connector.ExposeMode) {
+			return &metav1.Condition{
+				Type:    v1beta2.ValidConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  v1beta2.ValidConditionFailedInvalidExposeMode,
+				Message: fmt.Sprintf(".Spec.Connectors %q has invalid expose mode %s, it is only supported for acceptors", connector.Name, *connector.ExposeMode),
+			}, false
+		}
+	}
+
+	if console := customResource.Spec.Console; console.Expose && isServiceExposeMode(console.ExposeMode) {
+		return &metav1.Condition{
+			Type:    v1beta2.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta2.ValidConditionFailedInvalidExposeMode,
+			Message: fmt.Sprintf(".Spec.Console has invalid expose mode %s, it is only supported for acceptors", *console.ExposeMode),
+		}, false
+	}
+
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if acceptor.Expose && !isServiceExposeMode(acceptor.ExposeMode) && (acceptor.ExposeMode != nil && *acceptor.ExposeMode != v1beta2.ExposeModes.Route || !r.isOnOpenShift) &&
 			customResource.Spec.IngressDomain == "" && acceptor.IngressHost == "" {
 			return &metav1.Condition{
 				Type:    v1beta2.ValidConditionType,
@@ -433,7 +572,7 @@ func (r *ActiveMQArtemisReconcilerImpl) validateExposeModes(customResource *v1be
 	}
 
 	for _, connector := range customResource.Spec.Connectors {
-		if connector.Expose && (connector.ExposeMode != nil && *connector.ExposeMode == v1beta2.ExposeModes.Ingress || !r.isOnOpenShift) &&
+		if connector.Expose && (connector.ExposeMode != nil && *connector.ExposeMode != v1beta2.ExposeModes.Route || !r.isOnOpenShift) &&
 			customResource.Spec.IngressDomain == "" && connector.IngressHost == "" {
 			return &metav1.Condition{
 				Type:    v1beta2.ValidConditionType,
@@ -445,7 +584,7 @@ func (r *ActiveMQArtemisReconcilerImpl) validateExposeModes(customResource *v1be
 	}
 
 	console := customResource.Spec.Console
-	if console.Expose && (console.ExposeMode != nil && *console.ExposeMode == v1beta2.ExposeModes.Ingress || !r.isOnOpenShift) &&
+	if console.Expose && (console.ExposeMode != nil && *console.ExposeMode != v1beta2.ExposeModes.Route || !r.isOnOpenShift) &&
 		customResource.Spec.IngressDomain == "" && console.IngressHost == "" {
 		return &metav1.Condition{
 			Type:    v1beta2.ValidConditionType,
@@ -458,6 +597,29 @@ func (r *ActiveMQArtemisReconcilerImpl) validateExposeModes(customResource *v1be
 	return nil, false
 }
 
+func isGatewayExposeMode(exposeMode *v1beta2.ExposeMode) bool {
+	return exposeMode != nil && *exposeMode == v1beta2.ExposeModes.Gateway
+}
+
+func validateGatewayExposure(customResource *v1beta2.Broker, item string, sslEnabled bool) *metav1.Condition {
+	var message string
+	if customResource.Spec.Gateway == nil || customResource.Spec.Gateway.Name == "" {
+		message = fmt.Sprintf("%s has expose mode gateway, it requires the Spec.Gateway to attach the route to", item)
+	} else if !common.IsGatewayAPIAvailable() {
+		message = fmt.Sprintf("%s has expose mode gateway, the Gateway API TLSRoute and HTTPRoute resources are not available", item)
+	} else if !sslEnabled {
+		message = fmt.Sprintf("%s has expose mode gateway, it requires SSL for TLS passthrough", item)
+	} else {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    v1beta2.ValidConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  v1beta2.ValidConditionFailedInvalidExposeMode,
+		Message: message,
+	}
+}
+
 func (r *ActiveMQArtemisReconcilerImpl) validateEnvVars(customResource *v1beta2.Broker) (*metav1.Condition, bool) {
 
 	internalVarNames := map[string]string{
@@ -656,6 +818,11 @@ func validateExtraMounts(customResource *v1beta2.Broker, client rtclient.Client)
 			instanceCounts[jaasConfigSuffix]++
 		} else if strings.HasSuffix(s, common.BrokerPropsSuffix) {
 			Condition = AssertNoDupKeyInProperties(secret, ContextMessage)
+			if Condition == nil {
+				if brokerVersion, err := common.BrokerVersionToDeploy(customResource); err == nil {
+					Condition = AssertBrokerPropertiesSchemaInProperties(secret, brokerVersion, ContextMessage)
+				}
+			}
 		}
 		if Condition != nil {
 			return Condition, retry
@@ -887,6 +1054,10 @@ func (r *ActiveMQArtemisReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&netv1.Ingress{}).
 		Owns(&policyv1.PodDisruptionBudget{})
 
+	if common.IsImageCatalogAvailable() {
+		watchImageCatalogs(builder, mgr.GetClient(), &brokerv1beta1.ActiveMQArtemisList{}, r.log)
+	}
+
 	if r.isOnOpenShift {
 		builder.Owns(&routev1.Route{})
 	}
@@ -154,6 +154,73 @@ func TestValidateBrokerPropsDuplicateOnFirstEqualsCorrect(t *testing.T) {
 	assert.True(t, meta.IsStatusConditionTrue(cr.Status.Conditions, brokerv1beta1.ValidConditionType))
 }
 
+func TestValidateBrokerPropsSchema(t *testing.T) {
+
+	cr := &v1beta2.Broker{
+		Spec: v1beta2.BrokerSpec{
+			BrokerProperties: []string{
+				"# a comment",
+				"broker-0.journalMinFiles=4",
+				"addressesSetings.#.maxSizeBytes=10M",
+			},
+		},
+	}
+
+	namer := MakeNamers(cr)
+
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	valid, retry := ri.validate(cr, k8sClient, *namer)
+
+	assert.False(t, valid)
+	assert.False(t, retry)
+
+	condition := meta.FindStatusCondition(cr.Status.Conditions, v1beta2.ValidConditionType)
+	assert.Equal(t, v1beta2.ValidConditionFailedInvalidBrokerProperties, condition.Reason)
+	assert.Contains(t, condition.Message, "did you mean addressesSettings")
+
+	cr.Spec.BrokerProperties = []string{"journalMinFiles=four"}
+	valid, _ = ri.validate(cr, k8sClient, *namer)
+	assert.False(t, valid)
+	condition = meta.FindStatusCondition(cr.Status.Conditions, v1beta2.ValidConditionType)
+	assert.Contains(t, condition.Message, "journalMinFiles value four is not a valid int")
+}
+
+func TestValidateBrokerPropsSchemaInSecrets(t *testing.T) {
+
+	cr := &v1beta2.Broker{
+		ObjectMeta: v1.ObjectMeta{Name: "cr", Namespace: "test"},
+		Spec: v1beta2.BrokerSpec{
+			DeploymentPlan: v1beta2.DeploymentPlanType{
+				ExtraMounts: v1beta2.ExtraMountsType{Secrets: []string{"extra-bp"}},
+			},
+		},
+	}
+	extra := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: "extra-bp", Namespace: "test"},
+		Data:       map[string][]byte{"a.properties": []byte("criticalAnalyzer=maybe\n")},
+	}
+	override := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: "control-plane-override", Namespace: "test"},
+		Data:       map[string][]byte{"aa_restricted.properties": []byte("globalMaxSise=1G\n"), "_prometheus_exporter.yaml": []byte("x: y")},
+	}
+	fakeClient := newTestClient(extra, override)
+
+	condition, retry := validateExtraMounts(cr, fakeClient)
+	assert.True(t, retry)
+	assert.Equal(t, v1beta2.ValidConditionFailedInvalidBrokerProperties, condition.Reason)
+	assert.Contains(t, condition.Message, "extra-bp entry a.properties, criticalAnalyzer value maybe is not a valid boolean")
+
+	condition, _ = validateBrokerPropertiesSchema(cr, fakeClient)
+	assert.Nil(t, condition)
+
+	cr.Spec.Restricted = common.NewTrue()
+	condition, retry = validateBrokerPropertiesSchema(cr, fakeClient)
+	assert.True(t, retry)
+	assert.Contains(t, condition.Message, "control-plane-override entry aa_restricted.properties, globalMaxSise has an unknown property globalMaxSise, did you mean globalMaxSize")
+}
+
 func TestStatusPodsCheckCached(t *testing.T) {
 
 	replicas := int32(1)
@@ -223,14 +290,14 @@ func TestJolokiaStatusCached(t *testing.T) {
 	a := artemis_client.GetArtemisWithJolokia(j, "a")
 
 	j.EXPECT().
-		Read(gomock.Eq("org.apache.activemq.artemis:broker=\"a\"/Status")).
-		DoAndReturn(func(_ string) (*jolokia.ResponseData, error) {
-			return &jolokia.ResponseData{
+		Bulk(gomock.Eq([]jolokia.Request{{Type: "read", MBean: "org.apache.activemq.artemis:broker=\"a\"", Attribute: []string{"Status", "Backup", "ReplicaSync"}}})).
+		DoAndReturn(func(_ []jolokia.Request) ([]*jolokia.ResponseData, error) {
+			return []*jolokia.ResponseData{{
 				Status:    404,
 				Value:     "",
 				ErrorType: "javax.management.AttributeNotFoundException",
 				Error:     "javax.management.AttributeNotFoundException : No such attribute: Status",
-			}, fmt.Errorf("javax.management.AttributeNotFoundException")
+			}}, fmt.Errorf("javax.management.AttributeNotFoundException")
 		}).Times(1)
 
 	valid := ri.CheckStatusFromJolokia(&jolokia_client.JkInfo{Artemis: a, IP: "IP", Ordinal: "0"}, checkOk)
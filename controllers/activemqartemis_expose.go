@@ -0,0 +1,196 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/services"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+const (
+	externalPropertiesName     = "external" + PropertiesSuffix
+	externalConnectorSuffix    = "-external"
+	nettyConnectorFactoryClass = "org.apache.activemq.artemis.core.remoting.impl.netty.NettyConnectorFactory"
+)
+
+// the loadBalancer and nodePort modes expose an acceptor with a Service for each broker in place of a route
+func isServiceExposeMode(exposeMode *v1beta2.ExposeMode) bool {
+	return exposeMode != nil && (*exposeMode == v1beta2.ExposeModes.LoadBalancer || *exposeMode == v1beta2.ExposeModes.NodePort)
+}
+
+func exposedServiceName(customResource *v1beta2.Broker, acceptor *v1beta2.AcceptorType, ordinal int) string {
+	postfix := NodePortTypePostfix
+	if *acceptor.ExposeMode == v1beta2.ExposeModes.LoadBalancer {
+		postfix = LoadBalancerTypePostfix
+	}
+	return fmt.Sprintf("%s-%s-%d-%s-%s", customResource.Name, acceptor.Name, ordinal, ServiceTypePostfix, postfix)
+}
+
+func (reconciler *ActiveMQArtemisReconcilerImpl) exposedServiceDefinitionForCR(customResource *v1beta2.Broker, acceptor *v1beta2.AcceptorType, ordinal int, selectorLabels map[string]string, labels map[string]string) *corev1.Service {
+	serviceName := types.NamespacedName{Namespace: customResource.Namespace, Name: exposedServiceName(customResource, acceptor, ordinal)}
+	reconciler.log.V(1).Info("creating exposed service for "+acceptor.Name, "service", serviceName.Name)
+
+	var existing *corev1.Service = nil
+	obj := reconciler.cloneOfDeployed(reflect.TypeOf(corev1.Service{}), serviceName.Name)
+	if obj != nil {
+		existing = obj.(*corev1.Service)
+	}
+
+	serviceType := corev1.ServiceTypeNodePort
+	if *acceptor.ExposeMode == v1beta2.ExposeModes.LoadBalancer {
+		serviceType = corev1.ServiceTypeLoadBalancer
+	}
+	var nodePort int32
+	var sourceRanges []string
+	var annotations map[string]string
+	if acceptor.ExposeService != nil {
+		if ordinal < len(acceptor.ExposeService.NodePorts) {
+			nodePort = acceptor.ExposeService.NodePorts[ordinal]
+		}
+		sourceRanges = acceptor.ExposeService.LoadBalancerSourceRanges
+		if len(acceptor.ExposeService.Annotations) > 0 {
+			annotations = make(map[string]string, len(acceptor.ExposeService.Annotations))
+			for k, v := range acceptor.ExposeService.Annotations {
+				annotations[k] = v
+			}
+		}
+	}
+
+	nameSuffix := acceptor.Name + "-" + strconv.Itoa(ordinal)
+	return services.NewExposedServiceDefinitionForCR(serviceName, nameSuffix, acceptor.Port, serviceType, nodePort, sourceRanges, annotations, selectorLabels, labels, existing)
+}
+
+// externalAddressOf finds the address that clients outside the cluster use from the deployed Service, the
+// advertised host has precedence over the address assigned to a load balancer
+func externalAddressOf(customResource *v1beta2.Broker, acceptor *v1beta2.AcceptorType, ordinal int, service *corev1.Service) v1beta2.ExternalAddressStatus {
+	address := v1beta2.ExternalAddressStatus{
+		Acceptor: acceptor.Name,
+		Ordinal:  int32(ordinal),
+		Service:  exposedServiceName(customResource, acceptor, ordinal),
+	}
+
+	if acceptor.ExposeService != nil && acceptor.ExposeService.AdvertisedHost != "" {
+		address.Host = formatTemplatedString(customResource, acceptor.ExposeService.AdvertisedHost, strconv.Itoa(ordinal), acceptor.Name, ServiceTypePostfix)
+	}
+
+	if *acceptor.ExposeMode == v1beta2.ExposeModes.LoadBalancer {
+		address.Port = acceptor.Port
+		if address.Host == "" && service != nil {
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				if ingress.Hostname != "" {
+					address.Host = ingress.Hostname
+				} else {
+					address.Host = ingress.IP
+				}
+				if address.Host != "" {
+					break
+				}
+			}
+		}
+	} else if service != nil && len(service.Spec.Ports) > 0 {
+		address.Port = service.Spec.Ports[0].NodePort
+	}
+	return address
+}
+
+func externalAddressesOf(customResource *v1beta2.Broker, lookup func(name string) *corev1.Service) []v1beta2.ExternalAddressStatus {
+	var addresses []v1beta2.ExternalAddressStatus
+	size := int(common.GetDeploymentSize(customResource))
+	for i := range customResource.Spec.Acceptors {
+		acceptor := &customResource.Spec.Acceptors[i]
+		if !acceptor.Expose || !isServiceExposeMode(acceptor.ExposeMode) {
+			continue
+		}
+		for ordinal := 0; ordinal < size; ordinal++ {
+			addresses = append(addresses, externalAddressOf(customResource, acceptor, ordinal, lookup(exposedServiceName(customResource, acceptor, ordinal))))
+		}
+	}
+	return addresses
+}
+
+// ProcessExternalAddressProperties adds an ordinal specific property file with a connector to the external address
+// of each exposed acceptor that has a cluster connection to advertise it, an address without a host or a port is not known yet and has no connector. The file
+// exists before the addresses are known because a new ordinal key changes the projection of the pod template
+func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessExternalAddressProperties(m map[string][]byte) {
+	addresses := externalAddressesOf(reconciler.customResource, func(name string) *corev1.Service {
+		if obj := reconciler.getFromDeployed(reflect.TypeOf(corev1.Service{}), name); obj != nil {
+			return obj.(*corev1.Service)
+		}
+		return nil
+	})
+
+	buffers := map[int32]*bytes.Buffer{}
+	for _, address := range addresses {
+		buf, found := buffers[address.Ordinal]
+		if !found {
+			buf = NewPropsWithHeader()
+			buffers[address.Ordinal] = buf
+		}
+		if address.Host == "" || address.Port == 0 {
+			continue
+		}
+		for _, property := range externalConnectorProperties(reconciler.customResource, address) {
+			fmt.Fprintln(buf, property)
+		}
+	}
+	for ordinal, buf := range buffers {
+		m[fmt.Sprintf("%s%d%s%s", OrdinalPrefix, ordinal, OrdinalPrefixSep, externalPropertiesName)] = buf.Bytes()
+	}
+}
+
+// externalConnectorProperties defines the <acceptor>-external connector of a broker and sets it as the connector of
+// the cluster connection of the acceptor, an acceptor without a cluster connection has no connector
+func externalConnectorProperties(customResource *v1beta2.Broker, address v1beta2.ExternalAddressStatus) []string {
+	var props []string
+	for _, acceptor := range customResource.Spec.Acceptors {
+		if acceptor.Name != address.Acceptor || acceptor.ExposeService == nil || acceptor.ExposeService.ClusterConnection == "" {
+			continue
+		}
+		connector := address.Acceptor + externalConnectorSuffix
+		prefix := "connectorConfigurations." + connector
+		props = append(props,
+			prefix+".factoryClassName="+nettyConnectorFactoryClass,
+			prefix+".params.host="+address.Host,
+			prefix+".params.port="+strconv.Itoa(int(address.Port)))
+		if acceptor.SSLEnabled {
+			props = append(props, prefix+".params.sslEnabled=true")
+		}
+		props = append(props, "clusterConfigurations."+acceptor.ExposeService.ClusterConnection+".connectorName="+connector)
+	}
+	return props
+}
+
+// processExternalAddressesStatus reports the addresses of the exposed acceptors, a Service change triggers a
+// reconcile so a pending load balancer needs no retry
+func (reconciler *ActiveMQArtemisReconcilerImpl) processExternalAddressesStatus(cr *v1beta2.Broker, client rtclient.Client) {
+	cr.Status.ExternalAddresses = externalAddressesOf(cr, func(name string) *corev1.Service {
+		service := &corev1.Service{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Namespace: cr.Namespace, Name: name}, service); err != nil {
+			return nil
+		}
+		return service
+	})
+}
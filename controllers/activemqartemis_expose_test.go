@@ -0,0 +1,132 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+func TestLoadBalancerAndNodePortExposeModes(t *testing.T) {
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "ext", Namespace: "test", UID: types.UID("ext-uid")},
+		Spec: v1beta2.BrokerSpec{
+			DeploymentPlan: v1beta2.DeploymentPlanType{Size: common.Int32ToPtr(2)},
+			Acceptors: []v1beta2.AcceptorType{
+				{
+					Name:       "amqp",
+					Port:       5672,
+					Expose:     true,
+					ExposeMode: &v1beta2.ExposeModes.LoadBalancer,
+					ExposeService: &v1beta2.ExposeServiceType{
+						Annotations:              map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+						LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+					},
+				},
+				{
+					Name:       "core",
+					Port:       61616,
+					Expose:     true,
+					ExposeMode: &v1beta2.ExposeModes.NodePort,
+					ExposeService: &v1beta2.ExposeServiceType{
+						NodePorts:         []int32{30616, 30617},
+						AdvertisedHost:    "node-$(BROKER_ORDINAL).example.com",
+						ClusterConnection: "my-cluster",
+					},
+				},
+			},
+		},
+	}
+	scheme := renderScheme()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(broker).
+		WithStatusSubresource(broker, &corev1.Service{}).Build()
+	reconciler := &BrokerReconciler{Client: client, Scheme: scheme, log: ctrl.Log}
+	key := types.NamespacedName{Name: "ext", Namespace: "test"}
+
+	reconcile := func() *v1beta2.Broker {
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+		assert.NoError(t, err)
+		current := &v1beta2.Broker{}
+		assert.NoError(t, client.Get(context.TODO(), key, current))
+		return current
+	}
+
+	current := reconcile()
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, v1beta2.ValidConditionType))
+
+	lb := &corev1.Service{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "ext-amqp-1-svc-lb", Namespace: "test"}, lb))
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, lb.Spec.Type)
+	assert.Equal(t, "nlb", lb.Annotations["service.beta.kubernetes.io/aws-load-balancer-type"])
+	assert.Equal(t, []string{"10.0.0.0/8"}, lb.Spec.LoadBalancerSourceRanges)
+	assert.Equal(t, "ext-ss-1", lb.Spec.Selector[PodNameLabelKey])
+
+	np := &corev1.Service{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "ext-core-1-svc-np", Namespace: "test"}, np))
+	assert.Equal(t, corev1.ServiceTypeNodePort, np.Spec.Type)
+	assert.Equal(t, int32(30617), np.Spec.Ports[0].NodePort)
+	assert.Empty(t, np.Spec.LoadBalancerSourceRanges)
+
+	// the load balancer address is not assigned yet
+	assert.Contains(t, current.Status.ExternalAddresses, v1beta2.ExternalAddressStatus{Acceptor: "amqp", Ordinal: 1, Service: "ext-amqp-1-svc-lb", Port: 5672})
+	assert.Contains(t, current.Status.ExternalAddresses, v1beta2.ExternalAddressStatus{Acceptor: "core", Ordinal: 1, Service: "ext-core-1-svc-np", Host: "node-1.example.com", Port: 30617})
+
+	deployedSS := &appsv1.StatefulSet{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "ext-ss", Namespace: "test"}, deployedSS))
+
+	lb.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "192.0.2.10"}}
+	assert.NoError(t, client.Status().Update(context.TODO(), lb))
+	current = reconcile()
+
+	// the assigned address is reloaded, the brokers are not restarted
+	reconciledSS := &appsv1.StatefulSet{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "ext-ss", Namespace: "test"}, reconciledSS))
+	assert.Equal(t, deployedSS.Spec.Template, reconciledSS.Spec.Template)
+
+	assert.Contains(t, current.Status.ExternalAddresses, v1beta2.ExternalAddressStatus{Acceptor: "amqp", Ordinal: 1, Service: "ext-amqp-1-svc-lb", Host: "192.0.2.10", Port: 5672})
+
+	props := &corev1.Secret{}
+	assert.NoError(t, client.Get(context.TODO(), getPropertiesResourceNsName(current), props))
+	external := string(props.Data["broker-1."+externalPropertiesName])
+	assert.Contains(t, external, "connectorConfigurations.core-external.params.host=node-1.example.com\n")
+	assert.Contains(t, external, "connectorConfigurations.core-external.params.port=30617\n")
+	assert.Contains(t, external, "clusterConfigurations.my-cluster.connectorName=core-external\n")
+	// the amqp acceptor has no cluster connection to advertise its address
+	assert.NotContains(t, external, "amqp-external")
+	assert.NotContains(t, string(props.Data["broker-0."+externalPropertiesName]), "amqp-external")
+
+	// only acceptors have a Service for each broker
+	current.Spec.Console = v1beta2.ConsoleType{Expose: true, ExposeMode: &v1beta2.ExposeModes.NodePort}
+	assert.NoError(t, client.Update(context.TODO(), current))
+	current = reconcile()
+
+	valid := meta.FindStatusCondition(current.Status.Conditions, v1beta2.ValidConditionType)
+	if assert.NotNil(t, valid) {
+		assert.Equal(t, metav1.ConditionFalse, valid.Status)
+		assert.Equal(t, v1beta2.ValidConditionFailedInvalidExposeMode, valid.Reason)
+	}
+}
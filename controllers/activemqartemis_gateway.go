@@ -0,0 +1,148 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+)
+
+// processGatewayRoutesStatus reports whether the gateway accepted the routes of the CR, from the status that the
+// gateway controller sets on each route for its parents. A route without a status for the gateway is pending
+func (reconciler *ActiveMQArtemisReconcilerImpl) processGatewayRoutesStatus(cr *v1beta2.Broker, client rtclient.Client) (retry bool) {
+	if len(reconciler.requestedResources) == 0 {
+		// the resources were not processed, keep the last status
+		return false
+	}
+
+	statuses := map[string]*gatewayv1beta1.RouteStatus{}
+	for name := range reconciler.requestedResources[reflect.TypeOf(&gatewayv1alpha2.TLSRoute{})] {
+		route := &gatewayv1alpha2.TLSRoute{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Namespace: cr.Namespace, Name: name}, route); err == nil {
+			statuses[name] = &route.Status.RouteStatus
+		} else if k8serrors.IsNotFound(err) {
+			statuses[name] = nil
+		} else {
+			return setGatewayRoutesUnknown(cr, fmt.Sprintf("failed to get TLSRoute %s, %v", name, err))
+		}
+	}
+	for name := range reconciler.requestedResources[reflect.TypeOf(&gatewayv1beta1.HTTPRoute{})] {
+		route := &gatewayv1beta1.HTTPRoute{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Namespace: cr.Namespace, Name: name}, route); err == nil {
+			statuses[name] = &route.Status.RouteStatus
+		} else if k8serrors.IsNotFound(err) {
+			statuses[name] = nil
+		} else {
+			return setGatewayRoutesUnknown(cr, fmt.Sprintf("failed to get HTTPRoute %s, %v", name, err))
+		}
+	}
+
+	if len(statuses) == 0 {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, v1beta2.GatewayRoutesAcceptedConditionType)
+		return false
+	}
+
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	gateway := gatewayNamespacedName(cr)
+	var pending, rejected []string
+	for _, name := range names {
+		if problem, accepted := routeAcceptedBy(statuses[name], gateway, cr.Namespace); !accepted {
+			if problem == nil {
+				pending = append(pending, name)
+			} else {
+				rejected = append(rejected, fmt.Sprintf("%s %s: %s", name, problem.Reason, problem.Message))
+			}
+		}
+	}
+
+	if len(rejected) > 0 {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    v1beta2.GatewayRoutesAcceptedConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta2.GatewayRoutesAcceptedConditionRejectedReason,
+			Message: fmt.Sprintf("routes not accepted by gateway %s, %s", gateway, strings.Join(rejected, ", ")),
+		})
+		return false
+	}
+	if len(pending) > 0 {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    v1beta2.GatewayRoutesAcceptedConditionType,
+			Status:  metav1.ConditionUnknown,
+			Reason:  v1beta2.GatewayRoutesAcceptedConditionPendingReason,
+			Message: fmt.Sprintf("waiting for gateway %s to accept routes %s", gateway, strings.Join(pending, ", ")),
+		})
+		return true
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:   v1beta2.GatewayRoutesAcceptedConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta2.GatewayRoutesAcceptedConditionReason,
+	})
+	return false
+}
+
+func setGatewayRoutesUnknown(cr *v1beta2.Broker, message string) bool {
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:    v1beta2.GatewayRoutesAcceptedConditionType,
+		Status:  metav1.ConditionUnknown,
+		Reason:  v1beta2.GatewayRoutesAcceptedConditionPendingReason,
+		Message: message,
+	})
+	return true
+}
+
+// routeAcceptedBy finds the status of the gateway in the parents of a route, the first false Accepted or ResolvedRefs
+// condition is the problem. No problem and not accepted means the gateway did not report yet
+func routeAcceptedBy(status *gatewayv1beta1.RouteStatus, gateway types.NamespacedName, routeNamespace string) (*metav1.Condition, bool) {
+	if status == nil {
+		return nil, false
+	}
+	for _, parent := range status.Parents {
+		namespace := routeNamespace
+		if parent.ParentRef.Namespace != nil {
+			namespace = string(*parent.ParentRef.Namespace)
+		}
+		if string(parent.ParentRef.Name) != gateway.Name || namespace != gateway.Namespace {
+			continue
+		}
+		for _, conditionType := range []gatewayv1beta1.RouteConditionType{gatewayv1beta1.RouteConditionAccepted, gatewayv1beta1.RouteConditionResolvedRefs} {
+			if condition := meta.FindStatusCondition(parent.Conditions, string(conditionType)); condition != nil && condition.Status == metav1.ConditionFalse {
+				return condition, false
+			}
+		}
+		if meta.IsStatusConditionTrue(parent.Conditions, string(gatewayv1beta1.RouteConditionAccepted)) {
+			return nil, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,132 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+func TestGatewayExposeMode(t *testing.T) {
+	common.SetGatewayAPIAvailable(true)
+	defer common.SetGatewayAPIAvailable(false)
+
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "test", UID: types.UID("gw-uid")},
+		Spec: v1beta2.BrokerSpec{
+			IngressDomain: "example.com",
+			Gateway:       &v1beta2.GatewayType{Name: "edge", Namespace: "infra", HTTPListener: "http"},
+			Console: v1beta2.ConsoleType{
+				Expose:     true,
+				ExposeMode: &v1beta2.ExposeModes.Gateway,
+			},
+		},
+	}
+	scheme := renderScheme()
+	_ = gatewayv1alpha2.AddToScheme(scheme)
+	_ = gatewayv1beta1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(broker).
+		WithStatusSubresource(broker, &gatewayv1beta1.HTTPRoute{}).Build()
+	reconciler := &BrokerReconciler{Client: client, Scheme: scheme, log: ctrl.Log}
+	key := types.NamespacedName{Name: "gw", Namespace: "test"}
+
+	reconcile := func() *v1beta2.Broker {
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+		assert.NoError(t, err)
+		current := &v1beta2.Broker{}
+		assert.NoError(t, client.Get(context.TODO(), key, current))
+		return current
+	}
+
+	current := reconcile()
+
+	routes := &gatewayv1beta1.HTTPRouteList{}
+	assert.NoError(t, client.List(context.TODO(), routes))
+	if !assert.Len(t, routes.Items, 1) {
+		return
+	}
+	route := &routes.Items[0]
+	assert.Equal(t, "gw-wconsj-0-svc-"+HTTPRouteTypePostfix, route.Name)
+	if assert.Len(t, route.Spec.ParentRefs, 1) {
+		parent := route.Spec.ParentRefs[0]
+		assert.Equal(t, gatewayv1beta1.ObjectName("edge"), parent.Name)
+		assert.Equal(t, gatewayv1beta1.Namespace("infra"), *parent.Namespace)
+		assert.Equal(t, gatewayv1beta1.SectionName("http"), *parent.SectionName)
+	}
+	assert.Equal(t, []gatewayv1beta1.Hostname{gatewayv1beta1.Hostname(route.Name + "-test.example.com")}, route.Spec.Hostnames)
+	assert.Equal(t, gatewayv1beta1.ObjectName("gw-wconsj-0-svc"), route.Spec.Rules[0].BackendRefs[0].Name)
+
+	accepted := meta.FindStatusCondition(current.Status.Conditions, v1beta2.GatewayRoutesAcceptedConditionType)
+	if assert.NotNil(t, accepted) {
+		assert.Equal(t, metav1.ConditionUnknown, accepted.Status)
+		assert.Equal(t, v1beta2.GatewayRoutesAcceptedConditionPendingReason, accepted.Reason)
+	}
+
+	// the gateway controller rejects the route
+	namespace := gatewayv1beta1.Namespace("infra")
+	route.Status.Parents = []gatewayv1beta1.RouteParentStatus{{
+		ParentRef:      gatewayv1beta1.ParentReference{Name: "edge", Namespace: &namespace},
+		ControllerName: "example.com/gateway",
+		Conditions: []metav1.Condition{{
+			Type:               string(gatewayv1beta1.RouteConditionAccepted),
+			Status:             metav1.ConditionFalse,
+			Reason:             string(gatewayv1beta1.RouteReasonNotAllowedByListeners),
+			Message:            "not allowed",
+			LastTransitionTime: metav1.Now(),
+		}},
+	}}
+	assert.NoError(t, client.Status().Update(context.TODO(), route))
+	current = reconcile()
+
+	accepted = meta.FindStatusCondition(current.Status.Conditions, v1beta2.GatewayRoutesAcceptedConditionType)
+	if assert.NotNil(t, accepted) {
+		assert.Equal(t, metav1.ConditionFalse, accepted.Status)
+		assert.Equal(t, v1beta2.GatewayRoutesAcceptedConditionRejectedReason, accepted.Reason)
+		assert.Contains(t, accepted.Message, "not allowed")
+	}
+
+	// and then accepts it
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: route.Name, Namespace: "test"}, route))
+	route.Status.Parents[0].Conditions[0].Status = metav1.ConditionTrue
+	route.Status.Parents[0].Conditions[0].Reason = string(gatewayv1beta1.RouteReasonAccepted)
+	assert.NoError(t, client.Status().Update(context.TODO(), route))
+	current = reconcile()
+
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, v1beta2.GatewayRoutesAcceptedConditionType))
+
+	// an acceptor needs SSL for a TLS passthrough route
+	current.Spec.Acceptors = []v1beta2.AcceptorType{{Name: "amqp", Port: 5672, Expose: true, ExposeMode: &v1beta2.ExposeModes.Gateway}}
+	assert.NoError(t, client.Update(context.TODO(), current))
+	current = reconcile()
+
+	valid := meta.FindStatusCondition(current.Status.Conditions, v1beta2.ValidConditionType)
+	if assert.NotNil(t, valid) {
+		assert.Equal(t, metav1.ConditionFalse, valid.Status)
+		assert.Equal(t, v1beta2.ValidConditionFailedInvalidExposeMode, valid.Reason)
+		assert.Contains(t, valid.Message, "amqp")
+	}
+}
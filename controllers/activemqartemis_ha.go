@@ -0,0 +1,307 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/namer"
+)
+
+const (
+	haPropertiesName             = "ha" + PropertiesSuffix
+	haSharedStoreVolumeName      = "ha-shared-store"
+	haSharedStoreMountPath       = "/amq/ha/shared-store"
+	haDefaultAntiAffinityTopoKey = "kubernetes.io/hostname"
+)
+
+// primary/backup pairs are formed from consecutive ordinals, even ordinals are primaries
+func haPairOf(ordinal int) int {
+	return ordinal / 2
+}
+
+func haIsPrimary(ordinal int) bool {
+	return ordinal%2 == 0
+}
+
+func haGroupName(crName string, pair int) string {
+	return fmt.Sprintf("%s-pair-%d", crName, pair)
+}
+
+func haAllowFailBack(ha *v1beta2.HAType) bool {
+	return ha.AllowFailBack == nil || *ha.AllowFailBack
+}
+
+func validateHA(customResource *v1beta2.Broker) (*metav1.Condition, bool) {
+	ha := customResource.Spec.HA
+	if ha == nil {
+		return nil, false
+	}
+
+	var message string
+	size := common.GetDeploymentSize(customResource)
+	switch {
+	case ha.Policy != v1beta2.HAPolicies.Replication && ha.Policy != v1beta2.HAPolicies.SharedStore:
+		message = fmt.Sprintf(".Spec.HA.Policy %q is invalid, it must be %q or %q", ha.Policy, v1beta2.HAPolicies.Replication, v1beta2.HAPolicies.SharedStore)
+	case size%2 != 0:
+		message = fmt.Sprintf(".Spec.DeploymentPlan.Size %d is invalid with .Spec.HA, it must be a multiple of 2 to form primary/backup pairs", size)
+	case ha.Policy == v1beta2.HAPolicies.Replication && !isClustered(customResource):
+		message = ".Spec.HA replication requires a clustered deployment, it can not be used with .Spec.DeploymentPlan.Clustered=false or a restricted deployment"
+	case ha.Policy == v1beta2.HAPolicies.SharedStore && ha.SharedStoreClaimName == "":
+		message = ".Spec.HA.SharedStoreClaimName is required by the sharedStore policy"
+	case ha.Policy == v1beta2.HAPolicies.SharedStore && customResource.Spec.DeploymentPlan.PersistenceEnabled:
+		message = ".Spec.HA sharedStore keeps the journal on .Spec.HA.SharedStoreClaimName, it can not be used with .Spec.DeploymentPlan.PersistenceEnabled"
+	}
+
+	if message != "" {
+		return &metav1.Condition{
+			Type:    v1beta2.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta2.ValidConditionFailedInvalidHAConfig,
+			Message: message,
+		}, false
+	}
+	return nil, false
+}
+
+// ProcessHAProperties adds an ordinal specific property file with the HA policy of each broker
+func (r *ActiveMQArtemisReconcilerImpl) ProcessHAProperties(m map[string][]byte) {
+	ha := r.customResource.Spec.HA
+	if ha == nil {
+		return
+	}
+
+	size := int(common.GetDeploymentSize(r.customResource))
+	for ordinal := 0; ordinal < size; ordinal++ {
+		buf := NewPropsWithHeader()
+		for _, property := range haPropertiesFor(r.customResource.Name, ha, ordinal) {
+			fmt.Fprintln(buf, property)
+		}
+		m[fmt.Sprintf("%s%d%s%s", OrdinalPrefix, ordinal, OrdinalPrefixSep, haPropertiesName)] = buf.Bytes()
+	}
+}
+
+func haPropertiesFor(crName string, ha *v1beta2.HAType, ordinal int) []string {
+	pair := haPairOf(ordinal)
+	props := []string{}
+
+	switch ha.Policy {
+	case v1beta2.HAPolicies.Replication:
+		if haIsPrimary(ordinal) {
+			props = append(props,
+				"HAPolicyConfiguration=REPLICATION_PRIMARY_QUORUM_VOTING",
+				"HAPolicyConfiguration.checkForActiveServer=true")
+		} else {
+			props = append(props,
+				"HAPolicyConfiguration=REPLICATION_BACKUP_QUORUM_VOTING",
+				"HAPolicyConfiguration.allowFailBack="+strconv.FormatBool(haAllowFailBack(ha)))
+		}
+		props = append(props, "HAPolicyConfiguration.groupName="+haGroupName(crName, pair))
+
+	case v1beta2.HAPolicies.SharedStore:
+		if haIsPrimary(ordinal) {
+			props = append(props,
+				"HAPolicyConfiguration=SHARED_STORE_PRIMARY",
+				"HAPolicyConfiguration.failoverOnServerShutdown=true")
+		} else {
+			props = append(props,
+				"HAPolicyConfiguration=SHARED_STORE_BACKUP",
+				"HAPolicyConfiguration.allowFailBack="+strconv.FormatBool(haAllowFailBack(ha)))
+		}
+		pairDir := fmt.Sprintf("%s/pair-%d", haSharedStoreMountPath, pair)
+		props = append(props,
+			"persistenceEnabled=true",
+			"journalDirectory="+pairDir+"/journal",
+			"pagingDirectory="+pairDir+"/paging",
+			"bindingsDirectory="+pairDir+"/bindings",
+			"largeMessagesDirectory="+pairDir+"/largemessages")
+	}
+	return props
+}
+
+func haSharedStoreVolumes(customResource *v1beta2.Broker) []corev1.Volume {
+	ha := customResource.Spec.HA
+	if ha == nil || ha.Policy != v1beta2.HAPolicies.SharedStore {
+		return nil
+	}
+	return []corev1.Volume{
+		{
+			Name: haSharedStoreVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: ha.SharedStoreClaimName,
+				},
+			},
+		},
+	}
+}
+
+func haSharedStoreVolumeMounts(customResource *v1beta2.Broker) []corev1.VolumeMount {
+	ha := customResource.Spec.HA
+	if ha == nil || ha.Policy != v1beta2.HAPolicies.SharedStore {
+		return nil
+	}
+	return []corev1.VolumeMount{
+		{
+			Name:      haSharedStoreVolumeName,
+			MountPath: haSharedStoreMountPath,
+		},
+	}
+}
+
+// configureHAAntiAffinity keeps the brokers of the deployment, and so the members of each pair, on different topology domains
+func (reconciler *ActiveMQArtemisReconcilerImpl) configureHAAntiAffinity(podSpec *corev1.PodSpec, customResource *v1beta2.Broker, namer common.Namers) {
+	ha := customResource.Spec.HA
+	if ha == nil || customResource.Spec.DeploymentPlan.Affinity.PodAntiAffinity != nil {
+		return
+	}
+
+	topologyKey := ha.AntiAffinityTopologyKey
+	if topologyKey == "" {
+		topologyKey = haDefaultAntiAffinityTopoKey
+	}
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: namer.LabelBuilder.Labels(),
+		},
+		TopologyKey: topologyKey,
+	}
+
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	reconciler.log.V(1).Info("Adding HA Pod AntiAffinity", "topologyKey", topologyKey, "required", ha.RequiredAntiAffinity)
+	if ha.RequiredAntiAffinity {
+		podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{term},
+		}
+	} else {
+		podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight:          100,
+					PodAffinityTerm: term,
+				},
+			},
+		}
+	}
+}
+
+// configureHAPodManagement starts all ordinals in parallel, a backup never becomes ready
+// so the default ordered policy would block the next pair. The policy is immutable, it
+// can only be set when the statefulset is created.
+func (reconciler *ActiveMQArtemisReconcilerImpl) configureHAPodManagement(customResource *v1beta2.Broker, statefulSet *appsv1.StatefulSet) {
+	if customResource.Spec.HA == nil {
+		return
+	}
+	if statefulSet.ResourceVersion == "" {
+		statefulSet.Spec.PodManagementPolicy = appsv1.ParallelPodManagement
+	} else if statefulSet.Spec.PodManagementPolicy != appsv1.ParallelPodManagement {
+		reconciler.log.V(1).Info("HA pairs deployed with ordered pod management, a backup that is not ready blocks the next ordinal", "statefulset", statefulSet.Name)
+	}
+}
+
+// ProcessHAStatus reports the replication and fail over state of each pair from the brokers
+func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessHAStatus(cr *v1beta2.Broker, client rtclient.Client) (retry bool) {
+	ha := cr.Spec.HA
+	if ha == nil {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, v1beta2.HAReplicatingConditionType)
+		meta.RemoveStatusCondition(&cr.Status.Conditions, v1beta2.HAFailedOverConditionType)
+		return false
+	}
+
+	reconciler.resolveJolokiaEndpoints(cr, client)
+
+	activeBackups := []string{}
+	unsyncedPairs := []string{}
+	unknown := []string{}
+
+	for _, jk := range reconciler.jolokiaEndpoints {
+		ordinal, err := strconv.Atoi(jk.Ordinal)
+		if err != nil {
+			continue
+		}
+		podName := namer.CrToSSOrdinal(cr.Name, ordinal)
+
+		status, err := reconciler.GetAndCacheBrokerStatus(jk)
+		if err != nil {
+			reconciler.log.V(1).Info("unable to retrieve HA state", "pod", podName, "error", err)
+			unknown = append(unknown, podName)
+			continue
+		}
+		state := status.HA
+
+		if haIsPrimary(ordinal) {
+			if !state.Backup && ha.Policy == v1beta2.HAPolicies.Replication && !state.ReplicaSync {
+				unsyncedPairs = append(unsyncedPairs, haGroupName(cr.Name, haPairOf(ordinal)))
+			}
+		} else if !state.Backup {
+			activeBackups = append(activeBackups, podName)
+		}
+	}
+
+	if len(activeBackups) > 0 {
+		meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+			Type:    v1beta2.HAFailedOverConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  v1beta2.HAFailedOverConditionBackupActiveReason,
+			Message: fmt.Sprintf("backup brokers are active: %s", strings.Join(activeBackups, ", ")),
+		})
+	} else {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, v1beta2.HAFailedOverConditionType)
+	}
+
+	if ha.Policy != v1beta2.HAPolicies.Replication {
+		meta.RemoveStatusCondition(&cr.Status.Conditions, v1beta2.HAReplicatingConditionType)
+		return len(unknown) > 0
+	}
+
+	condition := metav1.Condition{
+		Type:   v1beta2.HAReplicatingConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta2.HAReplicatingConditionInSyncReason,
+	}
+	switch {
+	case len(reconciler.jolokiaEndpoints) == 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = v1beta2.HAConditionUnknownReason
+		condition.Message = "Waiting for Jolokia Clients to become available"
+	case len(activeBackups) > 0:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1beta2.HAReplicatingConditionFailedOverReason
+		condition.Message = fmt.Sprintf("pairs have failed over and are running without a replica, active backups: %s", strings.Join(activeBackups, ", "))
+	case len(unknown) > 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = v1beta2.HAConditionUnknownReason
+		condition.Message = fmt.Sprintf("unable to retrieve HA status from: %s", strings.Join(unknown, ", "))
+	case len(unsyncedPairs) > 0:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = v1beta2.HAReplicatingConditionPendingReason
+		condition.Message = fmt.Sprintf("waiting for the backup to synchronize with the primary in: %s", strings.Join(unsyncedPairs, ", "))
+	}
+	meta.SetStatusCondition(&cr.Status.Conditions, condition)
+
+	return condition.Status != metav1.ConditionTrue
+}
@@ -0,0 +1,345 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/adler32"
+	"testing"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	artemis_client "github.com/arkmq-org/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/namer"
+)
+
+func TestValidateHA(t *testing.T) {
+	condition, retry := validateHA(newTestBroker("ha", 2, nil))
+	assert.Nil(t, condition)
+	assert.False(t, retry)
+
+	condition, _ = validateHA(newTestBroker("ha", 2, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.Replication}
+	}))
+	assert.Nil(t, condition)
+
+	condition, _ = validateHA(newTestBroker("ha", 3, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.Replication}
+	}))
+	assert.NotNil(t, condition)
+	assert.Equal(t, v1beta2.ValidConditionFailedInvalidHAConfig, condition.Reason)
+	assert.Contains(t, condition.Message, "multiple of 2")
+
+	clustered := false
+	cr := newTestBroker("ha", 2, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.Replication}
+	})
+	cr.Spec.DeploymentPlan.Clustered = &clustered
+	condition, _ = validateHA(cr)
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "clustered")
+
+	condition, _ = validateHA(newTestBroker("ha", 2, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.SharedStore}
+	}))
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "SharedStoreClaimName")
+
+	condition, _ = validateHA(newTestBroker("ha", 4, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.SharedStore, SharedStoreClaimName: "shared"}
+	}))
+	assert.Nil(t, condition)
+}
+
+func TestProcessHAPropertiesReplication(t *testing.T) {
+	allowFailBack := false
+	cr := newTestBroker("ha", 4, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.Replication, AllowFailBack: &allowFailBack}
+	})
+
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	data := BrokerPropertiesData(nil)
+	ri.ProcessHAProperties(data)
+
+	assert.Len(t, data, 5)
+	assert.True(t, hasOrdinalPropertieKeyInData(data))
+
+	primary := string(data["broker-2.ha.properties"])
+	assert.Contains(t, primary, "HAPolicyConfiguration=REPLICATION_PRIMARY_QUORUM_VOTING\n")
+	assert.Contains(t, primary, "HAPolicyConfiguration.groupName=ha-pair-1\n")
+
+	backup := string(data["broker-3.ha.properties"])
+	assert.Contains(t, backup, "HAPolicyConfiguration=REPLICATION_BACKUP_QUORUM_VOTING\n")
+	assert.Contains(t, backup, "HAPolicyConfiguration.groupName=ha-pair-1\n")
+	assert.Contains(t, backup, "HAPolicyConfiguration.allowFailBack=false\n")
+}
+
+func TestProcessHAPropertiesSharedStore(t *testing.T) {
+	cr := newTestBroker("ha", 2, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.SharedStore, SharedStoreClaimName: "shared"}
+	})
+
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	data := map[string][]byte{}
+	ri.ProcessHAProperties(data)
+
+	primary := string(data["broker-0.ha.properties"])
+	assert.Contains(t, primary, "HAPolicyConfiguration=SHARED_STORE_PRIMARY\n")
+	assert.Contains(t, primary, "journalDirectory="+haSharedStoreMountPath+"/pair-0/journal\n")
+
+	backup := string(data["broker-1.ha.properties"])
+	assert.Contains(t, backup, "HAPolicyConfiguration=SHARED_STORE_BACKUP\n")
+	assert.Contains(t, backup, "HAPolicyConfiguration.allowFailBack=true\n")
+	assert.Contains(t, backup, "journalDirectory="+haSharedStoreMountPath+"/pair-0/journal\n")
+
+	volumes := haSharedStoreVolumes(cr)
+	assert.Len(t, volumes, 1)
+	assert.Equal(t, "shared", volumes[0].PersistentVolumeClaim.ClaimName)
+
+	mounts := haSharedStoreVolumeMounts(cr)
+	assert.Len(t, mounts, 1)
+	assert.Equal(t, haSharedStoreMountPath, mounts[0].MountPath)
+}
+
+func TestConfigureHAAntiAffinity(t *testing.T) {
+	cr := newTestBroker("ha", 2, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.Replication, AntiAffinityTopologyKey: "topology.kubernetes.io/zone"}
+	})
+	namer := MakeNamers(cr)
+
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	podSpec := &corev1.PodSpec{}
+	ri.configureHAAntiAffinity(podSpec, cr, *namer)
+
+	assert.NotNil(t, podSpec.Affinity.PodAntiAffinity)
+	terms := podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, terms, 1)
+	assert.Equal(t, "topology.kubernetes.io/zone", terms[0].PodAffinityTerm.TopologyKey)
+	assert.Equal(t, namer.LabelBuilder.Labels(), terms[0].PodAffinityTerm.LabelSelector.MatchLabels)
+
+	cr.Spec.HA.RequiredAntiAffinity = true
+	cr.Spec.HA.AntiAffinityTopologyKey = ""
+	podSpec = &corev1.PodSpec{}
+	ri.configureHAAntiAffinity(podSpec, cr, *namer)
+	assert.Len(t, podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, 1)
+	assert.Equal(t, haDefaultAntiAffinityTopoKey, podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey)
+
+	// user provided anti affinity is respected
+	cr.Spec.DeploymentPlan.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	podSpec = &corev1.PodSpec{}
+	ri.configureHAAntiAffinity(podSpec, cr, *namer)
+	assert.Nil(t, podSpec.Affinity)
+}
+
+func TestConfigureHAPodManagement(t *testing.T) {
+	cr := newTestBroker("ha", 2, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.Replication}
+	})
+
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	ss := &appsv1.StatefulSet{}
+	ri.configureHAPodManagement(cr, ss)
+	assert.Equal(t, appsv1.ParallelPodManagement, ss.Spec.PodManagementPolicy)
+
+	deployed := &appsv1.StatefulSet{}
+	deployed.ResourceVersion = "1"
+	deployed.Spec.PodManagementPolicy = appsv1.OrderedReadyPodManagement
+	ri.configureHAPodManagement(cr, deployed)
+	assert.Equal(t, appsv1.OrderedReadyPodManagement, deployed.Spec.PodManagementPolicy)
+}
+
+func haMockEndpoint(mockCtrl *gomock.Controller, ordinal int, backup string, replicaSync string) *jolokia_client.JkInfo {
+	j := jolokia.NewMockIJolokia(mockCtrl)
+	respond := func(requests []jolokia.Request) ([]*jolokia.ResponseData, error) {
+		if backup == "" {
+			return nil, fmt.Errorf("connection refused")
+		}
+		if replicaSync == "" {
+			replicaSync = "false"
+		}
+		value := fmt.Sprintf(`{"Status":"{}","Backup":%s,"ReplicaSync":%s}`, backup, replicaSync)
+		return []*jolokia.ResponseData{{Status: 200, RawValue: []byte(value)}}, nil
+	}
+	j.EXPECT().Bulk(gomock.Any()).DoAndReturn(respond).AnyTimes()
+	return &jolokia_client.JkInfo{
+		Artemis: artemis_client.GetArtemisWithJolokia(j, "ha"),
+		IP:      "IP",
+		Ordinal: fmt.Sprint(ordinal),
+	}
+}
+
+func TestProcessHAStatus(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cr := newTestBroker("ha", 4, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.Replication}
+	})
+
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	// in sync
+	ri.jolokiaEndpoints = []*jolokia_client.JkInfo{
+		haMockEndpoint(mockCtrl, 0, "false", "true"),
+		haMockEndpoint(mockCtrl, 1, "true", ""),
+		haMockEndpoint(mockCtrl, 2, "false", "true"),
+		haMockEndpoint(mockCtrl, 3, "true", ""),
+	}
+	assert.False(t, ri.ProcessHAStatus(cr, nil))
+	assert.True(t, meta.IsStatusConditionTrue(cr.Status.Conditions, v1beta2.HAReplicatingConditionType))
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, v1beta2.HAFailedOverConditionType))
+
+	// pending sync
+	ri.cachedBrokerStatus = map[string]any{}
+	ri.jolokiaEndpoints[2] = haMockEndpoint(mockCtrl, 2, "false", "false")
+	assert.True(t, ri.ProcessHAStatus(cr, nil))
+	condition := meta.FindStatusCondition(cr.Status.Conditions, v1beta2.HAReplicatingConditionType)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, v1beta2.HAReplicatingConditionPendingReason, condition.Reason)
+	assert.Contains(t, condition.Message, "ha-pair-1")
+
+	// primary gone, backup live
+	ri.cachedBrokerStatus = map[string]any{}
+	ri.jolokiaEndpoints = []*jolokia_client.JkInfo{
+		haMockEndpoint(mockCtrl, 1, "false", ""),
+		haMockEndpoint(mockCtrl, 2, "false", "true"),
+		haMockEndpoint(mockCtrl, 3, "true", ""),
+	}
+	assert.True(t, ri.ProcessHAStatus(cr, nil))
+	condition = meta.FindStatusCondition(cr.Status.Conditions, v1beta2.HAFailedOverConditionType)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, v1beta2.HAFailedOverConditionBackupActiveReason, condition.Reason)
+	assert.Contains(t, condition.Message, "ha-ss-1")
+	condition = meta.FindStatusCondition(cr.Status.Conditions, v1beta2.HAReplicatingConditionType)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1beta2.HAReplicatingConditionFailedOverReason, condition.Reason)
+
+	// ha removed
+	cr.Spec.HA = nil
+	assert.False(t, ri.ProcessHAStatus(cr, nil))
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, v1beta2.HAReplicatingConditionType))
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, v1beta2.HAFailedOverConditionType))
+}
+
+// haReadyEndpoint reports the broker properties of the props secret as applied, with the HA role of the ordinal
+func haReadyEndpoint(t *testing.T, mockCtrl *gomock.Controller, client rtclient.Client, cr *v1beta2.Broker, ordinal int) *jolokia_client.JkInfo {
+	j := jolokia.NewMockIJolokia(mockCtrl)
+	respond := func(requests []jolokia.Request) ([]*jolokia.ResponseData, error) {
+		secret := &corev1.Secret{}
+		if err := client.Get(context.TODO(), getPropertiesResourceNsName(cr), secret); err != nil {
+			return nil, err
+		}
+		status := brokerStatus{BrokerConfigStatus: brokerConfigStatus{PropertiesStatus: map[string]propertiesStatus{}}}
+		status.ServerStatus.Version, _ = common.ResolveBrokerVersionFromCR(cr)
+		for name, data := range secret.Data {
+			status.BrokerConfigStatus.PropertiesStatus[name] = propertiesStatus{FileAlder32: fmt.Sprint(adler32.Checksum(data))}
+		}
+		statusJson, err := json.Marshal(status)
+		assert.NoError(t, err)
+		value, err := json.Marshal(map[string]any{"Status": string(statusJson), "Backup": !haIsPrimary(ordinal), "ReplicaSync": haIsPrimary(ordinal)})
+		assert.NoError(t, err)
+		return []*jolokia.ResponseData{{Status: 200, RawValue: value}}, nil
+	}
+	j.EXPECT().Bulk(gomock.Any()).DoAndReturn(respond).AnyTimes()
+	return &jolokia_client.JkInfo{
+		Artemis: artemis_client.GetArtemisWithJolokia(j, cr.Name),
+		IP:      namer.CrToSSOrdinal(cr.Name, ordinal),
+		Ordinal: fmt.Sprint(ordinal),
+	}
+}
+
+func TestHABrokerReady(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	broker := newTestBroker("ha", 2, func(candidate *v1beta2.Broker) {
+		candidate.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.Replication}
+	})
+	broker.UID = types.UID("ha-uid")
+	scheme := renderScheme()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(broker).WithStatusSubresource(broker).Build()
+	reconciler := &BrokerReconciler{Client: client, Scheme: scheme, log: ctrl.Log}
+	brokerKey := types.NamespacedName{Name: broker.Name, Namespace: broker.Namespace}
+
+	// the primary is ready, the backup replicates and is not ready till it fails over
+	for ordinal, ready := range []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: namer.CrToSSOrdinal(broker.Name, ordinal), Namespace: broker.Namespace},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}},
+		}
+		assert.NoError(t, client.Create(context.TODO(), pod))
+	}
+
+	reconciler.getEndpoints = func(cr *v1beta2.Broker, c rtclient.Client) []*jolokia_client.JkInfo {
+		return []*jolokia_client.JkInfo{
+			haReadyEndpoint(t, mockCtrl, c, cr, 0),
+			haReadyEndpoint(t, mockCtrl, c, cr, 1),
+		}
+	}
+
+	current := &v1beta2.Broker{}
+	for i := 0; i < 3; i++ {
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: brokerKey})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, client.Get(context.TODO(), brokerKey, current))
+
+	condition := meta.FindStatusCondition(current.Status.Conditions, v1beta2.DeployedConditionType)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionTrue, condition.Status, condition.Message)
+	}
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, v1beta2.ConfigAppliedConditionType))
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, v1beta2.HAReplicatingConditionType))
+	assert.Nil(t, meta.FindStatusCondition(current.Status.Conditions, v1beta2.HAFailedOverConditionType))
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, v1beta2.ReadyConditionType), "%v", current.Status.Conditions)
+
+	// the pair is not deployed without a ready broker
+	primary := &corev1.Pod{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: namer.CrToSSOrdinal(broker.Name, 0), Namespace: broker.Namespace}, primary))
+	primary.Status.Conditions[0].Status = corev1.ConditionFalse
+	assert.NoError(t, client.Status().Update(context.TODO(), primary))
+	_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: brokerKey})
+	assert.NoError(t, err)
+	assert.NoError(t, client.Get(context.TODO(), brokerKey, current))
+	condition = meta.FindStatusCondition(current.Status.Conditions, v1beta2.DeployedConditionType)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "0/1 primary/backup pairs ready")
+	}
+}
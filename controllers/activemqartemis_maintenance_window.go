@@ -0,0 +1,240 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	// the operator image may not provide the time zone database
+	_ "time/tzdata"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+)
+
+type maintenanceSchedule struct {
+	days     map[time.Weekday]bool
+	hour     int
+	minute   int
+	duration time.Duration
+	location *time.Location
+}
+
+func parseMaintenanceWindow(window *v1beta2.MaintenanceWindowType) (*maintenanceSchedule, error) {
+	schedule := &maintenanceSchedule{days: map[time.Weekday]bool{}, location: time.UTC}
+
+	start, err := time.Parse("15:04", window.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("StartTime %q is invalid, it must use the HH:MM format", window.StartTime)
+	}
+	schedule.hour, schedule.minute = start.Hour(), start.Minute()
+
+	schedule.duration, err = time.ParseDuration(window.Duration)
+	if err != nil || schedule.duration <= 0 {
+		return nil, fmt.Errorf("Duration %q is invalid, it must be a positive duration like 2h or 90m", window.Duration)
+	}
+	if schedule.duration > 7*24*time.Hour {
+		return nil, fmt.Errorf("Duration %q is invalid, it must not be longer than a week", window.Duration)
+	}
+
+	if window.TimeZone != "" {
+		if schedule.location, err = time.LoadLocation(window.TimeZone); err != nil {
+			return nil, fmt.Errorf("TimeZone %q is invalid, %v", window.TimeZone, err)
+		}
+	}
+
+	for _, day := range window.Days {
+		found := false
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			if string(day) == weekday.String() {
+				schedule.days[weekday] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Days contains %q, it must be a day of the week like Monday", day)
+		}
+	}
+	return schedule, nil
+}
+
+// the opening of the window on the day of the given time
+func (schedule *maintenanceSchedule) openingOn(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), schedule.hour, schedule.minute, 0, 0, schedule.location)
+}
+
+func (schedule *maintenanceSchedule) opensOn(weekday time.Weekday) bool {
+	return len(schedule.days) == 0 || schedule.days[weekday]
+}
+
+func (schedule *maintenanceSchedule) isOpen(now time.Time) bool {
+	now = now.In(schedule.location)
+	// a window that opened on a previous day can still be open
+	for daysAgo := 0; daysAgo <= int(schedule.duration/(24*time.Hour))+1; daysAgo++ {
+		opening := schedule.openingOn(now.AddDate(0, 0, -daysAgo))
+		if schedule.opensOn(opening.Weekday()) && !now.Before(opening) && now.Before(opening.Add(schedule.duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (schedule *maintenanceSchedule) nextOpening(now time.Time) time.Time {
+	now = now.In(schedule.location)
+	for days := 0; days <= 7; days++ {
+		opening := schedule.openingOn(now.AddDate(0, 0, days))
+		if schedule.opensOn(opening.Weekday()) && opening.After(now) {
+			return opening
+		}
+	}
+	return time.Time{}
+}
+
+func validateMaintenanceWindow(customResource *v1beta2.Broker) (*metav1.Condition, bool) {
+	window := customResource.Spec.MaintenanceWindow
+	if window == nil {
+		return nil, false
+	}
+
+	if _, err := parseMaintenanceWindow(window); err != nil {
+		return &metav1.Condition{
+			Type:    v1beta2.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta2.ValidConditionFailedInvalidMaintenanceWindow,
+			Message: ".Spec.MaintenanceWindow." + err.Error(),
+		}, false
+	}
+	return nil, false
+}
+
+// holdPodTemplateChanges keeps the deployed pod template when the desired template would restart the
+// brokers outside of the maintenance window, the other changes to the statefulset still apply
+func (reconciler *ActiveMQArtemisReconcilerImpl) holdPodTemplateChanges(customResource *v1beta2.Broker, desired *appsv1.StatefulSet, now time.Time) {
+	window := customResource.Spec.MaintenanceWindow
+	if window == nil {
+		meta.RemoveStatusCondition(&customResource.Status.Conditions, v1beta2.PendingRestartConditionType)
+		return
+	}
+
+	obj := reconciler.getFromDeployed(reflect.TypeOf(appsv1.StatefulSet{}), desired.Name)
+	if obj == nil {
+		meta.RemoveStatusCondition(&customResource.Status.Conditions, v1beta2.PendingRestartConditionType)
+		return
+	}
+	deployed := obj.(*appsv1.StatefulSet)
+
+	if equality.Semantic.DeepEqual(deployed.Spec.Template, desired.Spec.Template) {
+		meta.RemoveStatusCondition(&customResource.Status.Conditions, v1beta2.PendingRestartConditionType)
+		return
+	}
+
+	schedule, err := parseMaintenanceWindow(window)
+	if err != nil || schedule.isOpen(now) {
+		meta.RemoveStatusCondition(&customResource.Status.Conditions, v1beta2.PendingRestartConditionType)
+		return
+	}
+
+	changes := podTemplateDiff(&deployed.Spec.Template, &desired.Spec.Template)
+	reconciler.log.V(1).Info("holding pod template changes till the maintenance window", "changes", changes)
+
+	desired.Spec.Template = *deployed.Spec.Template.DeepCopy()
+
+	meta.SetStatusCondition(&customResource.Status.Conditions, metav1.Condition{
+		Type:   v1beta2.PendingRestartConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta2.PendingRestartConditionOutsideWindowReason,
+		Message: fmt.Sprintf("changes that restart the brokers are held until the maintenance window opens at %s: %s",
+			schedule.nextOpening(now).Format(time.RFC3339), strings.Join(changes, ", ")),
+	})
+}
+
+// podTemplateDiff lists the paths of the pod template fields that differ, containers are matched by name
+func podTemplateDiff(deployed *corev1.PodTemplateSpec, desired *corev1.PodTemplateSpec) []string {
+	changes := []string{}
+
+	if !equality.Semantic.DeepEqual(deployed.Labels, desired.Labels) {
+		changes = append(changes, "metadata.labels")
+	}
+	if !equality.Semantic.DeepEqual(deployed.Annotations, desired.Annotations) {
+		changes = append(changes, "metadata.annotations")
+	}
+
+	deployedSpec := reflect.ValueOf(deployed.Spec)
+	desiredSpec := reflect.ValueOf(desired.Spec)
+	for i := 0; i < deployedSpec.NumField(); i++ {
+		field := deployedSpec.Type().Field(i)
+		switch field.Name {
+		case "Containers":
+			changes = append(changes, containersDiff("spec.containers", deployed.Spec.Containers, desired.Spec.Containers)...)
+		case "InitContainers":
+			changes = append(changes, containersDiff("spec.initContainers", deployed.Spec.InitContainers, desired.Spec.InitContainers)...)
+		default:
+			if !equality.Semantic.DeepEqual(deployedSpec.Field(i).Interface(), desiredSpec.Field(i).Interface()) {
+				changes = append(changes, "spec."+jsonFieldName(field))
+			}
+		}
+	}
+	return changes
+}
+
+func containersDiff(path string, deployed []corev1.Container, desired []corev1.Container) []string {
+	changes := []string{}
+
+	deployedByName := map[string]*corev1.Container{}
+	for i := range deployed {
+		deployedByName[deployed[i].Name] = &deployed[i]
+	}
+
+	for i := range desired {
+		container := &desired[i]
+		current, found := deployedByName[container.Name]
+		if !found {
+			changes = append(changes, fmt.Sprintf("%s[%s]", path, container.Name))
+			continue
+		}
+		delete(deployedByName, container.Name)
+
+		currentValue := reflect.ValueOf(*current)
+		desiredValue := reflect.ValueOf(*container)
+		for f := 0; f < currentValue.NumField(); f++ {
+			if !equality.Semantic.DeepEqual(currentValue.Field(f).Interface(), desiredValue.Field(f).Interface()) {
+				changes = append(changes, fmt.Sprintf("%s[%s].%s", path, container.Name, jsonFieldName(currentValue.Type().Field(f))))
+			}
+		}
+	}
+
+	removed := []string{}
+	for name := range deployedByName {
+		removed = append(removed, fmt.Sprintf("%s[%s]", path, name))
+	}
+	sort.Strings(removed)
+	return append(changes, removed...)
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
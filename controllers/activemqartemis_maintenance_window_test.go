@@ -0,0 +1,151 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestMaintenanceScheduleIsOpen(t *testing.T) {
+	schedule, err := parseMaintenanceWindow(&v1beta2.MaintenanceWindowType{
+		Days:      []v1beta2.MaintenanceWindowDay{"Saturday"},
+		StartTime: "23:00",
+		Duration:  "3h",
+		TimeZone:  "Europe/Rome",
+	})
+	assert.NoError(t, err)
+
+	rome, _ := time.LoadLocation("Europe/Rome")
+	// 2026-10-17 is a Saturday
+	assert.False(t, schedule.isOpen(time.Date(2026, 10, 17, 22, 59, 0, 0, rome)))
+	assert.True(t, schedule.isOpen(time.Date(2026, 10, 17, 23, 0, 0, 0, rome)))
+	assert.True(t, schedule.isOpen(time.Date(2026, 10, 18, 1, 30, 0, 0, rome)))
+	assert.False(t, schedule.isOpen(time.Date(2026, 10, 18, 2, 0, 0, 0, rome)))
+	assert.False(t, schedule.isOpen(time.Date(2026, 10, 16, 23, 30, 0, 0, rome)))
+	assert.True(t, schedule.isOpen(time.Date(2026, 10, 17, 21, 30, 0, 0, time.UTC)))
+
+	assert.Equal(t, time.Date(2026, 10, 17, 23, 0, 0, 0, rome), schedule.nextOpening(time.Date(2026, 10, 12, 9, 0, 0, 0, rome)))
+	assert.Equal(t, time.Date(2026, 10, 24, 23, 0, 0, 0, rome), schedule.nextOpening(time.Date(2026, 10, 17, 23, 30, 0, 0, rome)))
+
+	daily, err := parseMaintenanceWindow(&v1beta2.MaintenanceWindowType{StartTime: "02:00", Duration: "1h"})
+	assert.NoError(t, err)
+	assert.True(t, daily.isOpen(time.Date(2026, 10, 14, 2, 30, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2026, 10, 15, 2, 0, 0, 0, time.UTC), daily.nextOpening(time.Date(2026, 10, 14, 2, 30, 0, 0, time.UTC)))
+}
+
+func TestValidateMaintenanceWindow(t *testing.T) {
+	cr := &v1beta2.Broker{}
+	condition, retry := validateMaintenanceWindow(cr)
+	assert.Nil(t, condition)
+	assert.False(t, retry)
+
+	cr.Spec.MaintenanceWindow = &v1beta2.MaintenanceWindowType{StartTime: "02:00", Duration: "2h", TimeZone: "America/New_York"}
+	condition, _ = validateMaintenanceWindow(cr)
+	assert.Nil(t, condition)
+
+	for _, window := range []v1beta2.MaintenanceWindowType{
+		{StartTime: "2am", Duration: "2h"},
+		{StartTime: "02:00", Duration: "two hours"},
+		{StartTime: "02:00", Duration: "-1h"},
+		{StartTime: "02:00", Duration: "200h"},
+		{StartTime: "02:00", Duration: "2h", TimeZone: "Nowhere/Atlantis"},
+		{StartTime: "02:00", Duration: "2h", Days: []v1beta2.MaintenanceWindowDay{"Caturday"}},
+	} {
+		cr.Spec.MaintenanceWindow = &window
+		condition, _ = validateMaintenanceWindow(cr)
+		assert.NotNil(t, condition, "window %v", window)
+		assert.Equal(t, v1beta2.ValidConditionFailedInvalidMaintenanceWindow, condition.Reason)
+		assert.Contains(t, condition.Message, ".Spec.MaintenanceWindow.")
+	}
+}
+
+func TestHoldPodTemplateChanges(t *testing.T) {
+	cr := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "mw", Namespace: "test"},
+		Spec: v1beta2.BrokerSpec{
+			MaintenanceWindow: &v1beta2.MaintenanceWindowType{StartTime: "02:00", Duration: "1h"},
+		},
+	}
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	deployed := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "mw-ss", Namespace: "test"}}
+	deployed.Spec.Template.Spec.Containers = []corev1.Container{{Name: "mw-container", Image: "broker:1"}}
+
+	desired := deployed.DeepCopy()
+	replicas := int32(2)
+	desired.Spec.Replicas = &replicas
+	desired.Spec.Template.Spec.Containers[0].Image = "broker:2"
+
+	outside := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	inside := time.Date(2026, 10, 15, 2, 30, 0, 0, time.UTC)
+
+	// first deployment
+	ri.deployed = make(map[reflect.Type][]client.Object)
+	ri.holdPodTemplateChanges(cr, desired, outside)
+	assert.Equal(t, "broker:2", desired.Spec.Template.Spec.Containers[0].Image)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, v1beta2.PendingRestartConditionType))
+
+	// held outside the window, the size still applies
+	ri.addToDeployed(reflect.TypeOf(appsv1.StatefulSet{}), deployed)
+	ri.holdPodTemplateChanges(cr, desired, outside)
+	assert.Equal(t, "broker:1", desired.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(2), *desired.Spec.Replicas)
+	condition := meta.FindStatusCondition(cr.Status.Conditions, v1beta2.PendingRestartConditionType)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, v1beta2.PendingRestartConditionOutsideWindowReason, condition.Reason)
+	assert.Contains(t, condition.Message, "2026-10-15T02:00:00Z")
+	assert.Contains(t, condition.Message, "spec.containers[mw-container].image")
+
+	// applied inside the window
+	desired.Spec.Template.Spec.Containers[0].Image = "broker:2"
+	ri.holdPodTemplateChanges(cr, desired, inside)
+	assert.Equal(t, "broker:2", desired.Spec.Template.Spec.Containers[0].Image)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, v1beta2.PendingRestartConditionType))
+}
+
+func TestPodTemplateDiff(t *testing.T) {
+	deployed := &corev1.PodTemplateSpec{}
+	deployed.Spec.Containers = []corev1.Container{{Name: "a", Image: "broker:1"}, {Name: "b"}}
+
+	desired := deployed.DeepCopy()
+	assert.Empty(t, podTemplateDiff(deployed, desired))
+
+	desired.Annotations = map[string]string{"k": "v"}
+	desired.Spec.Containers = []corev1.Container{{Name: "a", Image: "broker:1", Env: []corev1.EnvVar{{Name: "E", Value: "v"}}}, {Name: "c"}}
+	desired.Spec.Volumes = []corev1.Volume{{Name: "v"}}
+	desired.Spec.InitContainers = []corev1.Container{{Name: "init"}}
+
+	assert.Equal(t, []string{
+		"metadata.annotations",
+		"spec.volumes",
+		"spec.initContainers[init]",
+		"spec.containers[a].env",
+		"spec.containers[c]",
+		"spec.containers[b]",
+	}, podTemplateDiff(deployed, desired))
+}
@@ -0,0 +1,258 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	brokerv1beta1 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+const migrateToBrokerDryRun = "dry-run"
+
+// isMigrationRequested is true when the migrate annotation asks for a dry run or a migration
+func isMigrationRequested(artemis *brokerv1beta1.ActiveMQArtemis) (mode string, requested bool) {
+	mode, present := artemis.Annotations[common.MigrateToBrokerAnnotation]
+	if !present {
+		return "", false
+	}
+	if mode == migrateToBrokerDryRun {
+		return mode, true
+	}
+	migrate, err := strconv.ParseBool(mode)
+	return mode, err == nil && migrate
+}
+
+// unconvertedFields lists the spec fields of an ActiveMQArtemis that are lost by its conversion to a Broker
+func unconvertedFields(artemis *brokerv1beta1.ActiveMQArtemis) ([]string, error) {
+	broker, err := ConvertArtemisToBroker(artemis)
+	if err != nil {
+		return nil, err
+	}
+	from, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&artemis.Spec)
+	if err != nil {
+		return nil, err
+	}
+	to, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&broker.Spec)
+	if err != nil {
+		return nil, err
+	}
+	fields := []string{}
+	compareConvertedFields("spec", from, to, &fields)
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func compareConvertedFields(path string, from interface{}, to interface{}, fields *[]string) {
+	switch fromValue := from.(type) {
+	case map[string]interface{}:
+		toValue, _ := to.(map[string]interface{})
+		for key, value := range fromValue {
+			compareConvertedFields(path+"."+key, value, toValue[key], fields)
+		}
+	case []interface{}:
+		toValue, _ := to.([]interface{})
+		if len(fromValue) != len(toValue) {
+			*fields = append(*fields, path)
+			return
+		}
+		for i := range fromValue {
+			compareConvertedFields(fmt.Sprintf("%s[%d]", path, i), fromValue[i], toValue[i], fields)
+		}
+	default:
+		if !reflect.DeepEqual(from, to) {
+			*fields = append(*fields, path)
+		}
+	}
+}
+
+// newMigrationBroker is the Broker that replaces an ActiveMQArtemis, its reconcile stays blocked until it owns the
+// resources of the ActiveMQArtemis
+func newMigrationBroker(artemis *brokerv1beta1.ActiveMQArtemis) (*v1beta2.Broker, error) {
+	converted, err := ConvertArtemisToBroker(artemis)
+	if err != nil {
+		return nil, err
+	}
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        artemis.Name,
+			Namespace:   artemis.Namespace,
+			Labels:      artemis.Labels,
+			Annotations: map[string]string{},
+		},
+		Spec: converted.Spec,
+	}
+	for key, value := range artemis.Annotations {
+		if key != common.MigrateToBrokerAnnotation && key != corev1.LastAppliedConfigAnnotation {
+			broker.Annotations[key] = value
+		}
+	}
+	broker.Annotations[common.MigratedFromAnnotation] = string(artemis.UID)
+	if _, blocked := artemis.Annotations[common.BlockReconcileAnnotation]; !blocked {
+		broker.Annotations[common.BlockReconcileAnnotation] = "true"
+	}
+	return broker, nil
+}
+
+// migrationOwnedResources lists the resources owned by an ActiveMQArtemis, the pods are owned by the StatefulSet
+func (r *ActiveMQArtemisReconciler) migrationOwnedResources(artemis *brokerv1beta1.ActiveMQArtemis) ([]rtclient.Object, error) {
+	converted, err := ConvertArtemisToBroker(artemis)
+	if err != nil {
+		return nil, err
+	}
+	deployed, err := common.GetDeployedResources(converted, r.Client, r.isOnOpenShift)
+	if err != nil {
+		return nil, err
+	}
+	owned := []rtclient.Object{}
+	for _, objs := range deployed {
+		owned = append(owned, objs...)
+	}
+
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	if err := r.List(context.TODO(), pvcs, rtclient.InNamespace(artemis.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range pvcs.Items {
+		for _, ref := range pvcs.Items[i].OwnerReferences {
+			if ref.UID == artemis.UID {
+				owned = append(owned, &pvcs.Items[i])
+				break
+			}
+		}
+	}
+	return owned, nil
+}
+
+// migrateToBroker reports on a dry run or moves an ActiveMQArtemis to a Broker with the same name, it returns true once
+// migrated, the ActiveMQArtemis is not reconciled anymore
+func (r *ActiveMQArtemisReconciler) migrateToBroker(artemis *brokerv1beta1.ActiveMQArtemis, mode string) (bool, error) {
+	fields, err := unconvertedFields(artemis)
+	if err != nil {
+		return false, err
+	}
+
+	broker := &v1beta2.Broker{}
+	brokerExists := true
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: artemis.Name, Namespace: artemis.Namespace}, broker); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		brokerExists = false
+	}
+
+	condition := metav1.Condition{
+		Type:               brokerv1beta1.MigratedConditionType,
+		Status:             metav1.ConditionUnknown,
+		ObservedGeneration: artemis.Generation,
+	}
+	if len(fields) > 0 {
+		condition.Reason = brokerv1beta1.MigratedConditionNotConvertibleReason
+		condition.Message = fmt.Sprintf("unable to convert %s to a Broker", strings.Join(fields, ", "))
+	} else if brokerExists && broker.Annotations[common.MigratedFromAnnotation] != string(artemis.UID) {
+		condition.Reason = brokerv1beta1.MigratedConditionBrokerExistsReason
+		condition.Message = fmt.Sprintf("a Broker named %s already exists", broker.Name)
+	} else if mode == migrateToBrokerDryRun {
+		owned, err := r.migrationOwnedResources(artemis)
+		if err != nil {
+			return false, err
+		}
+		condition.Reason = brokerv1beta1.MigratedConditionDryRunReason
+		condition.Message = fmt.Sprintf("all the fields convert to a Broker, the ownership of %d resources would be transferred", len(owned))
+	} else {
+		return true, r.completeMigration(artemis, broker, brokerExists)
+	}
+	meta.SetStatusCondition(&artemis.Status.Conditions, condition)
+	return false, nil
+}
+
+// completeMigration is repeatable, a failure is retried by the next reconcile of the ActiveMQArtemis
+func (r *ActiveMQArtemisReconciler) completeMigration(artemis *brokerv1beta1.ActiveMQArtemis, broker *v1beta2.Broker, brokerExists bool) error {
+	reqLogger := r.log.WithValues("ActiveMQArtemis", artemis.Name, "Namespace", artemis.Namespace)
+
+	if !brokerExists {
+		migrationBroker, err := newMigrationBroker(artemis)
+		if err != nil {
+			return err
+		}
+		if err := r.Create(context.TODO(), migrationBroker); err != nil {
+			return err
+		}
+		broker = migrationBroker
+		reqLogger.V(1).Info("created Broker for migration")
+	}
+
+	owned, err := r.migrationOwnedResources(artemis)
+	if err != nil {
+		return err
+	}
+	// the resources keep their names and content, only the owner changes so the pods do not restart
+	for _, obj := range owned {
+		refs := obj.GetOwnerReferences()
+		for i := range refs {
+			if refs[i].UID == artemis.UID {
+				refs[i].APIVersion = v1beta2.GroupVersion.String()
+				refs[i].Kind = "Broker"
+				refs[i].Name = broker.Name
+				refs[i].UID = broker.UID
+			}
+		}
+		obj.SetOwnerReferences(refs)
+		if err := r.Update(context.TODO(), obj); err != nil {
+			return err
+		}
+		reqLogger.V(1).Info("transferred ownership to Broker", "resource", obj.GetName(), "type", reflect.TypeOf(obj).String())
+	}
+
+	if _, blocked := artemis.Annotations[common.BlockReconcileAnnotation]; !blocked {
+		if _, blocked := broker.Annotations[common.BlockReconcileAnnotation]; blocked {
+			delete(broker.Annotations, common.BlockReconcileAnnotation)
+			if err := r.Update(context.TODO(), broker); err != nil {
+				return err
+			}
+		}
+	}
+
+	delete(artemis.Annotations, common.MigrateToBrokerAnnotation)
+	artemis.Annotations[common.MigratedToBrokerAnnotation] = broker.Name
+	if err := r.Update(context.TODO(), artemis); err != nil {
+		return err
+	}
+	meta.SetStatusCondition(&artemis.Status.Conditions, metav1.Condition{
+		Type:               brokerv1beta1.MigratedConditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             brokerv1beta1.MigratedConditionSuccessReason,
+		Message:            fmt.Sprintf("migrated to Broker %s", broker.Name),
+		ObservedGeneration: artemis.Generation,
+	})
+	reqLogger.Info("migrated to Broker")
+	return resources.UpdateStatus(r.Client, artemis)
+}
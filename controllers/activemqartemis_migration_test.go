@@ -0,0 +1,164 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	brokerv1beta1 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func migrationArtemis(mode string) *brokerv1beta1.ActiveMQArtemis {
+	size := int32(2)
+	return &brokerv1beta1.ActiveMQArtemis{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ex-aao",
+			Namespace:   "test",
+			UID:         "artemis-uid",
+			Labels:      map[string]string{"team": "orders"},
+			Annotations: map[string]string{common.MigrateToBrokerAnnotation: mode},
+		},
+		Spec: brokerv1beta1.ActiveMQArtemisSpec{
+			DeploymentPlan: brokerv1beta1.DeploymentPlanType{Size: &size, PersistenceEnabled: true},
+			Acceptors:      []brokerv1beta1.AcceptorType{{Name: "amqp", Port: 5672, Protocols: "amqp"}},
+			BrokerProperties: []string{
+				"addressConfigurations.orders.routingTypes=ANYCAST",
+			},
+		},
+	}
+}
+
+func ownedByArtemis(obj client.Object) client.Object {
+	isController := true
+	obj.SetNamespace("test")
+	obj.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: brokerv1beta1.GroupVersion.String(),
+		Kind:       "ActiveMQArtemis",
+		Name:       "ex-aao",
+		UID:        "artemis-uid",
+		Controller: &isController,
+	}})
+	return obj
+}
+
+func TestUnconvertedFields(t *testing.T) {
+	fields, err := unconvertedFields(migrationArtemis("true"))
+	assert.NoError(t, err)
+	assert.Empty(t, fields)
+
+	fields = []string{}
+	compareConvertedFields("spec",
+		map[string]interface{}{"a": "x", "b": []interface{}{"1", "2"}, "c": map[string]interface{}{"d": true}},
+		map[string]interface{}{"a": "x", "b": []interface{}{"1"}},
+		&fields)
+	assert.ElementsMatch(t, []string{"spec.b", "spec.c.d"}, fields)
+}
+
+func TestMigrateToBrokerDryRun(t *testing.T) {
+	artemis := migrationArtemis(migrateToBrokerDryRun)
+	ss := ownedByArtemis(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "ex-aao-ss"}})
+	cl := newTestClient(artemis, ss)
+	r := &ActiveMQArtemisReconciler{Client: cl, Scheme: cl.Scheme(), log: ctrl.Log}
+
+	mode, requested := isMigrationRequested(artemis)
+	assert.True(t, requested)
+	migrated, err := r.migrateToBroker(artemis, mode)
+	assert.NoError(t, err)
+	assert.False(t, migrated)
+
+	condition := meta.FindStatusCondition(artemis.Status.Conditions, brokerv1beta1.MigratedConditionType)
+	assert.Equal(t, brokerv1beta1.MigratedConditionDryRunReason, condition.Reason)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Contains(t, condition.Message, "1 resources")
+	assert.Error(t, r.Get(context.TODO(), types.NamespacedName{Name: "ex-aao", Namespace: "test"}, &v1beta2.Broker{}))
+
+	artemis.Annotations[common.MigrateToBrokerAnnotation] = "false"
+	_, requested = isMigrationRequested(artemis)
+	assert.False(t, requested)
+}
+
+func TestMigrateToBroker(t *testing.T) {
+	artemis := migrationArtemis("true")
+	ss := ownedByArtemis(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "ex-aao-ss"}})
+	secret := ownedByArtemis(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ex-aao-props"}})
+	pvc := ownedByArtemis(&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "ex-aao-ex-aao-ss-0"}})
+	unrelated := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"}}
+	cl := newTestClient(artemis, ss, secret, pvc, unrelated)
+	r := &ActiveMQArtemisReconciler{Client: cl, Scheme: cl.Scheme(), log: ctrl.Log}
+
+	migrated, err := r.migrateToBroker(artemis, "true")
+	assert.NoError(t, err)
+	assert.True(t, migrated)
+
+	broker := &v1beta2.Broker{}
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: "ex-aao", Namespace: "test"}, broker))
+	assert.Equal(t, artemis.Spec.BrokerProperties, broker.Spec.BrokerProperties)
+	assert.Equal(t, "orders", broker.Labels["team"])
+	assert.Equal(t, "artemis-uid", broker.Annotations[common.MigratedFromAnnotation])
+	assert.NotContains(t, broker.Annotations, common.BlockReconcileAnnotation)
+	assert.NotContains(t, broker.Annotations, common.MigrateToBrokerAnnotation)
+
+	for _, obj := range []client.Object{&appsv1.StatefulSet{}, &corev1.Secret{}, &corev1.PersistentVolumeClaim{}} {
+		var name string
+		switch obj.(type) {
+		case *appsv1.StatefulSet:
+			name = "ex-aao-ss"
+		case *corev1.Secret:
+			name = "ex-aao-props"
+		default:
+			name = "ex-aao-ex-aao-ss-0"
+		}
+		assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "test"}, obj))
+		assert.Equal(t, "Broker", obj.GetOwnerReferences()[0].Kind, name)
+		assert.Equal(t, v1beta2.GroupVersion.String(), obj.GetOwnerReferences()[0].APIVersion, name)
+		assert.True(t, *obj.GetOwnerReferences()[0].Controller, name)
+	}
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: "other", Namespace: "test"}, unrelated))
+	assert.Empty(t, unrelated.OwnerReferences)
+
+	current := &brokerv1beta1.ActiveMQArtemis{}
+	assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: "ex-aao", Namespace: "test"}, current))
+	assert.Equal(t, "ex-aao", current.Annotations[common.MigratedToBrokerAnnotation])
+	assert.NotContains(t, current.Annotations, common.MigrateToBrokerAnnotation)
+	assert.True(t, meta.IsStatusConditionTrue(current.Status.Conditions, brokerv1beta1.MigratedConditionType))
+
+	// a migrated ActiveMQArtemis is not reconciled anymore
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "ex-aao", Namespace: "test"}})
+	assert.NoError(t, err)
+}
+
+func TestMigrateToBrokerExists(t *testing.T) {
+	artemis := migrationArtemis("true")
+	existing := &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: "ex-aao", Namespace: "test"}}
+	cl := newTestClient(artemis, existing)
+	r := &ActiveMQArtemisReconciler{Client: cl, Scheme: cl.Scheme(), log: ctrl.Log}
+
+	migrated, err := r.migrateToBroker(artemis, "true")
+	assert.NoError(t, err)
+	assert.False(t, migrated)
+	condition := meta.FindStatusCondition(artemis.Status.Conditions, brokerv1beta1.MigratedConditionType)
+	assert.Equal(t, brokerv1beta1.MigratedConditionBrokerExistsReason, condition.Reason)
+}
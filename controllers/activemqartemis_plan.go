@@ -0,0 +1,166 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/RHsyseng/operator-utils/pkg/resource/compare"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+func isPlanRequested(customResource *v1beta2.Broker) bool {
+	if val, present := customResource.Annotations[common.PlanAnnotation]; present {
+		if boolVal, err := strconv.ParseBool(val); err == nil {
+			return boolVal
+		}
+	}
+	return false
+}
+
+// planOf describes the deltas of a reconcile for each requested and deployed resource, a resource that is in no
+// delta is unchanged
+func (reconciler *ActiveMQArtemisReconcilerImpl) planOf(customResource *v1beta2.Broker, requested map[reflect.Type][]rtclient.Object, deltas map[reflect.Type]compare.ResourceDelta) *v1beta2.PlanStatus {
+	plan := &v1beta2.PlanStatus{
+		ObservedGeneration: customResource.Generation,
+		Impact:             v1beta2.PlanImpactNone,
+	}
+
+	for _, resourceType := range getOrderedTypeList() {
+		delta := deltas[resourceType]
+		actions := map[string]v1beta2.PlanAction{}
+		for _, obj := range delta.Added {
+			actions[obj.GetName()] = v1beta2.PlanActionCreate
+		}
+		for _, obj := range delta.Removed {
+			actions[obj.GetName()] = v1beta2.PlanActionDelete
+		}
+		updated := map[string]rtclient.Object{}
+		for _, obj := range delta.Updated {
+			actions[obj.GetName()] = v1beta2.PlanActionUpdate
+			updated[obj.GetName()] = obj
+		}
+		for _, obj := range requested[resourceType] {
+			if _, found := actions[obj.GetName()]; !found {
+				actions[obj.GetName()] = v1beta2.PlanActionNone
+			}
+		}
+
+		names := make([]string, 0, len(actions))
+		for name := range actions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			planned := v1beta2.PlannedResource{
+				Kind:   resourceType.Name(),
+				Name:   name,
+				Action: actions[name],
+				Impact: v1beta2.PlanImpactNone,
+			}
+			if update, found := updated[name]; found {
+				if deployed := reconciler.getFromDeployed(resourceType, name); deployed != nil {
+					planned.Impact, planned.Fields = updateImpact(customResource, deployed, update)
+				}
+			}
+			plan.Impact = mostDisruptive(plan.Impact, planned.Impact)
+			plan.Resources = append(plan.Resources, planned)
+		}
+	}
+	return plan
+}
+
+// updateImpact tells how the brokers see an update, a pod template change restarts them and the properties
+// secret is reloaded, the other resources are not seen by the brokers
+func updateImpact(customResource *v1beta2.Broker, deployed rtclient.Object, requested rtclient.Object) (v1beta2.PlanImpact, []string) {
+	fields := metaDiff(deployed, requested)
+
+	switch requestedObj := requested.(type) {
+	case *appsv1.StatefulSet:
+		deployedObj := deployed.(*appsv1.StatefulSet)
+		impact := v1beta2.PlanImpactNone
+		for _, field := range podTemplateDiff(&deployedObj.Spec.Template, &requestedObj.Spec.Template) {
+			fields = append(fields, "spec.template."+field)
+			impact = v1beta2.PlanImpactRestart
+		}
+		deployedSpec := reflect.ValueOf(deployedObj.Spec)
+		requestedSpec := reflect.ValueOf(requestedObj.Spec)
+		for i := 0; i < deployedSpec.NumField(); i++ {
+			field := deployedSpec.Type().Field(i)
+			if field.Name != "Template" && !equality.Semantic.DeepEqual(deployedSpec.Field(i).Interface(), requestedSpec.Field(i).Interface()) {
+				fields = append(fields, "spec."+jsonFieldName(field))
+			}
+		}
+		return impact, fields
+
+	case *corev1.Secret:
+		deployedData := mergeSecretStringDataToData(deployed.(*corev1.Secret)).Data
+		requestedData := mergeSecretStringDataToData(requestedObj).Data
+		keys := []string{}
+		for key, value := range requestedData {
+			if deployedValue, found := deployedData[key]; !found || string(deployedValue) != string(value) {
+				keys = append(keys, "data."+key)
+			}
+		}
+		for key := range deployedData {
+			if _, found := requestedData[key]; !found {
+				keys = append(keys, "data."+key)
+			}
+		}
+		sort.Strings(keys)
+		fields = append(fields, keys...)
+		if requestedObj.Name == getPropertiesResourceNsName(customResource).Name && len(keys) > 0 {
+			return v1beta2.PlanImpactReload, fields
+		}
+		return v1beta2.PlanImpactNone, fields
+
+	case *corev1.ConfigMap:
+		// compared by name, a changed config map is created with a new name
+		return v1beta2.PlanImpactNone, fields
+	}
+
+	if !equality.Semantic.DeepEqual(specOf(deployed), specOf(requested)) {
+		fields = append(fields, "spec")
+	}
+	return v1beta2.PlanImpactNone, fields
+}
+
+func metaDiff(deployed rtclient.Object, requested rtclient.Object) []string {
+	fields := []string{}
+	if !equality.Semantic.DeepEqual(deployed.GetLabels(), requested.GetLabels()) {
+		fields = append(fields, "metadata.labels")
+	}
+	if !equality.Semantic.DeepEqual(deployed.GetAnnotations(), requested.GetAnnotations()) {
+		fields = append(fields, "metadata.annotations")
+	}
+	return fields
+}
+
+func mostDisruptive(current v1beta2.PlanImpact, candidate v1beta2.PlanImpact) v1beta2.PlanImpact {
+	rank := map[v1beta2.PlanImpact]int{v1beta2.PlanImpactNone: 0, v1beta2.PlanImpactReload: 1, v1beta2.PlanImpactRestart: 2}
+	if rank[candidate] > rank[current] {
+		return candidate
+	}
+	return current
+}
@@ -0,0 +1,115 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+func plannedResource(plan *v1beta2.PlanStatus, kind string, name string) *v1beta2.PlannedResource {
+	for i := range plan.Resources {
+		if plan.Resources[i].Kind == kind && plan.Resources[i].Name == name {
+			return &plan.Resources[i]
+		}
+	}
+	return nil
+}
+
+func TestPlanHoldsChanges(t *testing.T) {
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "plan", Namespace: "test", UID: types.UID("plan-uid")},
+		Spec:       v1beta2.BrokerSpec{BrokerProperties: []string{"globalMaxSize=512M"}},
+	}
+	scheme := renderScheme()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(broker).WithStatusSubresource(broker).Build()
+	reconciler := &BrokerReconciler{Client: client, Scheme: scheme, log: ctrl.Log}
+	key := types.NamespacedName{Name: "plan", Namespace: "test"}
+
+	reconcile := func() *v1beta2.Broker {
+		_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+		assert.NoError(t, err)
+		current := &v1beta2.Broker{}
+		assert.NoError(t, client.Get(context.TODO(), key, current))
+		return current
+	}
+
+	current := reconcile()
+	assert.Nil(t, current.Status.Plan)
+	deployedSS := &appsv1.StatefulSet{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "plan-ss", Namespace: "test"}, deployedSS))
+
+	// a properties change is reloaded
+	current.Annotations = map[string]string{common.PlanAnnotation: "true"}
+	current.Spec.BrokerProperties = []string{"globalMaxSize=1G"}
+	assert.NoError(t, client.Update(context.TODO(), current))
+	current = reconcile()
+
+	if assert.NotNil(t, current.Status.Plan) {
+		assert.Equal(t, v1beta2.PlanImpactReload, current.Status.Plan.Impact)
+		props := plannedResource(current.Status.Plan, "Secret", "plan-props")
+		if assert.NotNil(t, props) {
+			assert.Equal(t, v1beta2.PlanActionUpdate, props.Action)
+			assert.Equal(t, v1beta2.PlanImpactReload, props.Impact)
+			assert.Equal(t, []string{"data." + BrokerPropertiesName}, props.Fields)
+		}
+		ss := plannedResource(current.Status.Plan, "StatefulSet", "plan-ss")
+		if assert.NotNil(t, ss) {
+			assert.Equal(t, v1beta2.PlanActionNone, ss.Action)
+		}
+	}
+	propsSecret := &corev1.Secret{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "plan-props", Namespace: "test"}, propsSecret))
+	assert.Contains(t, string(propsSecret.Data[BrokerPropertiesName]), "globalMaxSize=512M")
+
+	// an env var restarts the brokers
+	current.Spec.Env = []corev1.EnvVar{{Name: "PLANNED", Value: "true"}}
+	assert.NoError(t, client.Update(context.TODO(), current))
+	current = reconcile()
+
+	if assert.NotNil(t, current.Status.Plan) {
+		assert.Equal(t, v1beta2.PlanImpactRestart, current.Status.Plan.Impact)
+		assert.Equal(t, current.Generation, current.Status.Plan.ObservedGeneration)
+		ss := plannedResource(current.Status.Plan, "StatefulSet", "plan-ss")
+		if assert.NotNil(t, ss) {
+			assert.Equal(t, v1beta2.PlanActionUpdate, ss.Action)
+			assert.Equal(t, v1beta2.PlanImpactRestart, ss.Impact)
+			assert.Contains(t, ss.Fields, "spec.template.spec.containers[plan-container].env")
+		}
+	}
+	heldSS := &appsv1.StatefulSet{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "plan-ss", Namespace: "test"}, heldSS))
+	assert.Equal(t, deployedSS.Spec.Template, heldSS.Spec.Template)
+
+	// removing the annotation applies the changes
+	delete(current.Annotations, common.PlanAnnotation)
+	assert.NoError(t, client.Update(context.TODO(), current))
+	current = reconcile()
+
+	assert.Nil(t, current.Status.Plan)
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "plan-props", Namespace: "test"}, propsSecret))
+	assert.Contains(t, string(propsSecret.Data[BrokerPropertiesName]), "globalMaxSize=1G")
+}
@@ -0,0 +1,110 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/brokerproperties"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+// validateBrokerPropertiesSchema checks the broker properties of the CR and of the control plane override secret
+// against the schema of the broker version, the -bp extra mount secrets are checked with the extra mounts
+func validateBrokerPropertiesSchema(customResource *v1beta2.Broker, client rtclient.Client) (*metav1.Condition, bool) {
+	brokerVersion, err := common.BrokerVersionToDeploy(customResource)
+	if err != nil {
+		// reported by the version validation
+		return nil, false
+	}
+
+	for _, entry := range customResource.Spec.BrokerProperties {
+		for _, keyAndValue := range KeyValuePairs([]byte(entry)) {
+			if problem := brokerPropertySchemaProblem(brokerVersion, keyAndValue); problem != "" {
+				return &metav1.Condition{
+					Type:    v1beta2.ValidConditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  v1beta2.ValidConditionFailedInvalidBrokerProperties,
+					Message: fmt.Sprintf(".Spec.BrokerProperties entry %v", problem),
+				}, false
+			}
+		}
+	}
+
+	if common.IsRestricted(customResource) {
+		overrideSecret, err := getControlPlaneOverrideSecret(customResource, client)
+		if err != nil {
+			return &metav1.Condition{
+				Type:    v1beta2.ValidConditionType,
+				Status:  metav1.ConditionUnknown,
+				Reason:  v1beta2.ValidConditionUnknownReason,
+				Message: fmt.Sprintf("failed to get the control plane override secret, %v", err),
+			}, true
+		}
+		if overrideSecret != nil {
+			if condition := AssertBrokerPropertiesSchemaInProperties(*overrideSecret, brokerVersion, "control plane override,"); condition != nil {
+				return condition, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// AssertBrokerPropertiesSchemaInProperties checks the properties entries of a secret, in key order to report the
+// same entry on each reconcile
+func AssertBrokerPropertiesSchemaInProperties(secret corev1.Secret, brokerVersion string, contextMessage string) *metav1.Condition {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		if !strings.HasPrefix(key, UncheckedPrefix) && strings.HasSuffix(key, PropertiesSuffix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, keyAndValue := range KeyValuePairs(secret.Data[key]) {
+			if problem := brokerPropertySchemaProblem(brokerVersion, keyAndValue); problem != "" {
+				return &metav1.Condition{
+					Type:    v1beta2.ValidConditionType,
+					Status:  metav1.ConditionFalse,
+					Reason:  v1beta2.ValidConditionFailedInvalidBrokerProperties,
+					Message: fmt.Sprintf("%s properties secret %v entry %v, %v", contextMessage, secret.Name, key, problem),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// brokerPropertySchemaProblem checks a key and value pair, the ordinal prefix of a property for a single broker is not
+// part of the schema and a pair without an equals separator is left to the broker
+func brokerPropertySchemaProblem(brokerVersion string, keyAndValue string) string {
+	key := extractPropertyKey(keyAndValue)
+	if key == keyAndValue {
+		return ""
+	}
+	value := keyAndValue[len(key)+1:]
+	if ordinalKey := ParseBrokerPropertyWithOrdinal(key); ordinalKey != nil {
+		key = ordinalKey[2]
+	}
+	return brokerproperties.Check(brokerVersion, key, value)
+}
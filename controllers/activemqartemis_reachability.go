@@ -0,0 +1,74 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/namer"
+)
+
+// requeueAfter is the resync period, or the backoff of a failed jolokia endpoint when it is sooner
+func (reconciler *ActiveMQArtemisReconcilerImpl) requeueAfter() time.Duration {
+	after := common.GetReconcileResyncPeriod()
+	for _, jk := range reconciler.jolokiaEndpoints {
+		if backoff, failed := reconciler.retryAfter(jk.IP); failed && backoff < after {
+			after = backoff
+		}
+	}
+	return after
+}
+
+// ProcessBrokersReachableStatus reports the brokers whose jolokia endpoint is marked unreachable
+// by the circuit breaker, the operator does not contact them till the retry time
+func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessBrokersReachableStatus(cr *v1beta2.Broker) (retry bool) {
+	unreachable := []string{}
+	var retryAt time.Time
+	for _, jk := range reconciler.jolokiaEndpoints {
+		if until, open := reconciler.unreachableUntil(jk.IP); open {
+			unreachable = append(unreachable, namer.CrToSS(cr.Name)+"-"+jk.Ordinal)
+			if retryAt.IsZero() || until.Before(retryAt) {
+				retryAt = until
+			}
+		}
+	}
+
+	if len(unreachable) == 0 {
+		if meta.FindStatusCondition(cr.Status.Conditions, v1beta2.BrokersReachableConditionType) != nil {
+			meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+				Type:   v1beta2.BrokersReachableConditionType,
+				Status: metav1.ConditionTrue,
+				Reason: v1beta2.BrokersReachableConditionReason,
+			})
+		}
+		return false
+	}
+
+	meta.SetStatusCondition(&cr.Status.Conditions, metav1.Condition{
+		Type:   v1beta2.BrokersReachableConditionType,
+		Status: metav1.ConditionFalse,
+		Reason: v1beta2.BrokersReachableConditionCircuitOpenReason,
+		Message: fmt.Sprintf("the jolokia endpoint of %s is unreachable, next attempt after %s",
+			strings.Join(unreachable, ", "), retryAt.Format(time.RFC3339)),
+	})
+	return true
+}
@@ -0,0 +1,86 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestProcessBrokersReachableStatus(t *testing.T) {
+	retryAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	unreachable := map[string]bool{}
+
+	cr := &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: "cb", Namespace: "test"}}
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+	ri.unreachableUntil = func(host string) (time.Time, bool) {
+		return retryAt, unreachable[host]
+	}
+	ri.jolokiaEndpoints = []*jolokia_client.JkInfo{
+		{IP: "cb-ss-0.cb-hdls-svc.test.svc.cluster.local", Ordinal: "0"},
+		{IP: "cb-ss-1.cb-hdls-svc.test.svc.cluster.local", Ordinal: "1"},
+	}
+
+	// no condition till an endpoint is unreachable
+	assert.False(t, ri.ProcessBrokersReachableStatus(cr))
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, v1beta2.BrokersReachableConditionType))
+
+	unreachable["cb-ss-1.cb-hdls-svc.test.svc.cluster.local"] = true
+	assert.True(t, ri.ProcessBrokersReachableStatus(cr))
+	condition := meta.FindStatusCondition(cr.Status.Conditions, v1beta2.BrokersReachableConditionType)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1beta2.BrokersReachableConditionCircuitOpenReason, condition.Reason)
+	assert.Contains(t, condition.Message, "cb-ss-1")
+	assert.NotContains(t, condition.Message, "cb-ss-0")
+	assert.Contains(t, condition.Message, "2026-10-19T10:00:00Z")
+
+	// recovered
+	delete(unreachable, "cb-ss-1.cb-hdls-svc.test.svc.cluster.local")
+	assert.False(t, ri.ProcessBrokersReachableStatus(cr))
+	assert.True(t, meta.IsStatusConditionTrue(cr.Status.Conditions, v1beta2.BrokersReachableConditionType))
+}
+
+func TestRequeueAfterJolokiaBackoff(t *testing.T) {
+	backoffs := map[string]time.Duration{}
+
+	cr := &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: "cb", Namespace: "test"}}
+	ri := NewActiveMQArtemisReconcilerImpl(cr, NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift))
+	ri.retryAfter = func(host string) (time.Duration, bool) {
+		backoff, found := backoffs[host]
+		return backoff, found
+	}
+	ri.jolokiaEndpoints = []*jolokia_client.JkInfo{
+		{IP: "cb-ss-0.cb-hdls-svc.test.svc.cluster.local", Ordinal: "0"},
+		{IP: "cb-ss-1.cb-hdls-svc.test.svc.cluster.local", Ordinal: "1"},
+	}
+	assert.Equal(t, common.GetReconcileResyncPeriod(), ri.requeueAfter())
+
+	backoffs["cb-ss-0.cb-hdls-svc.test.svc.cluster.local"] = 2 * time.Second
+	backoffs["cb-ss-1.cb-hdls-svc.test.svc.cluster.local"] = time.Second
+	assert.Equal(t, time.Second, ri.requeueAfter())
+
+	backoffs["cb-ss-1.cb-hdls-svc.test.svc.cluster.local"] = time.Hour
+	assert.Equal(t, 2*time.Second, ri.requeueAfter())
+}
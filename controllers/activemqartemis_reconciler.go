@@ -13,11 +13,13 @@ import (
 	"regexp"
 	"slices"
 	"sort"
+	"time"
 	"unicode"
 
 	"github.com/RHsyseng/operator-utils/pkg/resource/compare"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/containers"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/gateways"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/ingresses"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/persistentvolumeclaims"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/pods"
@@ -25,9 +27,11 @@ import (
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/secrets"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/serviceports"
 	ss "github.com/arkmq-org/activemq-artemis-operator/pkg/resources/statefulsets"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/artemis"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/certutil"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/cr2jinja2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/namer"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/random"
@@ -46,6 +50,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/environments"
 	svc "github.com/arkmq-org/activemq-artemis-operator/pkg/resources/services"
@@ -91,6 +97,10 @@ const (
 	ServiceTypePostfix       = "svc"
 	RouteTypePostfix         = "rte"
 	IngressTypePostfix       = "ing"
+	TLSRouteTypePostfix      = "tlsrte"
+	HTTPRouteTypePostfix     = "httprte"
+	LoadBalancerTypePostfix  = "lb"
+	NodePortTypePostfix      = "np"
 	RemoveKeySpecialValue    = "-"
 	javaArgsAppendEnvVarName = "JAVA_ARGS_APPEND"
 	debugArgsEnvVarName      = "DEBUG_ARGS"
@@ -112,6 +122,12 @@ var initHelperScript = "/opt/amq-broker/script/default.sh"
 var brokerConfigRoot = "/amq/init/config"
 var configCmd = "/opt/amq/bin/launch.sh"
 
+// the trust store of the login modules only holds certificates, the password of the JDK default trust store is kept
+const (
+	loginModulesTrustStorePath     = "/app/tmp/login-modules-truststore.p12"
+	loginModulesTrustStorePassword = "changeit"
+)
+
 // default ApplyRule for address-settings
 var defApplyRule string = "merge_all"
 var yacfgProfileVersion = version.YacfgProfileVersionFromFullVersion[version.GetDefaultVersion()]
@@ -126,6 +142,20 @@ type ActiveMQArtemisReconcilerImpl struct {
 	jolokiaEndpoints   []*jolokia_client.JkInfo
 	cachedBrokerStatus map[string]any
 	matchedTemplates   map[int]bool
+	// report the deltas in the status in place of applying them
+	planOnly bool
+	// the secrets with the certificates that the brokers consume
+	certificateSecrets map[types.NamespacedName]*corev1.Secret
+	// the certificate secrets that the brokers only read on start
+	restartCertificateSecrets map[types.NamespacedName]bool
+	// the certificates that acceptors reload from the mounted PEM files
+	servedCertificates []servedCertificate
+	// the rendered BrokerAddress and BrokerSecurity secrets that the brokers mount with the extra mounts
+	renderedPropertiesSecrets []string
+	// the jolokia endpoints of the brokers and their circuit breaker state, replaced in tests
+	getEndpoints     func(cr *v1beta2.Broker, client rtclient.Client) []*jolokia_client.JkInfo
+	unreachableUntil func(host string) (time.Time, bool)
+	retryAfter       func(host string) (time.Duration, bool)
 }
 
 func NewActiveMQArtemisReconcilerImpl(customResource *v1beta2.Broker, parent *ActiveMQArtemisReconciler) *ActiveMQArtemisReconcilerImpl {
@@ -137,6 +167,9 @@ func NewActiveMQArtemisReconcilerImpl(customResource *v1beta2.Broker, parent *Ac
 		isOnOpenShift:      parent.isOnOpenShift,
 		cachedBrokerStatus: make(map[string]any),
 		matchedTemplates:   make(map[int]bool),
+		getEndpoints:       jolokiaEndpointsOf,
+		unreachableUntil:   jolokia.UnreachableUntil,
+		retryAfter:         jolokia.RetryAfter,
 	}
 }
 
@@ -163,6 +196,9 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) Process(customResource *v1beta2
 
 	reconciler.CurrentDeployedResources(customResource, client)
 
+	// before anything resolves the version of the brokers
+	reconciler.holdBrokerUpgrade(customResource, time.Now())
+
 	// currentStateful Set is a clone of what exists if already deployed
 	// what follows should transform the resources using the crd
 	// if the transformation results in some change, process resources will respect that
@@ -193,7 +229,9 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) Process(customResource *v1beta2
 
 	// mods to env var values sourced from secrets are not detected by process resources
 	// track updates in trigger env var that has a total checksum
-	trackSecretCheckSumInEnvVar(common.ToResourceList(reconciler.requestedResources), desiredStatefulSet.Spec.Template.Spec.Containers)
+	reconciler.trackSecretCheckSumInEnvVar(common.ToResourceList(reconciler.requestedResources), desiredStatefulSet)
+
+	reconciler.holdPodTemplateChanges(customResource, desiredStatefulSet, time.Now())
 
 	reconciler.trackDesired(desiredStatefulSet)
 
@@ -210,7 +248,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) Process(customResource *v1beta2
 	return err
 }
 
-func trackSecretCheckSumInEnvVar(requestedResources []rtclient.Object, container []corev1.Container) {
+func (reconciler *ActiveMQArtemisReconcilerImpl) trackSecretCheckSumInEnvVar(requestedResources []rtclient.Object, statefulSet *appsv1.StatefulSet) {
 	// the requestedResources need to be sorted because they are extracted
 	// from a map and adler32 depends on the prder of the bytes
 	sort.Slice(requestedResources, func(i, j int) bool {
@@ -236,7 +274,8 @@ func trackSecretCheckSumInEnvVar(requestedResources []rtclient.Object, container
 			}
 		}
 	}
-	environments.TrackSecretCheckSumInRollCount(hex.EncodeToString(digest.Sum(nil)), container)
+	reconciler.writeCertificateSecrets(digest, statefulSet)
+	environments.TrackSecretCheckSumInRollCount(hex.EncodeToString(digest.Sum(nil)), statefulSet.Spec.Template.Spec.Containers)
 }
 
 func (reconciler *ActiveMQArtemisReconcilerImpl) cloneOfDeployed(kind reflect.Type, name string) rtclient.Object {
@@ -500,12 +539,11 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) GetTotalMessageCount(customReso
 		return -1, err
 	}
 	jk := reconciler.jolokiaEndpoints[ordinalToDrain]
-	result, err := jk.Artemis.GetTotalMessageCount()
+	count, err := jk.Artemis.GetTotalMessageCount()
 	if err != nil {
 		return -1, NewJolokiaClientsNotFoundError(fmt.Errorf("error on get total message count %w ", err))
 	}
-
-	return strconv.ParseInt(result, 10, 64)
+	return count, nil
 }
 
 func (reconciler *ActiveMQArtemisReconcilerImpl) resolveAndValidateJolokia(client rtclient.Client, ordinalOfInterest int32) error {
@@ -596,7 +634,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessConsole(customResource *
 	if customResource.Spec.Console.SSLSecret != "" {
 		secretName = customResource.Spec.Console.SSLSecret
 	}
-	sslArgs, sslFlags, err := reconciler.generateCommonSSLFlags(customResource, secretName, customResource.Spec.Console.TrustSecret, "", client, true)
+	sslArgs, sslFlags, err := reconciler.generateCommonSSLFlags(customResource, secretName, customResource.Spec.Console.TrustSecret, "", client, true, false)
 	if err != nil {
 		return err
 	}
@@ -921,12 +959,18 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) generateAcceptorsString(customR
 				return "", err
 			}
 
-			sslArgs, sslFlags, err := reconciler.generateCommonSSLFlags(customResource, secretToUse.Name, acceptor.TrustSecret, acceptor.TrustStoreType, client, false)
+			sslArgs, sslFlags, err := reconciler.generateCommonSSLFlags(customResource, secretToUse.Name, acceptor.TrustSecret, acceptor.TrustStoreType, client, false, acceptor.SSLAutoReload)
 			if err != nil {
 				return "", err
 			}
 			acceptorEntry = acceptorEntry + ";" + sslFlags
 
+			if acceptor.SSLAutoReload && sslArgs.KeyStoreType == "PEM" {
+				// a renewed certificate is reloaded by the acceptor without a restart
+				acceptorEntry = acceptorEntry + ";" + "sslAutoReload=true"
+				reconciler.trackServedCertificate(types.NamespacedName{Namespace: customResource.Namespace, Name: secretToUse.Name}, acceptor)
+			}
+
 			if len(sslArgs.PemCfgs) > 2 {
 				reconciler.addPemConfigFileSecret(currentSS, sslArgs.PemCfgs)
 			}
@@ -1002,7 +1046,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) generateConnectorsString(custom
 				return "", err
 			}
 
-			sslArgs, sslOpts, err := reconciler.generateCommonSSLFlags(customResource, secretToUse.Name, connector.TrustSecret, connector.TrustStoreType, client, false)
+			sslArgs, sslOpts, err := reconciler.generateCommonSSLFlags(customResource, secretToUse.Name, connector.TrustSecret, connector.TrustStoreType, client, false, false)
 
 			if err != nil {
 				return "", err
@@ -1047,8 +1091,10 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) configureAcceptorsExposure(cust
 			reconciler.checkExistingService(customResource, serviceDefinition, client)
 			reconciler.trackDesired(serviceDefinition)
 
-			if acceptor.Expose {
-				exposureDefinition := reconciler.ExposureDefinitionForCR(customResource, namespacedName, serviceRoutelabels, acceptor.SSLEnabled, acceptor.IngressHost, ordinalString, acceptor.Name, acceptor.ExposeMode)
+			if acceptor.Expose && isServiceExposeMode(acceptor.ExposeMode) {
+				reconciler.trackDesired(reconciler.exposedServiceDefinitionForCR(customResource, &acceptor, int(i), serviceRoutelabels, namer.LabelBuilder.Labels()))
+			} else if acceptor.Expose {
+				exposureDefinition := reconciler.ExposureDefinitionForCR(customResource, namespacedName, serviceRoutelabels, acceptor.SSLEnabled, acceptor.IngressHost, ordinalString, acceptor.Name, acceptor.Port, acceptor.ExposeMode)
 				reconciler.trackDesired(exposureDefinition)
 			}
 		}
@@ -1064,11 +1110,15 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ServiceDefinitionForCR(serviceN
 	return svc.NewServiceDefinitionForCR(serviceName, client, nameSuffix, portNumber, selectorLabels, labels, serviceDefinition)
 }
 
-func (reconciler *ActiveMQArtemisReconcilerImpl) ExposureDefinitionForCR(customResource *v1beta2.Broker, namespacedName types.NamespacedName, labels map[string]string, passthroughTLS bool, ingressHost string, ordinalString string, itemName string, exposeMode *v1beta2.ExposeMode) rtclient.Object {
+func (reconciler *ActiveMQArtemisReconcilerImpl) ExposureDefinitionForCR(customResource *v1beta2.Broker, namespacedName types.NamespacedName, labels map[string]string, passthroughTLS bool, ingressHost string, ordinalString string, itemName string, port int32, exposeMode *v1beta2.ExposeMode) rtclient.Object {
 
 	targetPortName := itemName + "-" + ordinalString
 	targetServiceName := customResource.Name + "-" + targetPortName + "-" + ServiceTypePostfix
 
+	if exposeMode != nil && *exposeMode == v1beta2.ExposeModes.Gateway {
+		return reconciler.tlsRouteDefinitionForCR(customResource, namespacedName, labels, targetServiceName, port, ingressHost, ordinalString, itemName)
+	}
+
 	exposeWithRoute := (exposeMode == nil && reconciler.isOnOpenShift) || (exposeMode != nil && *exposeMode == v1beta2.ExposeModes.Route)
 
 	if exposeWithRoute {
@@ -1330,7 +1380,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) configureConnectorsExposure(cus
 
 			if connector.Expose {
 
-				exposureDefinition := reconciler.ExposureDefinitionForCR(customResource, namespacedName, serviceRoutelabels, connector.SSLEnabled, connector.IngressHost, ordinalString, connector.Name, connector.ExposeMode)
+				exposureDefinition := reconciler.ExposureDefinitionForCR(customResource, namespacedName, serviceRoutelabels, connector.SSLEnabled, connector.IngressHost, ordinalString, connector.Name, connector.Port, connector.ExposeMode)
 
 				reconciler.trackDesired(exposureDefinition)
 			}
@@ -1379,7 +1429,21 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) configureConsoleExposure(custom
 
 			exposeWithRoute := (console.ExposeMode == nil && reconciler.isOnOpenShift) || (console.ExposeMode != nil && *console.ExposeMode == v1beta2.ExposeModes.Route)
 
-			if exposeWithRoute {
+			if console.ExposeMode != nil && *console.ExposeMode == v1beta2.ExposeModes.Gateway {
+				if console.SSLEnabled {
+					reconciler.trackDesired(reconciler.tlsRouteDefinitionForCR(customResource, namespacedName, serviceRoutelabels, targetServiceName, portNumber, console.IngressHost, ordinalString, consoleName))
+				} else {
+					reconciler.log.V(2).Info("httpRouteDefinition for " + targetPortName)
+					var existing *gatewayv1beta1.HTTPRoute = nil
+					obj := reconciler.cloneOfDeployed(reflect.TypeOf(gatewayv1beta1.HTTPRoute{}), targetServiceName+"-"+HTTPRouteTypePostfix)
+					if obj != nil {
+						existing = obj.(*gatewayv1beta1.HTTPRoute)
+					}
+					brokerHost := formatTemplatedString(customResource, console.IngressHost, ordinalString, consoleName, HTTPRouteTypePostfix)
+					httpRouteDefinition := gateways.NewHTTPRouteForCR(existing, namespacedName, serviceRoutelabels, targetServiceName, portNumber, gatewayNamespacedName(customResource), customResource.Spec.Gateway.HTTPListener, customResource.Spec.IngressDomain, brokerHost)
+					reconciler.trackDesired(httpRouteDefinition)
+				}
+			} else if exposeWithRoute {
 				reconciler.log.V(2).Info("routeDefinition for " + targetPortName)
 				var existing *routev1.Route = nil
 				obj := reconciler.cloneOfDeployed(reflect.TypeOf(routev1.Route{}), targetServiceName+"-"+RouteTypePostfix)
@@ -1405,6 +1469,27 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) configureConsoleExposure(custom
 	}
 }
 
+func (reconciler *ActiveMQArtemisReconcilerImpl) tlsRouteDefinitionForCR(customResource *v1beta2.Broker, namespacedName types.NamespacedName, labels map[string]string, targetServiceName string, port int32, ingressHost string, ordinalString string, itemName string) *gatewayv1alpha2.TLSRoute {
+	reconciler.log.V(1).Info("creating tls route for "+itemName+"-"+ordinalString, "service", targetServiceName)
+
+	var existing *gatewayv1alpha2.TLSRoute = nil
+	obj := reconciler.cloneOfDeployed(reflect.TypeOf(gatewayv1alpha2.TLSRoute{}), targetServiceName+"-"+TLSRouteTypePostfix)
+	if obj != nil {
+		existing = obj.(*gatewayv1alpha2.TLSRoute)
+	}
+	brokerHost := formatTemplatedString(customResource, ingressHost, ordinalString, itemName, TLSRouteTypePostfix)
+	return gateways.NewTLSRouteForCR(existing, namespacedName, labels, targetServiceName, port, gatewayNamespacedName(customResource), customResource.Spec.Gateway.TLSListener, customResource.Spec.IngressDomain, brokerHost)
+}
+
+// the namespace of the gateway defaults to the namespace of the CR, a gateway mode is only valid with a gateway
+func gatewayNamespacedName(customResource *v1beta2.Broker) types.NamespacedName {
+	gateway := types.NamespacedName{Name: customResource.Spec.Gateway.Name, Namespace: customResource.Spec.Gateway.Namespace}
+	if gateway.Namespace == "" {
+		gateway.Namespace = customResource.Namespace
+	}
+	return gateway
+}
+
 func formatTemplatedString(customResource *v1beta2.Broker, template string, brokerOrdinal string, itemName string, resType string) string {
 	if template != "" {
 		template = strings.Replace(template, "$(CR_NAME)", customResource.Name, -1)
@@ -1417,7 +1502,7 @@ func formatTemplatedString(customResource *v1beta2.Broker, template string, brok
 	return template
 }
 
-func (reconciler *ActiveMQArtemisReconcilerImpl) generateCommonSSLFlags(customResource *v1beta2.Broker, secretName string, caSecretName *string, trustStoreType string, client rtclient.Client, isConsole bool) (*certutil.SslArguments, string, error) {
+func (reconciler *ActiveMQArtemisReconcilerImpl) generateCommonSSLFlags(customResource *v1beta2.Broker, secretName string, caSecretName *string, trustStoreType string, client rtclient.Client, isConsole bool, autoReload bool) (*certutil.SslArguments, string, error) {
 
 	sslSecret := &corev1.Secret{}
 	if strings.HasSuffix(secretName, certutil.Cert_provided_secret_suffix) {
@@ -1454,6 +1539,10 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) generateCommonSSLFlags(customRe
 		return nil, "", err
 	}
 
+	// only the PEM files of a keystore are reloaded, a truststore is read on start
+	reconciler.trackCertificateSecret(sslSecret, !autoReload || sslArgs.KeyStoreType != "PEM")
+	reconciler.trackCertificateSecret(caSecret, true)
+
 	sslFlags := sslArgs.ToFlags()
 
 	return sslArgs, sslFlags, nil
@@ -1590,6 +1679,9 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) CurrentDeployedResources(custom
 		if strings.HasPrefix(secret.GetName(), "secret-broker-") {
 			// track this as it is managed by the controller state machine, not by reconcile
 			reconciler.trackDesired(secret)
+		} else if isRenderedPropertiesSecret(customResource, secret.GetName()) {
+			// track this as it is rendered by the address controllers, not by reconcile
+			reconciler.trackDesired(secret)
 		}
 	}
 
@@ -1624,6 +1716,11 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessResources(customResource
 
 	var compositeError []error
 	deltas := comparator.Compare(reconciler.deployed, requested)
+	if reconciler.planOnly {
+		customResource.Status.Plan = reconciler.planOf(customResource, requested, deltas)
+		reqLogger.V(1).Info("holding the planned changes", "impact", customResource.Status.Plan.Impact)
+		deltas = nil
+	}
 	for _, resourceType := range getOrderedTypeList() {
 		delta, ok := deltas[resourceType]
 		if !ok {
@@ -1917,7 +2014,7 @@ var orderedTypes *([]reflect.Type)
 
 func getOrderedTypeList() []reflect.Type {
 	if orderedTypes == nil {
-		types := make([]reflect.Type, 7)
+		types := make([]reflect.Type, 9)
 
 		// we want to create/update in this order
 		types[0] = reflect.TypeOf(corev1.Secret{})
@@ -1926,7 +2023,9 @@ func getOrderedTypeList() []reflect.Type {
 		types[3] = reflect.TypeOf(corev1.Service{})
 		types[4] = reflect.TypeOf(netv1.Ingress{})
 		types[5] = reflect.TypeOf(routev1.Route{})
-		types[6] = reflect.TypeOf(policyv1.PodDisruptionBudget{})
+		types[6] = reflect.TypeOf(gatewayv1alpha2.TLSRoute{})
+		types[7] = reflect.TypeOf(gatewayv1beta1.HTTPRoute{})
+		types[8] = reflect.TypeOf(policyv1.PodDisruptionBudget{})
 		orderedTypes = &types
 	}
 	return *orderedTypes
@@ -1951,6 +2050,8 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) MakeVolumes(customResource *v1b
 		volumeDefinitions = append(volumeDefinitions, emptyDirData)
 	}
 
+	volumeDefinitions = append(volumeDefinitions, haSharedStoreVolumes(customResource)...)
+
 	volumeDefinitions = append(volumeDefinitions, customResource.Spec.DeploymentPlan.ExtraVolumes...)
 
 	for _, epvc := range customResource.Spec.DeploymentPlan.ExtraVolumeClaimTemplates {
@@ -2062,6 +2163,8 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) MakeVolumeMounts(customResource
 		volumeMounts = append(volumeMounts, persistentCRVlMnt...)
 	}
 
+	volumeMounts = append(volumeMounts, haSharedStoreVolumeMounts(customResource)...)
+
 	// Add extra volumes and extra volume claim templates
 	extraVolumeMounts := MakeExtraVolumeMounts(customResource)
 	volumeMounts = append(volumeMounts, extraVolumeMounts...)
@@ -2187,8 +2290,8 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) PodTemplateSpecForCR(customReso
 	reqLogger.V(2).Info("Checking out extraMounts", "extra config", customResource.Spec.DeploymentPlan.ExtraMounts)
 
 	configMapsToMount := customResource.Spec.DeploymentPlan.ExtraMounts.ConfigMaps
-	secretsToMount := customResource.Spec.DeploymentPlan.ExtraMounts.Secrets
-	brokerPropertiesResourceName, isSecret, brokerPropertiesMapData, serr := reconciler.addResourceForBrokerProperties(customResource, namer)
+	secretsToMount := reconciler.extraMountSecrets(customResource)
+	brokerPropertiesResourceName, isSecret, brokerPropertiesMapData, serr := reconciler.addResourceForBrokerProperties(customResource, namer, client)
 	if serr != nil {
 		return nil, serr
 	}
@@ -2238,6 +2341,8 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) PodTemplateSpecForCR(customReso
 		if caCertSecret, err = common.GetOperatorCASecret(client); err != nil {
 			return nil, fmt.Errorf("failed to get operator ca secret, %w", err)
 		}
+		reconciler.trackCertificateSecret(operandCertSecret, true)
+		reconciler.trackCertificateSecret(caCertSecret, true)
 
 		caSecretKey, err := common.GetOperatorCASecretKey(client, caCertSecret)
 		if err != nil {
@@ -2314,6 +2419,13 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) PodTemplateSpecForCR(customReso
 
 		brokerPropertiesMapData["aa_rbac.properties"] = rbac.Bytes()
 
+		// there is no init container to apply the security handler config
+		if brokerConfigHandler := GetBrokerConfigHandler(namespacedName); brokerConfigHandler != nil {
+			for key, value := range brokerConfigHandler.BrokerProperties(mountPathRoot) {
+				brokerPropertiesMapData[key] = value
+			}
+		}
+
 		secretsToMount = append(secretsToMount, operandCertSecretName)
 		caSecret := common.GetOperatorCASecretName()
 		secretsToMount = append(secretsToMount, caSecret)
@@ -2431,6 +2543,21 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) PodTemplateSpecForCR(customReso
 			container.LivenessProbe = reconciler.configureLivenessProbe(container, customResource.Spec.DeploymentPlan.LivenessProbe)
 		}
 	}
+	secretsToMount = brokerConnectionsSecretsToMount(customResource, secretsToMount, client)
+
+	loginModulesTrustCmd := ""
+	if brokerConfigHandler := GetBrokerConfigHandler(namespacedName); brokerConfigHandler != nil && common.IsRestricted(customResource) {
+		for _, secret := range brokerConfigHandler.SecretsToMount() {
+			secretsToMount = appendIfMissing(secretsToMount, secret)
+		}
+		var trustProps []string
+		var terr error
+		if loginModulesTrustCmd, trustProps, terr = loginModulesTrustStore(customResource, brokerConfigHandler, client); terr != nil {
+			return nil, terr
+		}
+		additionalSystemPropsForRestricted = append(additionalSystemPropsForRestricted, trustProps...)
+	}
+
 	extraVolumes, extraVolumeMounts, err := reconciler.createExtraConfigmapsAndSecretsVolumeMounts(configMapsToMount, secretsToMount, brokerPropertiesResourceName, brokerPropertiesMapData, client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to createExtraConfigmapsAndSecretsVolumeMounts, %w", err)
@@ -2459,6 +2586,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) PodTemplateSpecForCR(customReso
 	}
 
 	reconciler.configureAffinity(podSpec, &customResource.Spec.DeploymentPlan.Affinity)
+	reconciler.configureHAAntiAffinity(podSpec, customResource, namer)
 
 	if len(customResource.Spec.DeploymentPlan.Tolerations) > 0 {
 		reqLogger.V(1).Info("Adding Tolerations", "len", len(customResource.Spec.DeploymentPlan.Tolerations))
@@ -2541,12 +2669,16 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) PodTemplateSpecForCR(customReso
 	var initCmds []string
 	var initCfgRootDir = "/init_cfg_root"
 
-	compactVersionToUse, verr := common.DetermineCompactVersionToUse(customResource)
+	fullVersionToUse, verr := common.BrokerVersionToDeploy(customResource)
 	if verr != nil {
 		reqLogger.Error(verr, "failed to get compact version", "Spec.Version", customResource.Spec.Version)
 		return nil, verr
 	}
-	yacfgProfileVersion = version.YacfgProfileVersionFromFullVersion[version.FullVersionFromCompactVersion[compactVersionToUse]]
+	var found bool
+	if yacfgProfileVersion, found = version.YacfgProfileVersionFromFullVersion[fullVersionToUse]; !found {
+		// a version of the image catalog uses the profile of the latest version
+		yacfgProfileVersion = version.YacfgProfileVersionFromFullVersion[version.LatestVersion]
+	}
 	yacfgProfileName := version.YacfgProfileName
 
 	//address settings
@@ -2739,6 +2871,9 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) PodTemplateSpecForCR(customReso
 		pts.Spec.InitContainers = nil
 
 		reEvalJdkOpts := generateReEvalOrdinaEnvReplacement(customResource.Spec.Env)
+		if loginModulesTrustCmd != "" {
+			reEvalJdkOpts += loginModulesTrustCmd + " && "
+		}
 
 		pts.Spec.Containers[0].Command = []string{
 			"/bin/bash", "-c",
@@ -2809,7 +2944,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) brokerPropertiesConfigSystemPro
 		result = fmt.Sprintf("-Dbroker.properties=%s%s/,%s%s/%s${STATEFUL_SET_ORDINAL}/", mountPoint, resourceName, mountPoint, resourceName, OrdinalPrefix)
 	}
 
-	for _, extraSecretName := range reconciler.customResource.Spec.DeploymentPlan.ExtraMounts.Secrets {
+	for _, extraSecretName := range reconciler.extraMountSecrets(reconciler.customResource) {
 		if strings.HasSuffix(extraSecretName, common.BrokerPropsSuffix) {
 			// append to ordinal path
 			result = fmt.Sprintf("%s,%s%s/,%s%s/%s${STATEFUL_SET_ORDINAL}/", result, common.SecretPathBase, extraSecretName, common.SecretPathBase, extraSecretName, OrdinalPrefix)
@@ -2824,6 +2959,42 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) brokerPropertiesConfigSystemPro
 	return result
 }
 
+// loginModulesTrustStore builds a trust store with the certificates of the default trust store of the JDK and the ca
+// bundle of the trust secret of the login modules, JNDI opens the ldaps connections with the default SSL context. It
+// returns the command that builds the trust store in the temp volume before the broker starts and the system props
+// of the broker JVM
+func loginModulesTrustStore(customResource *v1beta2.Broker, handler common.ActiveMQArtemisConfigHandler, client rtclient.Client) (string, []string, error) {
+	trustSecretName := handler.TrustSecret()
+	if trustSecretName == nil {
+		return "", nil, nil
+	}
+	trustSecret, err := common.GetNamespacedSecret(client, *trustSecretName, customResource.Namespace)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get the trust secret of the login modules, %w", err)
+	}
+	bundleKey, err := common.FindFirstDotPemKey(trustSecret)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find the ca bundle of the login modules trust secret %s, %w", *trustSecretName, err)
+	}
+	bundlePath := fmt.Sprintf("%s%s/%s", common.SecretPathBase, *trustSecretName, bundleKey)
+
+	storeOpts := fmt.Sprintf("-keystore %s -storetype PKCS12 -storepass %s", loginModulesTrustStorePath, loginModulesTrustStorePassword)
+	cmd := fmt.Sprintf("rm -f %[1]s && "+
+		"keytool -importkeystore -noprompt -srckeystore \"$(dirname $(dirname $(readlink -f $(command -v java))))/lib/security/cacerts\" -srcstorepass changeit -destkeystore %[1]s -deststoretype PKCS12 -deststorepass %[2]s > /dev/null && "+
+		"n=0 && pem=\"\" && while IFS= read -r line || [[ -n $line ]]; do "+
+		"if [[ $line == *\"BEGIN CERTIFICATE\"* ]]; then pem=\"\"; fi; pem=\"$pem$line\"$'\\n'; "+
+		"if [[ $line == *\"END CERTIFICATE\"* ]]; then n=$((n+1)); printf '%%s' \"$pem\" | keytool -importcert -noprompt -alias login-modules-ca-$n %[3]s > /dev/null || exit 1; fi; "+
+		"done < %[4]s",
+		loginModulesTrustStorePath, loginModulesTrustStorePassword, storeOpts, bundlePath)
+
+	props := []string{
+		"-Djavax.net.ssl.trustStore=" + loginModulesTrustStorePath,
+		"-Djavax.net.ssl.trustStorePassword=" + loginModulesTrustStorePassword,
+		"-Djavax.net.ssl.trustStoreType=PKCS12",
+	}
+	return cmd, props, nil
+}
+
 func getJaasConfigExtraMountPath(customResource *v1beta2.Broker) (string, bool) {
 	if t, name, found := getConfigExtraMount(customResource, jaasConfigSuffix); found {
 		return fmt.Sprintf("/amq/extra/%v/%v/login.config", t, name), true
@@ -2838,6 +3009,11 @@ func getLoggingConfigExtraMountPath(customResource *v1beta2.Broker) (string, boo
 	return "", false
 }
 
+// extraMountSecrets returns the extra mount secrets of the CR followed by the rendered properties secrets of a Broker
+func (reconciler *ActiveMQArtemisReconcilerImpl) extraMountSecrets(customResource *v1beta2.Broker) []string {
+	return slices.Concat(customResource.Spec.DeploymentPlan.ExtraMounts.Secrets, reconciler.renderedPropertiesSecrets)
+}
+
 func getConfigExtraMount(customResource *v1beta2.Broker, suffix string) (string, string, bool) {
 	for _, cm := range customResource.Spec.DeploymentPlan.ExtraMounts.ConfigMaps {
 		if strings.HasSuffix(cm, suffix) {
@@ -2999,10 +3175,24 @@ func conditionallyApplyValuesToPreserveDefaults(readinessProbe *corev1.Probe, pr
 }
 
 // applyControlPlaneOverrides applies control plane configuration overrides from secrets.
-// It first checks for CR-specific override secret ([cr-name]-control-plane-override),
-// then falls back to shared override secret (control-plane-override).
 // Each key in the override secret completely replaces the corresponding key in brokerPropertiesMapData.
 func applyControlPlaneOverrides(customResource *v1beta2.Broker, client rtclient.Client, brokerPropertiesMapData map[string][]byte) error {
+	overrideSecret, err := getControlPlaneOverrideSecret(customResource, client)
+	if err != nil || overrideSecret == nil {
+		return err
+	}
+
+	// Apply overrides - complete replacement per key
+	for key, value := range overrideSecret.Data {
+		brokerPropertiesMapData[key] = value
+	}
+
+	return nil
+}
+
+// getControlPlaneOverrideSecret first checks for CR-specific override secret ([cr-name]-control-plane-override),
+// then falls back to shared override secret (control-plane-override), it returns nil when neither exists.
+func getControlPlaneOverrideSecret(customResource *v1beta2.Broker, client rtclient.Client) (*corev1.Secret, error) {
 	ctx := context.Background()
 
 	// Try CR-specific override secret first
@@ -3022,21 +3212,15 @@ func applyControlPlaneOverrides(customResource *v1beta2.Broker, client rtclient.
 			if err != nil {
 				if k8serrors.IsNotFound(err) {
 					// No override secret found, this is OK
-					return nil
+					return nil, nil
 				}
-				return err
+				return nil, err
 			}
 		} else {
-			return err
+			return nil, err
 		}
 	}
-
-	// Apply overrides - complete replacement per key
-	for key, value := range overrideSecret.Data {
-		brokerPropertiesMapData[key] = value
-	}
-
-	return nil
+	return overrideSecret, nil
 }
 
 func getPropertiesResourceNsName(artemis *v1beta2.Broker) types.NamespacedName {
@@ -3046,7 +3230,7 @@ func getPropertiesResourceNsName(artemis *v1beta2.Broker) types.NamespacedName {
 	}
 }
 
-func (reconciler *ActiveMQArtemisReconcilerImpl) addResourceForBrokerProperties(customResource *v1beta2.Broker, namer common.Namers) (string, bool, map[string][]byte, error) {
+func (reconciler *ActiveMQArtemisReconcilerImpl) addResourceForBrokerProperties(customResource *v1beta2.Broker, namer common.Namers, client rtclient.Client) (string, bool, map[string][]byte, error) {
 
 	// fetch and do idempotent transform based on CR
 
@@ -3078,6 +3262,11 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) addResourceForBrokerProperties(
 
 	data := BrokerPropertiesData(reconciler.customResource.Spec.BrokerProperties)
 	reconciler.ProcessBrokerProperties(data)
+	reconciler.ProcessHAProperties(data)
+	reconciler.ProcessExternalAddressProperties(data)
+	if err := reconciler.ProcessBrokerConnectionsProperties(data, client); err != nil {
+		return "", false, nil, err
+	}
 
 	if desired == nil {
 		reconciler.log.V(1).Info("desired brokerprop secret nil, create new one", "name", resourceName.Name)
@@ -3364,6 +3553,8 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) StatefulSetForCR(customResource
 	}
 	replicas := common.GetDeploymentSize(customResource)
 	currentStateFullSet = ss.MakeStatefulSet(currentStateFullSet, namer.SsNameBuilder.Name(), namer.SvcHeadlessNameBuilder.Name(), namespacedName, nil, namer.LabelBuilder.Labels(), &replicas)
+	reconciler.configureHAPodManagement(customResource, currentStateFullSet)
+	reconciler.configureRolloutPartition(customResource, currentStateFullSet)
 
 	podTemplateSpec, err := reconciler.PodTemplateSpecForCR(customResource, namer, currentStateFullSet, client)
 	if err != nil {
@@ -3491,6 +3682,8 @@ func MakeEnvVarArrayForCR(customResource *v1beta2.Broker, namer common.Namers) [
 	envVarArrayForMetricsPlugin := environments.AddEnvVarForMetricsPlugin(metricsPluginEnabled)
 	envVar = append(envVar, envVarArrayForMetricsPlugin...)
 
+	envVar = append(envVar, MakeEnvVarArrayForBrokerConnections(customResource)...)
+
 	// Env from CR will override
 	envVar = environments.ReplaceOrAppend(envVar, customResource.Spec.Env...)
 
@@ -3500,6 +3693,7 @@ func MakeEnvVarArrayForCR(customResource *v1beta2.Broker, namer common.Namers) [
 type brokerStatus struct {
 	BrokerConfigStatus brokerConfigStatus `json:"configuration"`
 	ServerStatus       serverStatus       `json:"server"`
+	HA                 artemis.HAState    `json:"-"`
 }
 
 type serverStatus struct {
@@ -3508,6 +3702,15 @@ type serverStatus struct {
 	Version string     `json:"version"`
 	NodeId  string     `json:"nodeId"`
 	Uptime  string     `json:"uptime"`
+	// the ssl context of each acceptor, by acceptor name
+	TLS map[string]tlsStatus `json:"tls,omitempty"`
+}
+
+// tlsStatus is the certificate that an acceptor serves
+type tlsStatus struct {
+	Subject string `json:"subject"`
+	// the SHA-256 fingerprint of the certificate, in hex
+	Fingerprint string `json:"fingerprint"`
 }
 
 type jaasStatus struct {
@@ -3536,6 +3739,22 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessBrokerStatus(cr *v1beta2
 	// we need to requeue till stable
 	retry = meta.IsStatusConditionTrue(cr.Status.Conditions, v1beta2.ScaleDownPendingConditionType)
 
+	// and till held changes are applied in the maintenance window
+	retry = retry || meta.IsStatusConditionTrue(cr.Status.Conditions, v1beta2.PendingRestartConditionType)
+
+	// and till a held upgrade is applied when the schedule opens
+	if condition := meta.FindStatusCondition(cr.Status.Conditions, v1beta2.PendingUpgradeConditionType); condition != nil {
+		retry = retry || condition.Reason == v1beta2.PendingUpgradeConditionOutsideScheduleReason
+	}
+
+	// and till the gateway accepts the routes, that do not depend on the brokers
+	retry = reconciler.processGatewayRoutesStatus(cr, client) || retry
+	reconciler.processExternalAddressesStatus(cr, client)
+	reconciler.processCertificatesStatus(cr, time.Now())
+
+	// a backup is never ready, the pairs report their state before the brokers are available
+	retry = reconciler.ProcessHAStatus(cr, client) || retry
+
 	err := AssertBrokersAvailable(cr, client)
 	if err != nil {
 		condition = trapErrorAsCondition(err, v1beta2.ConfigAppliedConditionType)
@@ -3570,8 +3789,13 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessBrokerStatus(cr *v1beta2
 	}
 	meta.SetStatusCondition(&cr.Status.Conditions, condition)
 
-	if _, _, found := getConfigExtraMount(cr, jaasConfigSuffix); found {
+	_, _, found := getConfigExtraMount(cr, jaasConfigSuffix)
+	securityJaasFiles := reconciler.getSecurityJaasFiles(cr)
+	if found || len(securityJaasFiles) > 0 {
 		err = reconciler.AssertJaasPropertiesStatus(cr, client, scheme)
+		if err == nil && len(securityJaasFiles) > 0 {
+			err = reconciler.AssertSecurityJaasPropertiesStatus(cr, client, securityJaasFiles)
+		}
 		if err == nil {
 			condition = metav1.Condition{
 				Type:   v1beta2.JaasConfigAppliedConditionType,
@@ -3586,6 +3810,11 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessBrokerStatus(cr *v1beta2
 		meta.SetStatusCondition(&cr.Status.Conditions, condition)
 	}
 
+	retry = reconciler.ProcessBrokersReachableStatus(cr) || retry
+	retry = reconciler.processServedCertificatesStatus(cr, client) || retry
+	retry = reconciler.ProcessBrokerConnectionsStatus(cr, client) || retry
+	retry = reconciler.ProcessRolloutStatus(cr, client, scheme) || retry
+
 	// transition to check for empty after config for sig term updated
 	scaleDownCondtion := meta.FindStatusCondition(cr.Status.Conditions, v1beta2.ScaleDownPendingConditionType)
 	if scaleDownCondtion != nil {
@@ -3730,7 +3959,7 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) AssertBrokerPropertiesStatus(cr
 	})
 
 	if errorStatus == nil {
-		for _, extraSecretName := range cr.Spec.DeploymentPlan.ExtraMounts.Secrets {
+		for _, extraSecretName := range reconciler.extraMountSecrets(cr) {
 			if strings.HasSuffix(extraSecretName, common.BrokerPropsSuffix) {
 				secretProjection, err = reconciler.getSecretProjection(types.NamespacedName{Name: extraSecretName, Namespace: cr.Namespace}, client)
 				if err != nil {
@@ -3762,6 +3991,9 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) AssertJaasPropertiesStatus(cr *
 		reqLogger.V(2).Info("error retrieving config resources.")
 		return NewArtemisStatusError(err, false)
 	}
+	if Projection == nil {
+		return nil
+	}
 
 	statusError := reconciler.checkProjectionStatus(cr, client, Projection, func(BrokerStatus *brokerStatus, FileName string) (propertiesStatus, bool) {
 		current, present := BrokerStatus.ServerStatus.Jaas.PropertiesStatus[FileName]
@@ -3775,11 +4007,49 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) AssertJaasPropertiesStatus(cr *
 	return statusError
 }
 
+// AssertSecurityJaasPropertiesStatus checks that the brokers reloaded the users and roles files rendered from the
+// security CR into the broker properties secret
+func (reconciler *ActiveMQArtemisReconcilerImpl) AssertSecurityJaasPropertiesStatus(cr *v1beta2.Broker, client rtclient.Client, files []string) ArtemisError {
+	reqLogger := ctrl.Log.WithValues("ActiveMQArtemis Name", cr.Name)
+
+	secretProjection, err := reconciler.getSecretProjection(getPropertiesResourceNsName(cr), client)
+	if err != nil {
+		reqLogger.V(2).Info("error retrieving config resources.")
+		return NewArtemisStatusError(err, false)
+	}
+	jaasProjection := *secretProjection
+	jaasProjection.Files = map[string]propertyFile{}
+	for _, name := range files {
+		if file, found := secretProjection.Files[name]; found {
+			jaasProjection.Files[name] = file
+		}
+	}
+
+	return reconciler.checkProjectionStatus(cr, client, &jaasProjection, func(BrokerStatus *brokerStatus, FileName string) (propertiesStatus, bool) {
+		// the files are unchecked broker properties, a file that is not loaded yet is reported as out of sync
+		current := BrokerStatus.ServerStatus.Jaas.PropertiesStatus[FileName]
+		return current, true
+	})
+}
+
+// getSecurityJaasFiles lists the users and roles files of the security handler, they are only rendered in the
+// broker properties of restricted brokers
+func (reconciler *ActiveMQArtemisReconcilerImpl) getSecurityJaasFiles(cr *v1beta2.Broker) []string {
+	if !common.IsRestricted(cr) {
+		return nil
+	}
+	brokerConfigHandler := GetBrokerConfigHandler(types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace})
+	if brokerConfigHandler == nil {
+		return nil
+	}
+	return securityJaasFiles(brokerConfigHandler.BrokerProperties(""))
+}
+
 func (reconciler *ActiveMQArtemisReconcilerImpl) AssertBrokerImageVersion(cr *v1beta2.Broker, client rtclient.Client) ArtemisError {
 	reqLogger := ctrl.Log.WithValues("ActiveMQArtemis Name", cr.Name)
 
-	// The ResolveBrokerVersionFromCR should never fail because validation succeeded
-	resolvedFullVersion, _ := common.ResolveBrokerVersionFromCR(cr)
+	// The BrokerVersionToDeploy should never fail because validation succeeded
+	resolvedFullVersion, _ := common.BrokerVersionToDeploy(cr)
 
 	statusError := reconciler.CheckStatus(cr, client, func(brokerStatus *brokerStatus, jk *jolokia_client.JkInfo) ArtemisError {
 
@@ -3841,24 +4111,31 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) GetAndCacheBrokerStatus(jk *jol
 		}
 	}
 
-	currentJson, err := jk.Artemis.GetStatus()
+	// the status and the HA state of the broker in one request
+	state, err := jk.Artemis.GetBrokerState()
 
 	if err != nil {
-		reconciler.log.V(1).Info("error getting broker status with Jolokia", "IP", jk.IP, "Ordinal", jk.Ordinal, "error", err)
+		var circuitOpen *jolokia.CircuitOpenError
+		if errors.As(err, &circuitOpen) {
+			reconciler.log.V(2).Info("skipped getting broker status with Jolokia", "IP", jk.IP, "Ordinal", jk.Ordinal, "error", err)
+		} else {
+			reconciler.log.V(1).Info("error getting broker status with Jolokia", "IP", jk.IP, "Ordinal", jk.Ordinal, "error", err)
+		}
 		artemisError := NewArtemisStatusError(err, true)
 		reconciler.cachedBrokerStatus[jk.Ordinal] = artemisError
 		return nil, artemisError
 	}
 
-	reconciler.log.V(2).Info("raw json status", "IP", jk.IP, "ordinal", jk.Ordinal, "status json", currentJson)
+	reconciler.log.V(2).Info("raw json status", "IP", jk.IP, "ordinal", jk.Ordinal, "status json", state.Status)
 
-	brokerStatus, err := unmarshallStatus(currentJson)
+	brokerStatus, err := unmarshallStatus(state.Status)
 	if err != nil {
-		reconciler.log.Error(err, "unable to unmarshall broker status", "json", currentJson)
+		reconciler.log.Error(err, "unable to unmarshall broker status", "json", state.Status)
 		artemisError := NewArtemisStatusError(err, false)
 		reconciler.cachedBrokerStatus[jk.Ordinal] = artemisError
 		return nil, artemisError
 	}
+	brokerStatus.HA = state.HAState
 
 	reconciler.log.V(2).Info("cached broker status", "ordinal", jk.Ordinal, "status", brokerStatus)
 	reconciler.cachedBrokerStatus[jk.Ordinal] = brokerStatus
@@ -3869,23 +4146,27 @@ func (reconciler *ActiveMQArtemisReconcilerImpl) GetAndCacheBrokerStatus(jk *jol
 
 func (reconciler *ActiveMQArtemisReconcilerImpl) resolveJolokiaEndpoints(cr *v1beta2.Broker, client rtclient.Client) {
 	if reconciler.jolokiaEndpoints == nil {
-		if common.IsRestricted(cr) {
-			reconciler.jolokiaEndpoints = jolokia_client.GetMinimalJolokiaAgents(cr, client)
-		} else {
-			resource := types.NamespacedName{
-				Name:      cr.Name,
-				Namespace: cr.Namespace,
-			}
-			reconciler.jolokiaEndpoints = jolokia_client.GetBrokers(resource, []ss.StatefulSetInfo{
-				{
-					NamespacedName: types.NamespacedName{Name: namer.CrToSS(cr.Name), Namespace: cr.Namespace},
-					Replicas:       cr.Status.DeploymentPlanSize, // this means we wait till the pod status is good before trying the jolokia endpoint
-					Labels:         nil,
-				}}, client)
-		}
+		reconciler.jolokiaEndpoints = reconciler.getEndpoints(cr, client)
 	}
 }
 
+// jolokiaEndpointsOf returns the jolokia endpoints of the brokers
+func jolokiaEndpointsOf(cr *v1beta2.Broker, client rtclient.Client) []*jolokia_client.JkInfo {
+	if common.IsRestricted(cr) {
+		return jolokia_client.GetMinimalJolokiaAgents(cr, client)
+	}
+	resource := types.NamespacedName{
+		Name:      cr.Name,
+		Namespace: cr.Namespace,
+	}
+	return jolokia_client.GetBrokers(resource, []ss.StatefulSetInfo{
+		{
+			NamespacedName: types.NamespacedName{Name: namer.CrToSS(cr.Name), Namespace: cr.Namespace},
+			Replicas:       cr.Status.DeploymentPlanSize, // this means we wait till the pod status is good before trying the jolokia endpoint
+			Labels:         nil,
+		}}, client)
+}
+
 func (reconciler *ActiveMQArtemisReconcilerImpl) checkProjectionStatus(cr *v1beta2.Broker, client rtclient.Client, secretProjection *projection, extractStatus func(BrokerStatus *brokerStatus, FileName string) (propertiesStatus, bool)) ArtemisError {
 	reqLogger := ctrl.Log.WithValues("ActiveMQArtemis Name", cr.Name)
 
@@ -0,0 +1,203 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/namer"
+)
+
+const defaultRolloutProgressDeadlineSecs = 600
+
+func isCanaryRollout(customResource *v1beta2.Broker) bool {
+	return customResource.Spec.Rollout != nil && customResource.Spec.Rollout.Canary
+}
+
+// the partition that holds back every broker but the highest ordinal, so the
+// next change to the pod template is only applied to the canary
+func rolloutArmedPartition(customResource *v1beta2.Broker) int32 {
+	return max(common.GetDeploymentSize(customResource)-1, 0)
+}
+
+// the statefulset controller moves the current revision to the update revision
+// once every pod runs the update revision
+func isRolloutInProgress(statefulSet *appsv1.StatefulSet) bool {
+	return statefulSet.ResourceVersion != "" &&
+		statefulSet.Status.UpdateRevision != "" &&
+		statefulSet.Status.UpdateRevision != statefulSet.Status.CurrentRevision
+}
+
+// configureRolloutPartition sets the partition of the rolling update from the rollout status,
+// the partition only moves down once the broker at the partition is verified
+func (reconciler *ActiveMQArtemisReconcilerImpl) configureRolloutPartition(customResource *v1beta2.Broker, statefulSet *appsv1.StatefulSet) {
+	if !isCanaryRollout(customResource) {
+		if rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil {
+			rollingUpdate.Partition = nil
+		}
+		return
+	}
+
+	partition := rolloutArmedPartition(customResource)
+	if isRolloutInProgress(statefulSet) {
+		status := customResource.Status.Upgrade.Rollout
+		if status != nil && status.Revision == statefulSet.Status.UpdateRevision {
+			partition = min(status.Partition, partition)
+		}
+	}
+
+	statefulSet.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: &partition,
+		},
+	}
+}
+
+// ProcessRolloutStatus verifies the broker at the partition of a canary rollout and
+// moves the partition to the next ordinal, the rollout is paused when a broker is
+// not verified within the progress deadline
+func (reconciler *ActiveMQArtemisReconcilerImpl) ProcessRolloutStatus(cr *v1beta2.Broker, client rtclient.Client, scheme *runtime.Scheme) (retry bool) {
+	if !isCanaryRollout(cr) {
+		cr.Status.Upgrade.Rollout = nil
+		return false
+	}
+
+	obj := reconciler.getFromDeployed(reflect.TypeOf(appsv1.StatefulSet{}), namer.CrToSS(cr.Name))
+	if obj == nil {
+		return false
+	}
+	statefulSet := obj.(*appsv1.StatefulSet)
+
+	if !isRolloutInProgress(statefulSet) {
+		cr.Status.Upgrade.Rollout = &v1beta2.RolloutStatus{
+			Revision:       statefulSet.Status.CurrentRevision,
+			Partition:      rolloutArmedPartition(cr),
+			UpdatedBrokers: common.GetDeploymentSize(cr),
+		}
+		return false
+	}
+
+	now := time.Now()
+	status := cr.Status.Upgrade.Rollout
+	if status == nil || status.Revision != statefulSet.Status.UpdateRevision {
+		// a new revision starts again from the highest ordinal
+		status = &v1beta2.RolloutStatus{
+			Revision:         statefulSet.Status.UpdateRevision,
+			Partition:        rolloutArmedPartition(cr),
+			LastProgressTime: &metav1.Time{Time: now},
+		}
+		cr.Status.Upgrade.Rollout = status
+	}
+	status.UpdatedBrokers = statefulSet.Status.UpdatedReplicas
+	status.Partition = min(status.Partition, rolloutArmedPartition(cr))
+
+	if cr.Spec.Rollout.Paused {
+		status.Paused = true
+		status.Failed = false
+		status.Message = "rollout paused by .Spec.Rollout.Paused"
+		return false
+	}
+	if status.Failed {
+		return false
+	}
+	if status.Paused || status.LastProgressTime == nil {
+		status.Paused = false
+		status.LastProgressTime = &metav1.Time{Time: now}
+	}
+
+	podName := namer.CrToSSOrdinal(cr.Name, int(status.Partition))
+	if err := reconciler.checkRolloutOrdinal(cr, client, scheme, statefulSet, status.Partition); err != nil {
+		deadline := time.Duration(int32OrDefault(cr.Spec.Rollout.ProgressDeadlineSeconds, defaultRolloutProgressDeadlineSecs)) * time.Second
+		if now.Sub(status.LastProgressTime.Time) > deadline {
+			status.Paused = true
+			status.Failed = true
+			status.Message = fmt.Sprintf("rollout paused, %s was not updated to revision %s within %s: %v", podName, status.Revision, deadline, err)
+			reconciler.log.Info("canary rollout paused", "pod", podName, "revision", status.Revision, "reason", err.Error())
+			return false
+		}
+		status.Message = fmt.Sprintf("waiting for %s, %v", podName, err)
+		return true
+	}
+
+	if status.Partition > 0 {
+		status.Partition--
+		status.LastProgressTime = &metav1.Time{Time: now}
+		status.Message = fmt.Sprintf("%s updated to revision %s", podName, status.Revision)
+		reconciler.log.V(1).Info("canary rollout progressed", "pod", podName, "partition", status.Partition)
+	} else {
+		status.Message = fmt.Sprintf("all brokers updated to revision %s", status.Revision)
+	}
+	return true
+}
+
+// checkRolloutOrdinal verifies that the pod of the ordinal runs the update revision, is ready
+// and that its broker reports the resolved version and the applied broker properties
+func (reconciler *ActiveMQArtemisReconcilerImpl) checkRolloutOrdinal(cr *v1beta2.Broker, client rtclient.Client, scheme *runtime.Scheme, statefulSet *appsv1.StatefulSet, ordinal int32) error {
+	podName := namer.CrToSSOrdinal(cr.Name, int(ordinal))
+
+	pod := &corev1.Pod{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: podName, Namespace: cr.Namespace}, pod); err != nil {
+		return errors.Wrap(err, "unable to retrieve the pod")
+	}
+	if revision := pod.Labels[appsv1.ControllerRevisionHashLabelKey]; revision != statefulSet.Status.UpdateRevision {
+		return errors.Errorf("the pod is on revision %s", revision)
+	}
+	if !common.IsPodReady(pod) {
+		return errors.New("the pod is not ready")
+	}
+
+	reconciler.resolveJolokiaEndpoints(cr, client)
+
+	var endpoint *jolokia_client.JkInfo
+	for _, jk := range reconciler.jolokiaEndpoints {
+		if jk.Ordinal == strconv.Itoa(int(ordinal)) {
+			endpoint = jk
+		}
+	}
+	if endpoint == nil {
+		return errors.New("no Jolokia Client available")
+	}
+
+	// restrict the status checks to the broker of the ordinal
+	endpoints := reconciler.jolokiaEndpoints
+	reconciler.jolokiaEndpoints = []*jolokia_client.JkInfo{endpoint}
+	defer func() {
+		reconciler.jolokiaEndpoints = endpoints
+	}()
+
+	if err := reconciler.AssertBrokerImageVersion(cr, client); err != nil {
+		return err
+	}
+	if err := reconciler.AssertBrokerPropertiesStatus(cr, client, scheme); err != nil {
+		return err
+	}
+	return nil
+}
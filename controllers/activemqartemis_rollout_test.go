@@ -0,0 +1,151 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func rolloutStatefulSet(current string, update string, updated int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "ro-ss", Namespace: "test", ResourceVersion: "1"},
+		Status: appsv1.StatefulSetStatus{
+			CurrentRevision: current,
+			UpdateRevision:  update,
+			UpdatedReplicas: updated,
+		},
+	}
+}
+
+func rolloutPod(ordinal string, revision string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ro-ss-" + ordinal,
+			Namespace: "test",
+			Labels:    map[string]string{appsv1.ControllerRevisionHashLabelKey: revision},
+		},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestConfigureRolloutPartition(t *testing.T) {
+	cr := newTestBroker("ro", 3, nil)
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	ss := &appsv1.StatefulSet{}
+	ri.configureRolloutPartition(cr, ss)
+	assert.Nil(t, ss.Spec.UpdateStrategy.RollingUpdate)
+
+	// armed on the highest ordinal
+	cr.Spec.Rollout = &v1beta2.RolloutType{Canary: true}
+	ri.configureRolloutPartition(cr, ss)
+	assert.Equal(t, appsv1.RollingUpdateStatefulSetStrategyType, ss.Spec.UpdateStrategy.Type)
+	assert.Equal(t, int32(2), *ss.Spec.UpdateStrategy.RollingUpdate.Partition)
+
+	// in progress, follows the verified ordinals
+	ss = rolloutStatefulSet("rev-1", "rev-2", 1)
+	cr.Status.Upgrade.Rollout = &v1beta2.RolloutStatus{Revision: "rev-2", Partition: 1}
+	ri.configureRolloutPartition(cr, ss)
+	assert.Equal(t, int32(1), *ss.Spec.UpdateStrategy.RollingUpdate.Partition)
+
+	// a new revision starts from the highest ordinal
+	ss = rolloutStatefulSet("rev-1", "rev-3", 1)
+	ri.configureRolloutPartition(cr, ss)
+	assert.Equal(t, int32(2), *ss.Spec.UpdateStrategy.RollingUpdate.Partition)
+
+	// disabled
+	cr.Spec.Rollout = nil
+	ri.configureRolloutPartition(cr, ss)
+	assert.Nil(t, ss.Spec.UpdateStrategy.RollingUpdate.Partition)
+}
+
+func TestProcessRolloutStatus(t *testing.T) {
+	deadline := int32(60)
+	cr := newTestBroker("ro", 3, func(candidate *v1beta2.Broker) {
+		candidate.Spec.Rollout = &v1beta2.RolloutType{Canary: true, ProgressDeadlineSeconds: &deadline}
+	})
+
+	r := NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift)
+	ri := NewActiveMQArtemisReconcilerImpl(cr, r)
+
+	// complete
+	ri.deployed = make(map[reflect.Type][]client.Object)
+	ri.addToDeployed(reflect.TypeOf(appsv1.StatefulSet{}), rolloutStatefulSet("rev-1", "rev-1", 3))
+	assert.False(t, ri.ProcessRolloutStatus(cr, nil, nil))
+	assert.Equal(t, &v1beta2.RolloutStatus{Revision: "rev-1", Partition: 2, UpdatedBrokers: 3}, cr.Status.Upgrade.Rollout)
+
+	// canary pod not ready
+	ri = NewActiveMQArtemisReconcilerImpl(cr, r)
+	ri.deployed = make(map[reflect.Type][]client.Object)
+	ri.addToDeployed(reflect.TypeOf(appsv1.StatefulSet{}), rolloutStatefulSet("rev-1", "rev-2", 1))
+	fakeClient := newTestClient(rolloutPod("2", "rev-2", false))
+	assert.True(t, ri.ProcessRolloutStatus(cr, fakeClient, nil))
+	status := cr.Status.Upgrade.Rollout
+	assert.Equal(t, "rev-2", status.Revision)
+	assert.Equal(t, int32(2), status.Partition)
+	assert.Equal(t, int32(1), status.UpdatedBrokers)
+	assert.False(t, status.Paused)
+	assert.Contains(t, status.Message, "ro-ss-2")
+	assert.Contains(t, status.Message, "not ready")
+
+	// paused on failure after the deadline
+	status.LastProgressTime = &metav1.Time{Time: time.Now().Add(-2 * time.Minute)}
+	assert.False(t, ri.ProcessRolloutStatus(cr, fakeClient, nil))
+	assert.True(t, status.Paused)
+	assert.True(t, status.Failed)
+	assert.Equal(t, int32(2), status.Partition)
+	assert.Contains(t, status.Message, "rollout paused")
+
+	// stays paused
+	assert.False(t, ri.ProcessRolloutStatus(cr, fakeClient, nil))
+	assert.True(t, status.Failed)
+
+	// resumed by the spec with a fresh deadline
+	cr.Spec.Rollout.Paused = true
+	assert.False(t, ri.ProcessRolloutStatus(cr, fakeClient, nil))
+	assert.True(t, status.Paused)
+	assert.False(t, status.Failed)
+	cr.Spec.Rollout.Paused = false
+	assert.True(t, ri.ProcessRolloutStatus(cr, fakeClient, nil))
+	assert.False(t, status.Paused)
+	assert.WithinDuration(t, time.Now(), status.LastProgressTime.Time, time.Minute)
+
+	// canary on the previous revision
+	fakeClient = newTestClient(rolloutPod("2", "rev-1", true))
+	assert.True(t, ri.ProcessRolloutStatus(cr, fakeClient, nil))
+	assert.Contains(t, status.Message, "revision rev-1")
+
+	// canary disabled
+	cr.Spec.Rollout = nil
+	assert.False(t, ri.ProcessRolloutStatus(cr, fakeClient, nil))
+	assert.Nil(t, cr.Status.Upgrade.Rollout)
+}
@@ -0,0 +1,124 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+func validateUpdatePolicy(customResource *v1beta2.Broker) (*metav1.Condition, bool) {
+	policy := customResource.Spec.UpdatePolicy
+	if policy == nil || policy.Mode != v1beta2.UpdatePolicyModeSchedule {
+		return nil, false
+	}
+
+	invalid := func(message string) (*metav1.Condition, bool) {
+		return &metav1.Condition{
+			Type:    v1beta2.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta2.ValidConditionFailedInvalidUpdatePolicy,
+			Message: message,
+		}, false
+	}
+
+	if policy.Schedule == nil {
+		return invalid(".Spec.UpdatePolicy.Schedule is required with the schedule mode")
+	}
+	if _, err := parseMaintenanceWindow(policy.Schedule); err != nil {
+		return invalid(".Spec.UpdatePolicy.Schedule." + err.Error())
+	}
+	return nil, false
+}
+
+// holdBrokerUpgrade records a newer version that the update policy does not allow yet as the pending version, the
+// brokers stay on the deployed version till the upgrade is approved or the schedule opens
+func (reconciler *ActiveMQArtemisReconcilerImpl) holdBrokerUpgrade(customResource *v1beta2.Broker, now time.Time) {
+	customResource.Status.Upgrade.PendingVersion = ""
+
+	policy := customResource.Spec.UpdatePolicy
+	pending, found := common.PendingBrokerUpgrade(customResource)
+	deployed := customResource.Status.Version.BrokerVersion
+	if policy == nil || !found || !isAutomaticUpdate(&customResource.Status.Upgrade, deployed, pending) {
+		meta.RemoveStatusCondition(&customResource.Status.Conditions, v1beta2.PendingUpgradeConditionType)
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:   v1beta2.PendingUpgradeConditionType,
+		Status: metav1.ConditionTrue,
+	}
+
+	switch policy.Mode {
+	case v1beta2.UpdatePolicyModeApprovalRequired:
+		if customResource.Annotations[common.ApproveUpgradeAnnotation] == pending {
+			meta.RemoveStatusCondition(&customResource.Status.Conditions, v1beta2.PendingUpgradeConditionType)
+			return
+		}
+		condition.Reason = v1beta2.PendingUpgradeConditionAwaitingApprovalReason
+		condition.Message = fmt.Sprintf("version %s is available, the brokers stay on version %s until the %s annotation is set to %s",
+			pending, deployed, common.ApproveUpgradeAnnotation, pending)
+
+	case v1beta2.UpdatePolicyModeSchedule:
+		if policy.Schedule == nil {
+			meta.RemoveStatusCondition(&customResource.Status.Conditions, v1beta2.PendingUpgradeConditionType)
+			return
+		}
+		schedule, err := parseMaintenanceWindow(policy.Schedule)
+		if err != nil || schedule.isOpen(now) {
+			meta.RemoveStatusCondition(&customResource.Status.Conditions, v1beta2.PendingUpgradeConditionType)
+			return
+		}
+		condition.Reason = v1beta2.PendingUpgradeConditionOutsideScheduleReason
+		condition.Message = fmt.Sprintf("version %s is available, the brokers stay on version %s until the schedule opens at %s",
+			pending, deployed, schedule.nextOpening(now).Format(time.RFC3339))
+
+	default:
+		meta.RemoveStatusCondition(&customResource.Status.Conditions, v1beta2.PendingUpgradeConditionType)
+		return
+	}
+
+	reconciler.log.V(1).Info("holding broker upgrade", "deployed", deployed, "pending", pending, "reason", condition.Reason)
+
+	customResource.Status.Upgrade.PendingVersion = pending
+	meta.SetStatusCondition(&customResource.Status.Conditions, condition)
+}
+
+// isAutomaticUpdate checks the update from the deployed version to the pending version against the updates that
+// .Status.Upgrade allows, the update policy only holds the automatic updates
+func isAutomaticUpdate(upgrade *v1beta2.UpgradeStatus, deployed string, pending string) bool {
+	from, ferr := semver.Parse(deployed)
+	to, terr := semver.Parse(pending)
+	if ferr != nil || terr != nil || !upgrade.SecurityUpdates {
+		return false
+	}
+
+	switch {
+	case from.Major != to.Major:
+		return upgrade.MajorUpdates
+	case from.Minor != to.Minor:
+		return upgrade.MinorUpdates
+	case from.Patch != to.Patch:
+		return upgrade.PatchUpdates
+	}
+	return true
+}
@@ -0,0 +1,142 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+func TestValidateUpdatePolicy(t *testing.T) {
+	cr := &v1beta2.Broker{}
+	condition, retry := validateUpdatePolicy(cr)
+	assert.Nil(t, condition)
+	assert.False(t, retry)
+
+	cr.Spec.UpdatePolicy = &v1beta2.UpdatePolicyType{Mode: v1beta2.UpdatePolicyModeApprovalRequired}
+	condition, _ = validateUpdatePolicy(cr)
+	assert.Nil(t, condition)
+
+	cr.Spec.UpdatePolicy = &v1beta2.UpdatePolicyType{Mode: v1beta2.UpdatePolicyModeSchedule}
+	condition, _ = validateUpdatePolicy(cr)
+	assert.NotNil(t, condition)
+	assert.Equal(t, v1beta2.ValidConditionFailedInvalidUpdatePolicy, condition.Reason)
+
+	cr.Spec.UpdatePolicy.Schedule = &v1beta2.MaintenanceWindowType{StartTime: "2am", Duration: "2h"}
+	condition, _ = validateUpdatePolicy(cr)
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, ".Spec.UpdatePolicy.Schedule.StartTime")
+
+	cr.Spec.UpdatePolicy.Schedule.StartTime = "02:00"
+	condition, _ = validateUpdatePolicy(cr)
+	assert.Nil(t, condition)
+}
+
+func TestHoldBrokerUpgrade(t *testing.T) {
+	t.Cleanup(common.ResetImageCatalog)
+	common.SetImageCatalog([]common.CatalogImages{{
+		Version: semver.MustParse("2.53.1"),
+		Image:   "quay.io/arkmq-org/arkmq-org-broker-kubernetes:artemis.2.53.1",
+	}}, nil)
+
+	cr := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "held", Namespace: "test"},
+		Spec: v1beta2.BrokerSpec{
+			Version:      "2.53",
+			UpdatePolicy: &v1beta2.UpdatePolicyType{Mode: v1beta2.UpdatePolicyModeApprovalRequired},
+		},
+	}
+	cr.Status.Version.BrokerVersion = "2.53.0"
+	cr.Status.Upgrade.SecurityUpdates = true
+	cr.Status.Upgrade.PatchUpdates = true
+	ri := NewActiveMQArtemisReconcilerImpl(cr, NewActiveMQArtemisReconciler(&NillCluster{}, ctrl.Log, isOpenshift))
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	// held till approved
+	ri.holdBrokerUpgrade(cr, now)
+	assert.Equal(t, "2.53.1", cr.Status.Upgrade.PendingVersion)
+	condition := meta.FindStatusCondition(cr.Status.Conditions, v1beta2.PendingUpgradeConditionType)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionTrue, condition.Status)
+		assert.Equal(t, v1beta2.PendingUpgradeConditionAwaitingApprovalReason, condition.Reason)
+		assert.Contains(t, condition.Message, common.ApproveUpgradeAnnotation)
+	}
+	resolved, err := common.ResolveBrokerVersionFromCR(cr)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.53.1", resolved)
+	toDeploy, err := common.BrokerVersionToDeploy(cr)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.53.0", toDeploy)
+	assert.NotContains(t, common.ResolveImage(cr, common.BrokerImageKey), "2.53.1")
+
+	// an approval of another version does not apply
+	cr.Annotations = map[string]string{common.ApproveUpgradeAnnotation: "2.53.2"}
+	ri.holdBrokerUpgrade(cr, now)
+	assert.Equal(t, "2.53.1", cr.Status.Upgrade.PendingVersion)
+
+	cr.Annotations[common.ApproveUpgradeAnnotation] = "2.53.1"
+	ri.holdBrokerUpgrade(cr, now)
+	assert.Empty(t, cr.Status.Upgrade.PendingVersion)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, v1beta2.PendingUpgradeConditionType))
+	toDeploy, _ = common.BrokerVersionToDeploy(cr)
+	assert.Equal(t, "2.53.1", toDeploy)
+	assert.Equal(t, "quay.io/arkmq-org/arkmq-org-broker-kubernetes:artemis.2.53.1", common.ResolveImage(cr, common.BrokerImageKey))
+
+	// held till the schedule opens
+	cr.Spec.UpdatePolicy = &v1beta2.UpdatePolicyType{
+		Mode:     v1beta2.UpdatePolicyModeSchedule,
+		Schedule: &v1beta2.MaintenanceWindowType{StartTime: "02:00", Duration: "1h"},
+	}
+	ri.holdBrokerUpgrade(cr, now)
+	assert.Equal(t, "2.53.1", cr.Status.Upgrade.PendingVersion)
+	condition = meta.FindStatusCondition(cr.Status.Conditions, v1beta2.PendingUpgradeConditionType)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, v1beta2.PendingUpgradeConditionOutsideScheduleReason, condition.Reason)
+		assert.Contains(t, condition.Message, "2026-10-15T02:00:00Z")
+	}
+
+	ri.holdBrokerUpgrade(cr, time.Date(2026, 10, 15, 2, 30, 0, 0, time.UTC))
+	assert.Empty(t, cr.Status.Upgrade.PendingVersion)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, v1beta2.PendingUpgradeConditionType))
+
+	// an update that the upgrade status does not allow is not held
+	cr.Spec.UpdatePolicy.Mode = v1beta2.UpdatePolicyModeApprovalRequired
+	cr.Status.Upgrade.PatchUpdates = false
+	ri.holdBrokerUpgrade(cr, now)
+	assert.Empty(t, cr.Status.Upgrade.PendingVersion)
+	assert.Nil(t, meta.FindStatusCondition(cr.Status.Conditions, v1beta2.PendingUpgradeConditionType))
+	cr.Status.Upgrade.PatchUpdates = true
+
+	// a version that no longer matches the spec is not held
+	cr.Spec.Version = "2.54"
+	cr.Spec.UpdatePolicy.Mode = v1beta2.UpdatePolicyModeApprovalRequired
+	ri.holdBrokerUpgrade(cr, now)
+	assert.Empty(t, cr.Status.Upgrade.PendingVersion)
+
+	// the auto mode applies the newer version
+	cr.Spec.Version = "2.53"
+	cr.Spec.UpdatePolicy.Mode = v1beta2.UpdatePolicyModeAuto
+	ri.holdBrokerUpgrade(cr, now)
+	assert.Empty(t, cr.Status.Upgrade.PendingVersion)
+}
@@ -18,8 +18,12 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	brokerv1beta1 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
 	ss "github.com/arkmq-org/activemq-artemis-operator/pkg/resources/statefulsets"
 	mgmt "github.com/arkmq-org/activemq-artemis-operator/pkg/utils/artemis"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/channels"
@@ -31,9 +35,12 @@ import (
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/selectors"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -134,6 +141,8 @@ func (r *ActiveMQArtemisAddressReconciler) Reconcile(ctx context.Context, reques
 	err = r.createQueue(&addressDeployment, request, r.Client)
 	if nil == err {
 		namespacedNameToAddressName[request.NamespacedName] = addressDeployment
+		// the status update changed the resource version
+		instance.ResourceVersion = addressDeployment.AddressResource.ResourceVersion
 		crstr, merr := common.ToJson(instance)
 		if merr != nil {
 			reqLogger.Error(merr, "failed to marshal cr")
@@ -197,32 +206,96 @@ func (r *ActiveMQArtemisAddressReconciler) SetupWithManager(mgr ctrl.Manager, ct
 		Complete(r)
 }
 
-// This method deals with creating queues and addresses.
+// This method deals with creating queues and addresses, the state on each broker is recorded in the status.
 func (r *ActiveMQArtemisAddressReconciler) createQueue(instance *AddressDeployment, request ctrl.Request, client client.Client) error {
 
 	r.log.V(1).Info("Creating ActiveMQArtemisAddress")
 
-	var err error = nil
+	addressRes := &instance.AddressResource
+	previous := map[string]*brokerv1beta1.AddressBrokerStatus{}
+	for i := range addressRes.Status.Brokers {
+		previous[addressRes.Status.Brokers[i].Pod] = &addressRes.Status.Brokers[i]
+	}
+
+	var brokers []brokerv1beta1.AddressBrokerStatus
+	var errs []error
+	now := time.Now()
 	artemisArray := r.getPodBrokers(instance, request, client)
-	if nil != artemisArray {
-		for _, a := range artemisArray {
-			if nil == a {
-				r.log.V(1).Info("Creating ActiveMQArtemisAddress artemisArray had a nil!")
-				continue
-			}
-			err = createAddressResource(a, &instance.AddressResource, r.log)
+	for _, a := range artemisArray {
+		if nil == a {
+			r.log.V(1).Info("Creating ActiveMQArtemisAddress artemisArray had a nil!")
+			continue
+		}
+		status := applyAddressResource(a, addressRes, previous[addressBrokerPod(a)], now, r.log)
+		if !status.Applied {
+			r.log.V(1).Info("Failed to create address resource", "failed broker", status.Pod)
+			errs = append(errs, fmt.Errorf("%s: %s", status.Pod, status.Error))
+		}
+		brokers = append(brokers, status)
+	}
+
+	if !equality.Semantic.DeepEqual(addressRes.Status.Brokers, brokers) {
+		addressRes.Status.Brokers = brokers
+		if err := resources.UpdateStatus(client, addressRes); err != nil {
+			r.log.V(1).Info("unable to update the address status", "error", err)
+		}
+	}
+
+	if len(errs) == 0 {
+		r.log.V(1).Info("Successfully created resources on all brokers", "size", len(artemisArray))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// the broker pod name is the first label of the ordinal fqdn
+func addressBrokerPod(a *jc.JkInfo) string {
+	pod, _, _ := strings.Cut(a.IP, ".")
+	return pod
+}
+
+// applyAddressResource creates the address or queue on a broker, once applied for the current generation
+// it is only recreated when it was removed from the broker out of band
+func applyAddressResource(a *jc.JkInfo, addressRes *brokerv1beta1.ActiveMQArtemisAddress, previous *brokerv1beta1.AddressBrokerStatus, now time.Time, log logr.Logger) brokerv1beta1.AddressBrokerStatus {
+	status := brokerv1beta1.AddressBrokerStatus{Pod: addressBrokerPod(a)}
+	repair := false
+	if previous != nil {
+		status.LastAppliedTime = previous.LastAppliedTime
+		status.LastRepairTime = previous.LastRepairTime
+
+		if previous.Applied && previous.ObservedGeneration == addressRes.Generation {
+			exists, err := addressResourceExists(a, addressRes)
 			if err != nil {
-				r.log.V(1).Info("Failed to create address resource", "failed broker", a)
-				continue
+				log.V(1).Info("unable to check the address resource, keeping its state", "broker", status.Pod, "error", err)
+				return *previous
 			}
+			if exists {
+				return *previous
+			}
+			log.Info("Recreating the address resource removed from the broker", "broker", status.Pod, "address", addressRes.Spec.AddressName)
+			repair = true
 		}
 	}
 
-	if err == nil {
-		r.log.V(1).Info("Successfully created resources on all brokers", "size", len(artemisArray))
+	if err := createAddressResource(a, addressRes, log); err != nil {
+		status.Error = err.Error()
+		return status
 	}
 
-	return err
+	status.Applied = true
+	status.ObservedGeneration = addressRes.Generation
+	status.LastAppliedTime = &metav1.Time{Time: now}
+	if repair {
+		status.LastRepairTime = &metav1.Time{Time: now}
+	}
+	return status
+}
+
+func addressResourceExists(a *jc.JkInfo, addressRes *brokerv1beta1.ActiveMQArtemisAddress) (bool, error) {
+	if addressRes.Spec.QueueName == nil || *addressRes.Spec.QueueName == "" {
+		return a.Artemis.AddressExists(addressRes.Spec.AddressName)
+	}
+	return a.Artemis.QueueExists(*addressRes.Spec.QueueName)
 }
 
 func createAddressResource(a *jc.JkInfo, addressRes *brokerv1beta1.ActiveMQArtemisAddress, log logr.Logger) error {
@@ -389,7 +462,17 @@ func (r *ActiveMQArtemisAddressReconciler) getPodBrokers(instance *AddressDeploy
 	reqLogger.V(2).Info("target Cr names", "result", targetCrNamespacedNames)
 	ssInfos := ss.GetDeployedStatefulSetNames(client, request.Namespace, targetCrNamespacedNames)
 
-	return jc.GetBrokers(request.NamespacedName, ssInfos, client)
+	// the addresses of the brokers that opted in to the broker properties backend are not applied over jolokia
+	jolokiaSsInfos := []ss.StatefulSetInfo{}
+	for _, info := range ssInfos {
+		if isAddressPropertiesBackend(client, types.NamespacedName{Name: namer.SSToCr(info.NamespacedName.Name), Namespace: info.NamespacedName.Namespace}) {
+			reqLogger.V(2).Info("skipping broker with the broker properties backend", "ss", info.NamespacedName.Name)
+			continue
+		}
+		jolokiaSsInfos = append(jolokiaSsInfos, info)
+	}
+
+	return jc.GetBrokers(request.NamespacedName, jolokiaSsInfos, client)
 }
 
 func createTargetCrNamespacedNames(namespace string, targetCrNames []string, log logr.Logger) []types.NamespacedName {
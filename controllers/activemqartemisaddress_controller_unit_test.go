@@ -22,8 +22,12 @@ import (
 	"time"
 
 	"github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/artemis"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia"
+	jc "github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
 	"github.com/go-logr/logr"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -124,3 +128,66 @@ func TestDeleteAddressWithInternalError(t *testing.T) {
 	assert.False(t, result.Requeue)
 	assert.Equal(t, time.Duration(0), result.RequeueAfter)
 }
+
+func TestApplyAddressResourceRepairsDrift(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	routingType := "anycast"
+	addressRes := &v1beta1.ActiveMQArtemisAddress{
+		ObjectMeta: v1.ObjectMeta{Name: "orders", Namespace: "test", Generation: 2},
+		Spec:       v1beta1.ActiveMQArtemisAddressSpec{AddressName: "orders", RoutingType: &routingType},
+	}
+	addressMBean := `org.apache.activemq.artemis:broker="amq",component=addresses,address="orders"`
+
+	j := jolokia.NewMockIJolokia(mockCtrl)
+	jk := &jc.JkInfo{Artemis: artemis.GetArtemisWithJolokia(j, "amq"), IP: "ex-aao-ss-0.ex-aao-hdls-svc.test.svc.cluster.local", Ordinal: "0"}
+	logger := logr.New(log.NullLogSink{})
+	applied := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	repaired := applied.Add(time.Hour)
+
+	// first apply
+	j.EXPECT().Exec(gomock.Any(), gomock.Any()).Return(&jolokia.ResponseData{Status: 200}, nil).Times(1)
+	status := applyAddressResource(jk, addressRes, nil, applied, logger)
+	assert.Equal(t, "ex-aao-ss-0", status.Pod)
+	assert.True(t, status.Applied)
+	assert.Equal(t, int64(2), status.ObservedGeneration)
+	assert.Equal(t, applied, status.LastAppliedTime.Time)
+	assert.Nil(t, status.LastRepairTime)
+
+	// still deployed, not applied again
+	j.EXPECT().Search(addressMBean).Return([]string{addressMBean}, nil).Times(1)
+	assert.Equal(t, status, applyAddressResource(jk, addressRes, &status, repaired, logger))
+
+	// removed out of band
+	j.EXPECT().Search(addressMBean).Return([]string{}, nil).Times(1)
+	j.EXPECT().Exec(gomock.Any(), gomock.Any()).Return(&jolokia.ResponseData{Status: 200}, nil).Times(1)
+	status = applyAddressResource(jk, addressRes, &status, repaired, logger)
+	assert.True(t, status.Applied)
+	assert.Equal(t, repaired, status.LastAppliedTime.Time)
+	assert.Equal(t, repaired, status.LastRepairTime.Time)
+
+	// a new generation is applied without a check, a failure is recorded
+	addressRes.Generation = 3
+	j.EXPECT().Exec(gomock.Any(), gomock.Any()).Return(&jolokia.ResponseData{Status: 500}, errors.New("broker unavailable")).Times(1)
+	failed := applyAddressResource(jk, addressRes, &status, repaired, logger)
+	assert.False(t, failed.Applied)
+	assert.Equal(t, "broker unavailable", failed.Error)
+	assert.Equal(t, status.LastRepairTime, failed.LastRepairTime)
+}
+
+func TestAddressResourceExistsForQueue(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	queueName := "orders.eu"
+	addressRes := &v1beta1.ActiveMQArtemisAddress{Spec: v1beta1.ActiveMQArtemisAddressSpec{AddressName: "orders", QueueName: &queueName}}
+
+	j := jolokia.NewMockIJolokia(mockCtrl)
+	jk := &jc.JkInfo{Artemis: artemis.GetArtemisWithJolokia(j, "amq"), IP: "ex-aao-ss-1", Ordinal: "1"}
+
+	j.EXPECT().Search(`org.apache.activemq.artemis:broker="amq",component=addresses,subcomponent=queues,queue="orders.eu",*`).Return(nil, nil).Times(1)
+	exists, err := addressResourceExists(jk, addressRes)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
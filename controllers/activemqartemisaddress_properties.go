@@ -0,0 +1,329 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	brokerv1beta1 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/secrets"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+// AddressPropertiesSecretName is the broker properties secret that holds the ActiveMQArtemisAddress resources
+// of a broker CR, listing it in .spec.deploymentPlan.extraMounts.secrets opts the broker CR in
+func AddressPropertiesSecretName(crName string) string {
+	return fmt.Sprintf("%s-addresses%s", crName, common.BrokerPropsSuffix)
+}
+
+// isRenderedPropertiesSecret checks whether a secret owned by the broker CR is rendered by another controller
+func isRenderedPropertiesSecret(cr *v1beta2.Broker, secretName string) bool {
+	return secretName == AddressPropertiesSecretName(cr.Name) ||
+		secretName == BrokerAddressesSecretName(cr.Name) ||
+		secretName == BrokerSecuritySecretName(cr.Name)
+}
+
+// addressPropertiesOwner returns the broker CR that opted in to the broker properties backend, nil otherwise
+func addressPropertiesOwner(client rtclient.Client, crName types.NamespacedName) rtclient.Object {
+	secretName := AddressPropertiesSecretName(crName.Name)
+
+	broker := &v1beta2.Broker{}
+	if err := client.Get(context.TODO(), crName, broker); err == nil {
+		if slices.Contains(broker.Spec.DeploymentPlan.ExtraMounts.Secrets, secretName) {
+			return broker
+		}
+	}
+	artemis := &brokerv1beta1.ActiveMQArtemis{}
+	if err := client.Get(context.TODO(), crName, artemis); err == nil {
+		if slices.Contains(artemis.Spec.DeploymentPlan.ExtraMounts.Secrets, secretName) {
+			return artemis
+		}
+	}
+	return nil
+}
+
+// isAddressPropertiesBackend checks whether the addresses of the broker CR are owned by its broker properties
+func isAddressPropertiesBackend(client rtclient.Client, crName types.NamespacedName) bool {
+	return addressPropertiesOwner(client, crName) != nil
+}
+
+func isAddressTargetingCr(addressRes *brokerv1beta1.ActiveMQArtemisAddress, crName string) bool {
+	if len(addressRes.Spec.ApplyToCrNames) == 0 {
+		return true
+	}
+	for _, name := range addressRes.Spec.ApplyToCrNames {
+		if name == "" || name == "*" || name == crName {
+			return true
+		}
+	}
+	return false
+}
+
+func addressRoutingType(addressRes *brokerv1beta1.ActiveMQArtemisAddress) string {
+	if addressRes.Spec.RoutingType == nil || *addressRes.Spec.RoutingType == "" {
+		return defaultRoutingType
+	}
+	return strings.ToUpper(*addressRes.Spec.RoutingType)
+}
+
+// kebab-case queue configuration keys are camelCase broker properties
+func queueConfigProperty(key string) string {
+	parts := strings.Split(key, "-")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// renderAddressProperties renders an ActiveMQArtemisAddress as addressConfigurations broker properties, the
+// routing types of the address are those of all the ActiveMQArtemisAddress resources with the same address
+func renderAddressProperties(addressRes *brokerv1beta1.ActiveMQArtemisAddress, routingTypes []string) ([]byte, error) {
+	if addressRes.Spec.AddressName == "" {
+		return nil, errors.New("spec.addressName is required with the broker properties backend")
+	}
+	buf := &bytes.Buffer{}
+	prefix := fmt.Sprintf("addressConfigurations.\"%s\".", addressRes.Spec.AddressName)
+	fmt.Fprintf(buf, "# %s\n", addressRes.Name)
+	fmt.Fprintf(buf, "%sroutingTypes=%s\n", prefix, strings.Join(routingTypes, ","))
+
+	if addressRes.Spec.QueueName == nil || *addressRes.Spec.QueueName == "" {
+		return buf.Bytes(), nil
+	}
+
+	// same defaults as a queue created over jolokia
+	queueRes := addressRes.DeepCopy()
+	defaultConfigurationManaged := true
+	if queueRes.Spec.QueueConfiguration == nil {
+		queueRes.Spec.QueueConfiguration = &brokerv1beta1.QueueConfigurationType{}
+	}
+	if queueRes.Spec.QueueConfiguration.ConfigurationManaged == nil {
+		queueRes.Spec.QueueConfiguration.ConfigurationManaged = &defaultConfigurationManaged
+	}
+	queueCfg, _, err := GetQueueConfig(queueRes)
+	if err != nil {
+		return nil, err
+	}
+	config := map[string]interface{}{}
+	decoder := json.NewDecoder(strings.NewReader(queueCfg))
+	decoder.UseNumber()
+	if err = decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		if key != "name" && key != "id" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(buf, "%squeueConfigs.\"%s\".%s=%v\n", prefix, *addressRes.Spec.QueueName, queueConfigProperty(key), config[key])
+	}
+	return buf.Bytes(), nil
+}
+
+// renderAddressPropertiesSecretData renders the ActiveMQArtemisAddress resources of a broker CR, one key for each,
+// a resource that can not be rendered is left out and its error is returned by name
+func renderAddressPropertiesSecretData(addresses []brokerv1beta1.ActiveMQArtemisAddress, crName string) (map[string][]byte, map[string]error) {
+	targets := []*brokerv1beta1.ActiveMQArtemisAddress{}
+	routingTypes := map[string][]string{}
+	for i := range addresses {
+		addressRes := &addresses[i]
+		if addressRes.DeletionTimestamp != nil || !isAddressTargetingCr(addressRes, crName) {
+			continue
+		}
+		targets = append(targets, addressRes)
+		address := addressRes.Spec.AddressName
+		if routingType := addressRoutingType(addressRes); !slices.Contains(routingTypes[address], routingType) {
+			routingTypes[address] = append(routingTypes[address], routingType)
+			sort.Strings(routingTypes[address])
+		}
+	}
+
+	data := map[string][]byte{}
+	invalid := map[string]error{}
+	for _, addressRes := range targets {
+		properties, err := renderAddressProperties(addressRes, routingTypes[addressRes.Spec.AddressName])
+		if err != nil {
+			invalid[addressRes.Name] = err
+			continue
+		}
+		data[addressRes.Name+".properties"] = properties
+	}
+	return data, invalid
+}
+
+// updateAddressesValidCondition reports the render errors with the Valid condition of the ActiveMQArtemisAddress
+// resources, the condition is set back to true once the resource is rendered
+func (r *AddressPropertiesReconciler) updateAddressesValidCondition(addresses []brokerv1beta1.ActiveMQArtemisAddress, crName string, invalid map[string]error) error {
+	for i := range addresses {
+		addressRes := &addresses[i]
+		if addressRes.DeletionTimestamp != nil || !isAddressTargetingCr(addressRes, crName) {
+			continue
+		}
+
+		previous := meta.FindStatusCondition(addressRes.Status.Conditions, brokerv1beta1.ValidConditionType)
+		condition := metav1.Condition{
+			Type:   brokerv1beta1.ValidConditionType,
+			Status: metav1.ConditionTrue,
+			Reason: brokerv1beta1.ValidConditionSuccessReason,
+		}
+		if err, found := invalid[addressRes.Name]; found {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = brokerv1beta1.ValidConditionFailureReason
+			condition.Message = fmt.Sprintf("unable to render the broker properties of %s, %v", crName, err)
+		} else if previous == nil {
+			continue
+		}
+		if previous != nil && previous.Status == condition.Status && previous.Reason == condition.Reason && previous.Message == condition.Message {
+			continue
+		}
+
+		meta.SetStatusCondition(&addressRes.Status.Conditions, condition)
+		if err := resources.UpdateStatus(r.Client, addressRes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddressPropertiesReconciler renders the ActiveMQArtemisAddress resources of the broker CRs that opted in
+// to the broker properties backend into their addresses secret
+type AddressPropertiesReconciler struct {
+	rtclient.Client
+	Scheme *runtime.Scheme
+	log    logr.Logger
+}
+
+func NewAddressPropertiesReconciler(client rtclient.Client, scheme *runtime.Scheme, logger logr.Logger) *AddressPropertiesReconciler {
+	return &AddressPropertiesReconciler{
+		Client: client,
+		Scheme: scheme,
+		log:    logger,
+	}
+}
+
+func (r *AddressPropertiesReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "Reconciling", "AddressProperties")
+
+	owner := addressPropertiesOwner(r.Client, request.NamespacedName)
+	if owner == nil {
+		return ctrl.Result{}, nil
+	}
+
+	addresses := &brokerv1beta1.ActiveMQArtemisAddressList{}
+	if err := r.List(ctx, addresses, rtclient.InNamespace(request.Namespace)); err != nil {
+		reqLogger.Error(err, "unable to list the ActiveMQArtemisAddress resources")
+		return ctrl.Result{}, err
+	}
+
+	data, invalid := renderAddressPropertiesSecretData(addresses.Items, request.Name)
+	for name, err := range invalid {
+		reqLogger.V(1).Info("unable to render the ActiveMQArtemisAddress", "address", name, "error", err)
+	}
+	if err := r.updateAddressesValidCondition(addresses.Items, request.Name, invalid); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	secretName := types.NamespacedName{Name: AddressPropertiesSecretName(request.Name), Namespace: request.Namespace}
+	existing := &corev1.Secret{}
+	if err := resources.Retrieve(secretName, r.Client, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		reqLogger.V(1).Info("creating the addresses secret", "secret", secretName.Name, "addresses", len(data))
+		return ctrl.Result{}, resources.Create(owner.(metav1.Object), r.Client, r.Scheme, secrets.NewSecret(secretName, data, nil))
+	}
+
+	if equality.Semantic.DeepEqual(existing.Data, data) || (len(existing.Data) == 0 && len(data) == 0) {
+		return ctrl.Result{}, nil
+	}
+	reqLogger.V(1).Info("updating the addresses secret", "secret", secretName.Name, "addresses", len(data))
+	existing.Data = data
+	return ctrl.Result{}, resources.Update(r.Client, existing)
+}
+
+// enqueueBrokersForAddress enqueues the broker CRs targeted by an ActiveMQArtemisAddress
+func (r *AddressPropertiesReconciler) enqueueBrokersForAddress() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj rtclient.Object) []reconcile.Request {
+		addressRes := obj.(*brokerv1beta1.ActiveMQArtemisAddress)
+
+		crNames := map[string]bool{}
+		if targets := createTargetCrNamespacedNames(addressRes.Namespace, addressRes.Spec.ApplyToCrNames, r.log); targets != nil {
+			for _, target := range targets {
+				crNames[target.Name] = true
+			}
+		} else {
+			brokers := &v1beta2.BrokerList{}
+			if err := r.List(ctx, brokers, rtclient.InNamespace(addressRes.Namespace)); err != nil {
+				r.log.Error(err, "Failed to list Brokers for address watch", "address", addressRes.Name)
+			}
+			for _, broker := range brokers.Items {
+				crNames[broker.Name] = true
+			}
+			artemises := &brokerv1beta1.ActiveMQArtemisList{}
+			if err := r.List(ctx, artemises, rtclient.InNamespace(addressRes.Namespace)); err != nil {
+				r.log.Error(err, "Failed to list ActiveMQArtemis for address watch", "address", addressRes.Name)
+			}
+			for _, artemis := range artemises.Items {
+				crNames[artemis.Name] = true
+			}
+		}
+
+		var requests []reconcile.Request
+		for crName := range crNames {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: addressRes.Namespace,
+					Name:      crName,
+				},
+			})
+		}
+		return requests
+	})
+}
+
+func (r *AddressPropertiesReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("addressproperties").
+		For(&v1beta2.Broker{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&brokerv1beta1.ActiveMQArtemis{}, &handler.EnqueueRequestForObject{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&brokerv1beta1.ActiveMQArtemisAddress{}, r.enqueueBrokersForAddress()).
+		Complete(r)
+}
@@ -0,0 +1,163 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	brokerv1beta1 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func propertiesAddress(name string, address string, queue string, routingType string, applyTo ...string) brokerv1beta1.ActiveMQArtemisAddress {
+	addressRes := brokerv1beta1.ActiveMQArtemisAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: brokerv1beta1.ActiveMQArtemisAddressSpec{
+			AddressName:    address,
+			RoutingType:    &routingType,
+			ApplyToCrNames: applyTo,
+		},
+	}
+	if queue != "" {
+		addressRes.Spec.QueueName = &queue
+	}
+	return addressRes
+}
+
+func TestRenderAddressPropertiesSecretData(t *testing.T) {
+	maxConsumers := int32(5)
+	queue := propertiesAddress("orders-queue", "orders", "orders", "anycast")
+	queue.Spec.QueueConfiguration = &brokerv1beta1.QueueConfigurationType{MaxConsumers: &maxConsumers}
+
+	data, invalid := renderAddressPropertiesSecretData([]brokerv1beta1.ActiveMQArtemisAddress{
+		queue,
+		propertiesAddress("orders-topic", "orders", "", "multicast"),
+		propertiesAddress("other", "other", "", "anycast", "other-broker"),
+		propertiesAddress("unnamed", "", "", "anycast"),
+	}, "ex-aao")
+	assert.Len(t, data, 2)
+	assert.Len(t, invalid, 1)
+	assert.ErrorContains(t, invalid["unnamed"], "spec.addressName")
+
+	assert.Equal(t, "# orders-topic\n"+
+		"addressConfigurations.\"orders\".routingTypes=ANYCAST,MULTICAST\n", string(data["orders-topic.properties"]))
+
+	assert.Equal(t, "# orders-queue\n"+
+		"addressConfigurations.\"orders\".routingTypes=ANYCAST,MULTICAST\n"+
+		"addressConfigurations.\"orders\".queueConfigs.\"orders\".address=orders\n"+
+		"addressConfigurations.\"orders\".queueConfigs.\"orders\".configurationManaged=true\n"+
+		"addressConfigurations.\"orders\".queueConfigs.\"orders\".maxConsumers=5\n"+
+		"addressConfigurations.\"orders\".queueConfigs.\"orders\".routingType=ANYCAST\n", string(data["orders-queue.properties"]))
+}
+
+func TestQueueConfigProperty(t *testing.T) {
+	assert.Equal(t, "routingType", queueConfigProperty("routing-type"))
+	assert.Equal(t, "groupRebalancePauseDispatch", queueConfigProperty("group-rebalance-pause-dispatch"))
+	assert.Equal(t, "durable", queueConfigProperty("durable"))
+}
+
+func TestAddressPropertiesReconcile(t *testing.T) {
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "ex-aao", Namespace: "test"},
+	}
+	broker.Spec.DeploymentPlan.ExtraMounts.Secrets = []string{AddressPropertiesSecretName("ex-aao")}
+	notOptedIn := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "jolokia", Namespace: "test"},
+	}
+
+	cl := newTestClient(broker, notOptedIn)
+	r := NewAddressPropertiesReconciler(cl, cl.Scheme(), ctrl.Log)
+	assert.True(t, isAddressPropertiesBackend(r.Client, types.NamespacedName{Name: "ex-aao", Namespace: "test"}))
+	assert.False(t, isAddressPropertiesBackend(r.Client, types.NamespacedName{Name: "jolokia", Namespace: "test"}))
+
+	secretKey := types.NamespacedName{Name: "ex-aao-addresses-bp", Namespace: "test"}
+	secret := &corev1.Secret{}
+
+	// the secret exists before any address
+	_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "ex-aao", Namespace: "test"}})
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(context.TODO(), secretKey, secret))
+	assert.Empty(t, secret.Data)
+	assert.Equal(t, "ex-aao", secret.OwnerReferences[0].Name)
+
+	addressRes := propertiesAddress("orders", "orders", "", "anycast")
+	assert.NoError(t, r.Create(context.TODO(), &addressRes))
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "ex-aao", Namespace: "test"}})
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(context.TODO(), secretKey, secret))
+	assert.Contains(t, string(secret.Data["orders.properties"]), "addressConfigurations.\"orders\".routingTypes=ANYCAST")
+
+	// an address that can not be rendered is reported on its status and left out
+	unnamed := propertiesAddress("unnamed", "", "", "anycast")
+	assert.NoError(t, r.Create(context.TODO(), &unnamed))
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "ex-aao", Namespace: "test"}})
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(context.TODO(), secretKey, secret))
+	assert.NotContains(t, secret.Data, "unnamed.properties")
+	assert.NoError(t, r.Get(context.TODO(), client.ObjectKeyFromObject(&unnamed), &unnamed))
+	condition := meta.FindStatusCondition(unnamed.Status.Conditions, brokerv1beta1.ValidConditionType)
+	if assert.NotNil(t, condition) {
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Contains(t, condition.Message, "spec.addressName")
+	}
+	assert.NoError(t, r.Get(context.TODO(), client.ObjectKeyFromObject(&addressRes), &addressRes))
+	assert.Nil(t, meta.FindStatusCondition(addressRes.Status.Conditions, brokerv1beta1.ValidConditionType))
+
+	unnamed.Spec.AddressName = "unnamed"
+	assert.NoError(t, r.Update(context.TODO(), &unnamed))
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "ex-aao", Namespace: "test"}})
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(context.TODO(), client.ObjectKeyFromObject(&unnamed), &unnamed))
+	assert.True(t, meta.IsStatusConditionTrue(unnamed.Status.Conditions, brokerv1beta1.ValidConditionType))
+	assert.NoError(t, r.Delete(context.TODO(), &unnamed))
+
+	// deleting the address removes its entry
+	assert.NoError(t, r.Delete(context.TODO(), &addressRes))
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "ex-aao", Namespace: "test"}})
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(context.TODO(), secretKey, secret))
+	assert.Empty(t, secret.Data)
+
+	// no secret for brokers that did not opt in
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "jolokia", Namespace: "test"}})
+	assert.NoError(t, err)
+	assert.Error(t, r.Get(context.TODO(), types.NamespacedName{Name: "jolokia-addresses-bp", Namespace: "test"}, &corev1.Secret{}))
+}
+
+func TestCurrentDeployedResourcesKeepsAddressesSecret(t *testing.T) {
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "ex-aao", Namespace: "test", UID: "ex-aao-uid"},
+	}
+	owned := []metav1.OwnerReference{{APIVersion: "arkmq.org/v1beta2", Kind: "Broker", Name: "ex-aao", UID: "ex-aao-uid"}}
+	addresses := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: AddressPropertiesSecretName("ex-aao"), Namespace: "test", OwnerReferences: owned}}
+	stale := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "stale", Namespace: "test", OwnerReferences: owned}}
+
+	cl := newTestClient(broker, addresses, stale)
+	r := NewAddressPropertiesReconciler(cl, cl.Scheme(), ctrl.Log)
+	ri := NewActiveMQArtemisReconcilerImpl(broker, &ActiveMQArtemisReconciler{Client: r.Client, Scheme: r.Scheme, log: ctrl.Log})
+	ri.CurrentDeployedResources(broker, r.Client)
+
+	assert.NotNil(t, ri.getFromDesired(reflect.TypeOf(&corev1.Secret{}), addresses.Name))
+	assert.Nil(t, ri.getFromDesired(reflect.TypeOf(&corev1.Secret{}), stale.Name))
+}
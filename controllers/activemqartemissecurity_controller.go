@@ -18,7 +18,15 @@ package controllers
 
 import (
 	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
 	"reflect"
+	"slices"
+	"sort"
+	"strings"
 
 	brokerv1beta1 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
@@ -37,6 +45,15 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// the defaults of the one-way codec of the broker
+	passwordHashIterations = 1024
+	passwordHashKeyLength  = 64
+	passwordSaltLength     = 32
 )
 
 // ActiveMQArtemisSecurityReconciler reconciles a ActiveMQArtemisSecurity object
@@ -98,13 +115,14 @@ func (r *ActiveMQArtemisSecurityReconciler) Reconcile(ctx context.Context, reque
 		instance,
 		request.NamespacedName,
 		r,
+		r.referencedSecretsVersion(instance),
 	}
 
 	if securityHandler := GetBrokerConfigHandler(request.NamespacedName); securityHandler == nil {
 		reqLogger.V(1).Info("Operator doesn't have the security handler, try retrive it from secret")
 		if existingHandler := lsrcrs.RetrieveLastSuccessfulReconciledCR(request.NamespacedName, "security", r.Client, getLabels(instance)); existingHandler != nil {
 			//compare resource version
-			if existingHandler.Checksum == instance.ResourceVersion {
+			if existingHandler.Checksum == reconciledChecksum(instance, newHandler.secretsVersion) {
 				reqLogger.V(2).Info("The incoming security CR is identical to stored CR, no reconcile")
 				toReconcile = false
 			}
@@ -137,7 +155,7 @@ func (r *ActiveMQArtemisSecurityReconciler) Reconcile(ctx context.Context, reque
 	}
 
 	lsrcrs.StoreLastSuccessfulReconciledCR(instance, instance.Name, instance.Namespace, "security",
-		crstr, string(data), instance.ResourceVersion, getLabels(instance), r.Client, r.Scheme)
+		crstr, string(data), reconciledChecksum(instance, newHandler.secretsVersion), getLabels(instance), r.Client, r.Scheme)
 
 	return ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}, nil
 }
@@ -146,6 +164,8 @@ type ActiveMQArtemisSecurityConfigHandler struct {
 	SecurityCR     *brokerv1beta1.ActiveMQArtemisSecurity
 	NamespacedName types.NamespacedName
 	owner          *ActiveMQArtemisSecurityReconciler
+	// the versions of the referenced secrets, a rotation replaces the handler
+	secretsVersion string
 }
 
 func getLabels(cr *brokerv1beta1.ActiveMQArtemisSecurity) map[string]string {
@@ -191,14 +211,41 @@ func (r *ActiveMQArtemisSecurityConfigHandler) processCrPasswords() *brokerv1bet
 		for i, pm := range result.Spec.LoginModules.PropertiesLoginModules {
 			if len(pm.Users) > 0 {
 				for j, user := range pm.Users {
-					if user.Password == nil {
+					if user.Password == nil && user.PasswordSecret != nil {
+						result.Spec.LoginModules.PropertiesLoginModules[i].Users[j].Password = r.getSecretValue(user.PasswordSecret.Name, user.PasswordSecret.Key)
+					}
+					if result.Spec.LoginModules.PropertiesLoginModules[i].Users[j].Password == nil {
 						result.Spec.LoginModules.PropertiesLoginModules[i].Users[j].Password = r.getPassword("security-properties-"+pm.Name, user.Name)
 					}
+					if pm.HashPasswords != nil && *pm.HashPasswords {
+						password := *result.Spec.LoginModules.PropertiesLoginModules[i].Users[j].Password
+						salt, err := r.getPasswordSalt(pm.Name, user.Name)
+						var hashed string
+						if err == nil {
+							hashed, err = hashPassword(password, salt)
+						}
+						if err == nil {
+							result.Spec.LoginModules.PropertiesLoginModules[i].Users[j].Password = &hashed
+						} else {
+							r.owner.log.Error(err, "failed to hash password, it is not hashed", "module", pm.Name, "user", user.Name)
+						}
+					}
 				}
 			}
 		}
 	}
 
+	for i, lm := range result.Spec.LoginModules.LdapLoginModules {
+		if lm.BindCredentialsSecret != nil {
+			if username := r.getSecretValue(*lm.BindCredentialsSecret, "username"); username != nil {
+				result.Spec.LoginModules.LdapLoginModules[i].ConnectionUsername = username
+			}
+			if password := r.getSecretValue(*lm.BindCredentialsSecret, "password"); password != nil {
+				result.Spec.LoginModules.LdapLoginModules[i].ConnectionPassword = password
+			}
+		}
+	}
+
 	if len(result.Spec.LoginModules.KeycloakLoginModules) > 0 {
 		for _, pm := range result.Spec.LoginModules.KeycloakLoginModules {
 			keycloakSecretName := "security-keycloak-" + pm.Name
@@ -235,8 +282,95 @@ func (r *ActiveMQArtemisSecurityConfigHandler) GetDefaultLabels() map[string]str
 
 }
 
+// retrieve a value from a secret provided by the user, nil when the secret or the key does not exist
+func (r *ActiveMQArtemisSecurityConfigHandler) getSecretValue(secretName string, key string) *string {
+	namespacedName := types.NamespacedName{
+		Name:      secretName,
+		Namespace: r.NamespacedName.Namespace,
+	}
+	secret := &corev1.Secret{}
+	if err := resources.Retrieve(namespacedName, r.owner.Client, secret); err != nil {
+		r.owner.log.Error(err, "failed to retrieve secret", "secret", secretName)
+		return nil
+	}
+	elem, ok := secret.Data[key]
+	if !ok {
+		r.owner.log.V(1).Info("secret has no key", "secret", secretName, "key", key)
+		return nil
+	}
+	value := string(elem)
+	return &value
+}
+
+// hashPassword encodes a password like the one-way codec of the broker, ENC(iterations:salt:hash) with PBKDF2WithHmacSHA1
+func hashPassword(password string, salt []byte) (string, error) {
+	hash, err := pbkdf2.Key(sha1.New, password, salt, passwordHashIterations, passwordHashKeyLength)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ENC(%d:%s:%s)", passwordHashIterations, hex.EncodeToString(salt), hex.EncodeToString(hash)), nil
+}
+
+// getPasswordSalt is the random salt of a user, it is kept in a secret of the module so that an unchanged password
+// does not change the rendered config and restart the brokers
+func (r *ActiveMQArtemisSecurityConfigHandler) getPasswordSalt(module string, user string) ([]byte, error) {
+	salt := r.getOrGenerateValue("security-salt-"+module, user, newPasswordSalt)
+	return hex.DecodeString(*salt)
+}
+
+func newPasswordSalt() string {
+	salt := make([]byte, passwordSaltLength)
+	rand.Read(salt)
+	return hex.EncodeToString(salt)
+}
+
+// referencedSecretsVersion tracks the secrets referenced by a security CR, a change of version is a rotation to apply
+func (r *ActiveMQArtemisSecurityReconciler) referencedSecretsVersion(security *brokerv1beta1.ActiveMQArtemisSecurity) string {
+	versions := []string{}
+	for _, name := range referencedSecrets(security) {
+		secret := &corev1.Secret{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: security.Namespace}, secret); err == nil {
+			versions = append(versions, name+"="+secret.ResourceVersion)
+		}
+	}
+	return strings.Join(versions, ",")
+}
+
+// reconciledChecksum is the checksum of a stored security CR, the versions of the referenced secrets are included so
+// a rotation while the operator is down is reconciled on restart
+func reconciledChecksum(security *brokerv1beta1.ActiveMQArtemisSecurity, secretsVersion string) string {
+	if secretsVersion == "" {
+		return security.ResourceVersion
+	}
+	return security.ResourceVersion + "," + secretsVersion
+}
+
+// referencedSecrets are the sorted names of the user provided secrets of a security CR
+func referencedSecrets(security *brokerv1beta1.ActiveMQArtemisSecurity) []string {
+	names := []string{}
+	for _, pm := range security.Spec.LoginModules.PropertiesLoginModules {
+		for _, user := range pm.Users {
+			if user.PasswordSecret != nil && !slices.Contains(names, user.PasswordSecret.Name) {
+				names = append(names, user.PasswordSecret.Name)
+			}
+		}
+	}
+	for _, lm := range security.Spec.LoginModules.LdapLoginModules {
+		if lm.BindCredentialsSecret != nil && !slices.Contains(names, *lm.BindCredentialsSecret) {
+			names = append(names, *lm.BindCredentialsSecret)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // retrive value from secret, generate value if not exist.
 func (r *ActiveMQArtemisSecurityConfigHandler) getPassword(secretName string, key string) *string {
+	return r.getOrGenerateValue(secretName, key, func() string { return random.GenerateRandomString(8) })
+}
+
+// getOrGenerateValue retrieves a value of a secret owned by the security CR, a missing value is generated and stored
+func (r *ActiveMQArtemisSecurityConfigHandler) getOrGenerateValue(secretName string, key string, generate func() string) *string {
 	//check if the secret exists.
 	namespacedName := types.NamespacedName{
 		Name:      secretName,
@@ -262,7 +396,7 @@ func (r *ActiveMQArtemisSecurityConfigHandler) getPassword(secretName string, ke
 		}
 	}
 	//now need generate value
-	value := random.GenerateRandomString(8)
+	value := generate()
 	//update the secret
 	if secretDefinition.Data == nil {
 		secretDefinition.Data = make(map[string][]byte)
@@ -317,5 +451,24 @@ func (r *ActiveMQArtemisSecurityConfigHandler) Config(initContainers []corev1.Co
 func (r *ActiveMQArtemisSecurityReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&brokerv1beta1.ActiveMQArtemisSecurity{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.securitiesReferencingSecret)).
 		Complete(r)
 }
+
+// securitiesReferencingSecret enqueues the security CRs of the namespace that reference a rotated secret
+func (r *ActiveMQArtemisSecurityReconciler) securitiesReferencingSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	securities := &brokerv1beta1.ActiveMQArtemisSecurityList{}
+	if err := r.List(ctx, securities, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.log.Error(err, "Failed to list security CRs for secret watch", "secret", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, security := range securities.Items {
+		if slices.Contains(referencedSecrets(&security), obj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: security.Namespace, Name: security.Name},
+			})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,322 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	brokerv1beta1 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	jaasModulePackage             = "org.apache.activemq.artemis.spi.core.security.jaas."
+	propertiesLoginModuleClass    = jaasModulePackage + "PropertiesLoginModule"
+	guestLoginModuleClass         = jaasModulePackage + "GuestLoginModule"
+	ldapLoginModuleClass          = jaasModulePackage + "LDAPLoginModule"
+	certificateLoginModuleClass   = jaasModulePackage + "TextFileCertificateLoginModule"
+	kerberosLoginModuleClass      = jaasModulePackage + "Krb5LoginModule"
+	kerberosAcceptorModuleClass   = "com.sun.security.auth.module.Krb5LoginModule"
+	kerberosAcceptorRealm         = "amqp-sasl-gssapi"
+	securityBrokerPropertiesKey   = "aa_security.properties"
+	defaultLoginModuleControlFlag = "required"
+)
+
+// jaasModuleParam is a login module option, they are rendered in order
+type jaasModuleParam struct {
+	key   string
+	value string
+}
+
+type jaasModule struct {
+	class  string
+	params []jaasModuleParam
+	// the module reads files of the properties secret, they change without a restart
+	reloadable bool
+}
+
+func (m *jaasModule) addParam(key string, value *string) {
+	if value != nil {
+		m.params = append(m.params, jaasModuleParam{key, *value})
+	}
+}
+
+func (m *jaasModule) addBoolParam(key string, value *bool) {
+	if value != nil {
+		m.params = append(m.params, jaasModuleParam{key, strconv.FormatBool(*value)})
+	}
+}
+
+// BrokerProperties renders the login modules of the broker domain as jaasConfigs broker properties, the files of the
+// modules are keys of the same secret
+func (r *ActiveMQArtemisSecurityConfigHandler) BrokerProperties(baseDir string) map[string][]byte {
+	return renderSecurityBrokerProperties(r.processCrPasswords(), baseDir)
+}
+
+// BrokerPropertiesOnlyModules are the ldap, certificate and kerberos modules of the broker domain, the init container
+// does not configure them
+func (r *ActiveMQArtemisSecurityConfigHandler) BrokerPropertiesOnlyModules() []string {
+	modules := &r.SecurityCR.Spec.LoginModules
+	names := []string{}
+	for _, ref := range r.SecurityCR.Spec.SecurityDomains.BrokerDomain.LoginModules {
+		if ref.Name == nil {
+			continue
+		}
+		for _, lm := range modules.LdapLoginModules {
+			if lm.Name == *ref.Name {
+				names = appendIfMissing(names, lm.Name)
+			}
+		}
+		for _, cm := range modules.CertificateLoginModules {
+			if cm.Name == *ref.Name {
+				names = appendIfMissing(names, cm.Name)
+			}
+		}
+		for _, km := range modules.KerberosLoginModules {
+			if km.Name == *ref.Name {
+				names = appendIfMissing(names, km.Name)
+			}
+		}
+	}
+	return names
+}
+
+func (r *ActiveMQArtemisSecurityConfigHandler) SecretsToMount() []string {
+	secrets := []string{}
+	modules := r.SecurityCR.Spec.LoginModules
+	for _, lm := range modules.LdapLoginModules {
+		if lm.TrustSecret != nil {
+			secrets = appendIfMissing(secrets, *lm.TrustSecret)
+		}
+	}
+	for _, km := range modules.KerberosLoginModules {
+		if km.KeyTabSecret != nil {
+			secrets = appendIfMissing(secrets, *km.KeyTabSecret)
+		}
+	}
+	return secrets
+}
+
+// TrustSecret is the trust secret of the first LDAP module that has one, the JVM has a single default trust store
+func (r *ActiveMQArtemisSecurityConfigHandler) TrustSecret() *string {
+	for _, lm := range r.SecurityCR.Spec.LoginModules.LdapLoginModules {
+		if lm.TrustSecret != nil {
+			return lm.TrustSecret
+		}
+	}
+	return nil
+}
+
+func appendIfMissing(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// securityJaasFiles are the keys of the users and roles files in the broker properties of a security handler
+func securityJaasFiles(brokerProperties map[string][]byte) []string {
+	files := []string{}
+	for key := range brokerProperties {
+		if key != securityBrokerPropertiesKey {
+			files = append(files, key)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+func renderSecurityBrokerProperties(security *brokerv1beta1.ActiveMQArtemisSecurity, baseDir string) map[string][]byte {
+	data := map[string][]byte{}
+	realm := common.JaasRealm
+	if name := security.Spec.SecurityDomains.BrokerDomain.Name; name != nil && *name != "" {
+		realm = *name
+	}
+
+	buf := NewPropsWithHeader()
+	acceptorBuf := &bytes.Buffer{}
+	rendered := false
+	for _, ref := range security.Spec.SecurityDomains.BrokerDomain.LoginModules {
+		if ref.Name == nil {
+			continue
+		}
+		module := securityLoginModule(security, *ref.Name, baseDir, data)
+		if module == nil {
+			// keycloak modules are only supported with an init container
+			continue
+		}
+		controlFlag := defaultLoginModuleControlFlag
+		if ref.Flag != nil {
+			controlFlag = *ref.Flag
+		}
+		module.addBoolParam("debug", ref.Debug)
+		if ref.Reload == nil && module.reloadable {
+			module.addBoolParam("reload", ptr.To(true))
+		} else {
+			module.addBoolParam("reload", ref.Reload)
+		}
+		writeJaasModule(buf, realm, *ref.Name, controlFlag, module)
+		rendered = true
+
+		if acceptor := kerberosAcceptorModule(security, *ref.Name); acceptor != nil {
+			writeJaasModule(acceptorBuf, kerberosAcceptorRealm, *ref.Name, defaultLoginModuleControlFlag, acceptor)
+		}
+	}
+	buf.Write(acceptorBuf.Bytes())
+
+	if rendered {
+		data[securityBrokerPropertiesKey] = buf.Bytes()
+	}
+	return data
+}
+
+func writeJaasModule(buf *bytes.Buffer, realm string, name string, controlFlag string, module *jaasModule) {
+	prefix := fmt.Sprintf("jaasConfigs.\"%s\".modules.\"%s\".", realm, name)
+	fmt.Fprintf(buf, "%sloginModuleClass=%s\n", prefix, module.class)
+	fmt.Fprintf(buf, "%scontrolFlag=%s\n", prefix, controlFlag)
+	for _, param := range module.params {
+		fmt.Fprintf(buf, "%sparams.\"%s\"=%s\n", prefix, param.key, param.value)
+	}
+}
+
+// securityLoginModule finds a login module by name and adds the files it needs to the data
+func securityLoginModule(security *brokerv1beta1.ActiveMQArtemisSecurity, name string, baseDir string, data map[string][]byte) *jaasModule {
+	modules := &security.Spec.LoginModules
+
+	for _, pm := range modules.PropertiesLoginModules {
+		if pm.Name == name {
+			usersKey, rolesKey := fmt.Sprintf("_%s-users", name), fmt.Sprintf("_%s-roles", name)
+			users, roles := NewPropsWithHeader(), NewPropsWithHeader()
+			roleUsers := map[string][]string{}
+			roleNames := []string{}
+			for _, user := range pm.Users {
+				if user.Password != nil {
+					fmt.Fprintf(users, "%s=%s\n", user.Name, *user.Password)
+				}
+				for _, role := range user.Roles {
+					if _, found := roleUsers[role]; !found {
+						roleNames = append(roleNames, role)
+					}
+					roleUsers[role] = append(roleUsers[role], user.Name)
+				}
+			}
+			for _, role := range roleNames {
+				fmt.Fprintf(roles, "%s=%s\n", role, strings.Join(roleUsers[role], ","))
+			}
+			data[usersKey], data[rolesKey] = users.Bytes(), roles.Bytes()
+			return &jaasModule{class: propertiesLoginModuleClass, reloadable: true, params: []jaasModuleParam{
+				{"org.apache.activemq.jaas.properties.user", usersKey},
+				{"org.apache.activemq.jaas.properties.role", rolesKey},
+				{"baseDir", baseDir},
+			}}
+		}
+	}
+
+	for _, gm := range modules.GuestLoginModules {
+		if gm.Name == name {
+			module := &jaasModule{class: guestLoginModuleClass}
+			module.addParam("org.apache.activemq.jaas.guest.user", gm.GuestUser)
+			module.addParam("org.apache.activemq.jaas.guest.role", gm.GuestRole)
+			return module
+		}
+	}
+
+	for _, lm := range modules.LdapLoginModules {
+		if lm.Name == name {
+			module := &jaasModule{class: ldapLoginModuleClass, params: []jaasModuleParam{
+				{"initialContextFactory", "com.sun.jndi.ldap.LdapCtxFactory"},
+			}}
+			module.addParam("connectionURL", lm.ConnectionURL)
+			module.addParam("connectionUsername", lm.ConnectionUsername)
+			module.addParam("connectionPassword", lm.ConnectionPassword)
+			module.addParam("connectionProtocol", lm.ConnectionProtocol)
+			module.addParam("authentication", lm.Authentication)
+			module.addParam("userBase", lm.UserBase)
+			module.addParam("userSearchMatching", lm.UserSearchMatching)
+			module.addBoolParam("userSearchSubtree", lm.UserSearchSubtree)
+			module.addParam("roleBase", lm.RoleBase)
+			module.addParam("roleName", lm.RoleName)
+			module.addParam("roleSearchMatching", lm.RoleSearchMatching)
+			module.addBoolParam("roleSearchSubtree", lm.RoleSearchSubtree)
+			module.addBoolParam("authenticateUser", lm.AuthenticateUser)
+			module.addParam("referral", lm.Referral)
+			module.addBoolParam("expandRoles", lm.ExpandRoles)
+			module.addParam("expandRolesMatching", lm.ExpandRolesMatching)
+			return module
+		}
+	}
+
+	for _, cm := range modules.CertificateLoginModules {
+		if cm.Name == name {
+			usersKey, rolesKey := fmt.Sprintf("_%s-users", name), fmt.Sprintf("_%s-roles", name)
+			users, roles := NewPropsWithHeader(), NewPropsWithHeader()
+			roleUsers := map[string][]string{}
+			roleNames := []string{}
+			for _, user := range cm.Users {
+				fmt.Fprintf(users, "%s=%s\n", user.Name, user.DN)
+				for _, role := range user.Roles {
+					if _, found := roleUsers[role]; !found {
+						roleNames = append(roleNames, role)
+					}
+					roleUsers[role] = append(roleUsers[role], user.Name)
+				}
+			}
+			for _, role := range roleNames {
+				fmt.Fprintf(roles, "%s=%s\n", role, strings.Join(roleUsers[role], ","))
+			}
+			data[usersKey], data[rolesKey] = users.Bytes(), roles.Bytes()
+			return &jaasModule{class: certificateLoginModuleClass, reloadable: true, params: []jaasModuleParam{
+				{"org.apache.activemq.jaas.textfiledn.user", usersKey},
+				{"org.apache.activemq.jaas.textfiledn.role", rolesKey},
+				{"baseDir", baseDir},
+			}}
+		}
+	}
+
+	for _, km := range modules.KerberosLoginModules {
+		if km.Name == name {
+			return &jaasModule{class: kerberosLoginModuleClass}
+		}
+	}
+	return nil
+}
+
+// kerberosAcceptorModule is the login of the broker service principal that accepts the GSSAPI connections
+func kerberosAcceptorModule(security *brokerv1beta1.ActiveMQArtemisSecurity, name string) *jaasModule {
+	for _, km := range security.Spec.LoginModules.KerberosLoginModules {
+		if km.Name == name && km.Principal != nil {
+			module := &jaasModule{class: kerberosAcceptorModuleClass, params: []jaasModuleParam{
+				{"isInitiator", "false"},
+				{"storeKey", "true"},
+				{"doNotPrompt", "true"},
+			}}
+			module.addParam("principal", km.Principal)
+			if km.KeyTabSecret != nil {
+				module.params = append(module.params,
+					jaasModuleParam{"useKeyTab", "true"},
+					jaasModuleParam{"keyTab", common.SecretPathBase + *km.KeyTabSecret + "/keytab"})
+			}
+			return module
+		}
+	}
+	return nil
+}
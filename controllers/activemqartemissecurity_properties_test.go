@@ -0,0 +1,292 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	brokerv1beta1 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRenderSecurityBrokerProperties(t *testing.T) {
+	security := &brokerv1beta1.ActiveMQArtemisSecurity{
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{{
+					Name: "props",
+					Users: []brokerv1beta1.UserType{
+						{Name: "bob", Password: ptr.To("pw"), Roles: []string{"admins", "users"}},
+						{Name: "joe", Password: ptr.To("pw2"), Roles: []string{"users"}},
+					},
+				}},
+				LdapLoginModules: []brokerv1beta1.LdapLoginModuleType{{
+					Name:               "ldap",
+					ConnectionURL:      ptr.To("ldaps://ldap.example.com:636"),
+					ConnectionUsername: ptr.To("cn=admin"),
+					ConnectionPassword: ptr.To("secret"),
+					UserSearchSubtree:  ptr.To(true),
+				}},
+				CertificateLoginModules: []brokerv1beta1.CertificateLoginModuleType{{
+					Name:  "cert",
+					Users: []brokerv1beta1.CertificateUserType{{Name: "app", DN: "CN=app, O=example", Roles: []string{"apps"}}},
+				}},
+				KerberosLoginModules: []brokerv1beta1.KerberosLoginModuleType{{
+					Name:         "krb",
+					Principal:    ptr.To("amqp/broker@EXAMPLE.COM"),
+					KeyTabSecret: ptr.To("broker-keytab"),
+				}},
+				KeycloakLoginModules: []brokerv1beta1.KeycloakLoginModuleType{{Name: "keycloak"}},
+			},
+			SecurityDomains: brokerv1beta1.SecurityDomainsType{
+				BrokerDomain: brokerv1beta1.BrokerDomainType{
+					Name: ptr.To("domain"),
+					LoginModules: []brokerv1beta1.LoginModuleReferenceType{
+						{Name: ptr.To("props"), Flag: ptr.To("sufficient"), Reload: ptr.To(true)},
+						{Name: ptr.To("ldap"), Flag: ptr.To("sufficient")},
+						{Name: ptr.To("cert"), Flag: ptr.To("sufficient")},
+						{Name: ptr.To("krb")},
+						{Name: ptr.To("keycloak")},
+					},
+				},
+			},
+		},
+	}
+
+	data := renderSecurityBrokerProperties(security, "/amq/extra/secrets/cr-props")
+
+	props := string(data[securityBrokerPropertiesKey])
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"props\".loginModuleClass="+propertiesLoginModuleClass+"\n")
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"props\".controlFlag=sufficient\n")
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"props\".params.\"org.apache.activemq.jaas.properties.user\"=_props-users\n")
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"props\".params.\"baseDir\"=/amq/extra/secrets/cr-props\n")
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"props\".params.\"reload\"=true\n")
+
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"ldap\".loginModuleClass="+ldapLoginModuleClass+"\n")
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"ldap\".params.\"connectionURL\"=ldaps://ldap.example.com:636\n")
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"ldap\".params.\"connectionPassword\"=secret\n")
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"ldap\".params.\"userSearchSubtree\"=true\n")
+	assert.NotContains(t, props, "jaasConfigs.\"domain\".modules.\"ldap\".params.\"roleBase\"")
+
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"cert\".loginModuleClass="+certificateLoginModuleClass+"\n")
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"cert\".params.\"org.apache.activemq.jaas.textfiledn.user\"=_cert-users\n")
+
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"krb\".loginModuleClass="+kerberosLoginModuleClass+"\n")
+	assert.Contains(t, props, "jaasConfigs.\"domain\".modules.\"krb\".controlFlag=required\n")
+	assert.Contains(t, props, "jaasConfigs.\""+kerberosAcceptorRealm+"\".modules.\"krb\".params.\"principal\"=amqp/broker@EXAMPLE.COM\n")
+	assert.Contains(t, props, "jaasConfigs.\""+kerberosAcceptorRealm+"\".modules.\"krb\".params.\"keyTab\"="+common.SecretPathBase+"broker-keytab/keytab\n")
+
+	assert.NotContains(t, props, "keycloak")
+
+	assert.Contains(t, string(data["_props-users"]), "bob=pw\njoe=pw2\n")
+	assert.Contains(t, string(data["_props-roles"]), "admins=bob\nusers=bob,joe\n")
+	assert.Contains(t, string(data["_cert-users"]), "app=CN=app, O=example\n")
+	assert.Contains(t, string(data["_cert-roles"]), "apps=app\n")
+}
+
+func TestRenderSecurityBrokerPropertiesNoModules(t *testing.T) {
+	assert.Empty(t, renderSecurityBrokerProperties(&brokerv1beta1.ActiveMQArtemisSecurity{}, "/base"))
+}
+
+func TestSecurityLoginModulesSecrets(t *testing.T) {
+	handler := &ActiveMQArtemisSecurityConfigHandler{SecurityCR: &brokerv1beta1.ActiveMQArtemisSecurity{
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				LdapLoginModules: []brokerv1beta1.LdapLoginModuleType{
+					{Name: "ldap", TrustSecret: ptr.To("ldap-ca")},
+					{Name: "ldap2", TrustSecret: ptr.To("ldap-ca")},
+				},
+				KerberosLoginModules: []brokerv1beta1.KerberosLoginModuleType{{Name: "krb", KeyTabSecret: ptr.To("broker-keytab")}},
+			},
+		},
+	}}
+	assert.Equal(t, []string{"ldap-ca", "broker-keytab"}, handler.SecretsToMount())
+	assert.Equal(t, "ldap-ca", *handler.TrustSecret())
+
+	trustSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "ldap-ca", Namespace: "test"},
+		Data:       map[string][]byte{"ca.pem": []byte("pem")},
+	}
+	client := fake.NewClientBuilder().WithObjects(trustSecret).Build()
+
+	broker := &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"}, Spec: v1beta2.BrokerSpec{Restricted: ptr.To(true)}}
+	cmd, props, err := loginModulesTrustStore(broker, handler, client)
+	assert.NoError(t, err)
+	assert.Contains(t, cmd, "keytool -importkeystore")
+	assert.Contains(t, cmd, "done < "+common.SecretPathBase+"ldap-ca/ca.pem")
+	assert.Equal(t, []string{
+		"-Djavax.net.ssl.trustStore=" + loginModulesTrustStorePath,
+		"-Djavax.net.ssl.trustStorePassword=" + loginModulesTrustStorePassword,
+		"-Djavax.net.ssl.trustStoreType=PKCS12",
+	}, props)
+	assert.NotContains(t, cmd, "security.provider")
+
+	trustSecret.Data = map[string][]byte{"client.ts": []byte("jks")}
+	client = fake.NewClientBuilder().WithObjects(trustSecret).Build()
+	_, _, err = loginModulesTrustStore(broker, handler, client)
+	assert.ErrorContains(t, err, "ldap-ca")
+}
+
+func TestValidateSecurityLoginModules(t *testing.T) {
+	handler := &ActiveMQArtemisSecurityConfigHandler{
+		NamespacedName: types.NamespacedName{Name: "sec", Namespace: "test"},
+		owner:          &ActiveMQArtemisSecurityReconciler{log: ctrl.Log},
+		SecurityCR: &brokerv1beta1.ActiveMQArtemisSecurity{
+			ObjectMeta: metav1.ObjectMeta{Name: "sec", Namespace: "test"},
+			Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+				LoginModules: brokerv1beta1.LoginModulesType{
+					PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{{Name: "props"}},
+					LdapLoginModules:       []brokerv1beta1.LdapLoginModuleType{{Name: "ldap"}},
+					KerberosLoginModules:   []brokerv1beta1.KerberosLoginModuleType{{Name: "krb"}},
+				},
+				SecurityDomains: brokerv1beta1.SecurityDomainsType{
+					BrokerDomain: brokerv1beta1.BrokerDomainType{
+						LoginModules: []brokerv1beta1.LoginModuleReferenceType{{Name: ptr.To("props")}, {Name: ptr.To("ldap")}},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []string{"ldap"}, handler.BrokerPropertiesOnlyModules())
+
+	broker := &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: "broker", Namespace: "test"}}
+	brokerName := types.NamespacedName{Name: broker.Name, Namespace: broker.Namespace}
+	assert.Nil(t, validateSecurityLoginModules(broker))
+
+	namespaceToConfigHandler[brokerName] = handler
+	defer delete(namespaceToConfigHandler, brokerName)
+
+	condition := validateSecurityLoginModules(broker)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, v1beta2.ValidConditionFailedUnsupportedLoginModules, condition.Reason)
+	assert.Contains(t, condition.Message, "ldap")
+
+	broker.Spec.Restricted = ptr.To(true)
+	assert.Nil(t, validateSecurityLoginModules(broker))
+}
+
+func TestHashPassword(t *testing.T) {
+	hashed, err := hashPassword("secret", bytes.Repeat([]byte{1}, 32))
+	assert.NoError(t, err)
+	assert.Equal(t, "ENC(1024:"+strings.Repeat("01", 32)+":"+
+		"cfffbdc1a9eaeee0569675fecece9e6c5e63cf83a2167d887b273c729d6f97fd773821b4fd9f6137124fd8ab870a236994c4b3db150387c74d02be7b2a70c7bb)",
+		hashed)
+}
+
+func TestProcessCrPasswordsFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "bob-password", Namespace: "test"},
+		Data:       map[string][]byte{"password": []byte("rotated")},
+	}
+	security := &brokerv1beta1.ActiveMQArtemisSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: "sec", Namespace: "test", UID: "uid"},
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{{
+					Name:          "props",
+					HashPasswords: ptr.To(true),
+					Users: []brokerv1beta1.UserType{{
+						Name: "bob",
+						PasswordSecret: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "bob-password"},
+							Key:                  "password",
+						},
+					}},
+				}},
+			},
+		},
+	}
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = brokerv1beta1.AddToScheme(s)
+	reconciler := NewActiveMQArtemisSecurityReconciler(fake.NewClientBuilder().WithScheme(s).WithObjects(secret).Build(), s, nil, ctrl.Log)
+	handler := &ActiveMQArtemisSecurityConfigHandler{
+		SecurityCR:     security,
+		NamespacedName: types.NamespacedName{Name: "sec", Namespace: "test"},
+		owner:          reconciler,
+	}
+
+	processed := handler.processCrPasswords()
+	saltSecret := &corev1.Secret{}
+	assert.NoError(t, reconciler.Get(context.TODO(), types.NamespacedName{Name: "security-salt-props", Namespace: "test"}, saltSecret))
+	salt, err := hex.DecodeString(string(saltSecret.Data["bob"]))
+	assert.NoError(t, err)
+	assert.Len(t, salt, passwordSaltLength)
+	hashed, _ := hashPassword("rotated", salt)
+	assert.Equal(t, hashed,
+		*processed.Spec.LoginModules.PropertiesLoginModules[0].Users[0].Password)
+	assert.NotContains(t, hashed, "rotated")
+	assert.Nil(t, security.Spec.LoginModules.PropertiesLoginModules[0].Users[0].Password)
+
+	// the persisted salt keeps the hash of an unchanged password
+	assert.Equal(t, processed, handler.processCrPasswords())
+
+	assert.Equal(t, []string{"bob-password"}, referencedSecrets(security))
+	version := reconciler.referencedSecretsVersion(security)
+	assert.Contains(t, version, "bob-password=")
+	checksum := reconciledChecksum(security, version)
+
+	secret.Data["password"] = []byte("rotated again")
+	assert.NoError(t, reconciler.Update(context.TODO(), secret))
+	assert.NotEqual(t, version, reconciler.referencedSecretsVersion(security))
+	assert.NotEqual(t, checksum, reconciledChecksum(security, reconciler.referencedSecretsVersion(security)))
+
+	requests := reconciler.securitiesReferencingSecret(context.TODO(), secret)
+	assert.Empty(t, requests)
+	assert.NoError(t, reconciler.Create(context.TODO(), security.DeepCopy()))
+	requests = reconciler.securitiesReferencingSecret(context.TODO(), secret)
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "sec", Namespace: "test"}}}, requests)
+}
+
+func TestRenderSecurityBrokerPropertiesReload(t *testing.T) {
+	security := &brokerv1beta1.ActiveMQArtemisSecurity{
+		Spec: brokerv1beta1.ActiveMQArtemisSecuritySpec{
+			LoginModules: brokerv1beta1.LoginModulesType{
+				PropertiesLoginModules: []brokerv1beta1.PropertiesLoginModuleType{{Name: "props"}, {Name: "static"}},
+			},
+			SecurityDomains: brokerv1beta1.SecurityDomainsType{
+				BrokerDomain: brokerv1beta1.BrokerDomainType{
+					LoginModules: []brokerv1beta1.LoginModuleReferenceType{
+						{Name: ptr.To("props")},
+						{Name: ptr.To("static"), Reload: ptr.To(false)},
+					},
+				},
+			},
+		},
+	}
+
+	data := renderSecurityBrokerProperties(security, "/base")
+
+	props := string(data[securityBrokerPropertiesKey])
+	assert.Contains(t, props, "jaasConfigs.\"activemq\".modules.\"props\".params.\"reload\"=true\n")
+	assert.Contains(t, props, "jaasConfigs.\"activemq\".modules.\"static\".params.\"reload\"=false\n")
+	assert.Equal(t, []string{"_props-roles", "_props-users", "_static-roles", "_static-users"}, securityJaasFiles(data))
+}
@@ -132,6 +132,10 @@ func (c *AddressObserver) checkCRsForNewPod(newPod *corev1.Pod) {
 
 		podCrName := namer.SSToCr(*podSSName)
 		c.log.V(1).Info("got pod's CR name", "value", podCrName)
+		if isAddressPropertiesBackend(c.opclient, types.NamespacedName{Name: podCrName, Namespace: newPod.Namespace}) {
+			c.log.V(1).Info("The pod's CR owns its addresses with broker properties", "cr", podCrName)
+			continue
+		}
 		//if the new pod is a target for this address cr, create
 		isTargetPod := false
 		if targetCrNamespacedNames == nil {
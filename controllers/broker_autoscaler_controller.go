@@ -0,0 +1,263 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
+)
+
+const (
+	defaultAutoscalingMinSize          = 1
+	defaultAutoscalingUpCooldownSecs   = 60
+	defaultAutoscalingDownCooldownSecs = 300
+	defaultAutoscalingPollIntervalSecs = 30
+)
+
+// BrokerAutoscalerReconciler adjusts the size of a Broker (arkmq.org/v1beta2) with autoscaling from the broker metrics
+type BrokerAutoscalerReconciler struct {
+	rtclient.Client
+	Scheme *runtime.Scheme
+	log    logr.Logger
+	// the jolokia endpoints of the brokers, replaced in tests
+	getEndpoints func(cr *v1beta2.Broker, client rtclient.Client) []*jolokia_client.JkInfo
+}
+
+func NewBrokerAutoscalerReconciler(client rtclient.Client, scheme *runtime.Scheme, logger logr.Logger) *BrokerAutoscalerReconciler {
+	return &BrokerAutoscalerReconciler{
+		Client:       client,
+		Scheme:       scheme,
+		log:          logger,
+		getEndpoints: jolokiaEndpointsOf,
+	}
+}
+
+func (r *BrokerAutoscalerReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "Reconciling", "BrokerAutoscaler")
+
+	cr := &v1beta2.Broker{}
+	if err := r.Get(ctx, request.NamespacedName, cr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		reqLogger.Error(err, "unable to retrieve the Broker")
+		return ctrl.Result{}, err
+	}
+
+	autoscaling := cr.Spec.Autoscaling
+	if autoscaling == nil {
+		if cr.Status.Autoscaling != nil {
+			cr.Status.Autoscaling = nil
+			return ctrl.Result{}, resources.UpdateStatus(r.Client, cr)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	result := ctrl.Result{RequeueAfter: time.Duration(int32OrDefault(autoscaling.PollIntervalSeconds, defaultAutoscalingPollIntervalSecs)) * time.Second}
+
+	if val, present := cr.Annotations[common.BlockReconcileAnnotation]; present {
+		if blocked, err := strconv.ParseBool(val); err == nil && blocked {
+			reqLogger.V(1).Info("reconcile blocked, not autoscaling")
+			return result, nil
+		}
+	}
+
+	if reason := autoscalingNotReadyReason(cr); reason != "" {
+		reqLogger.V(1).Info("not autoscaling", "reason", reason)
+		return result, nil
+	}
+
+	newSize, status, err := evaluateAutoscaling(cr, r.getEndpoints(cr, r.Client), time.Now())
+	if err != nil {
+		reqLogger.V(1).Info("unable to evaluate autoscaling", "error", err)
+		return result, nil
+	}
+
+	// the scale time is stored before the size so a failed status update can not bypass the cooldown
+	current := common.GetDeploymentSize(cr)
+	if err = r.updateOnConflict(ctx, cr, func() { cr.Status.Autoscaling = status }, resources.UpdateStatus); err != nil {
+		return result, err
+	}
+
+	if newSize != current && common.GetDeploymentSize(cr) == current {
+		reqLogger.Info("autoscaling", "from", current, "to", newSize, "messageCount", status.MessageCount, "consumerCount", status.ConsumerCount)
+		if err = r.updateOnConflict(ctx, cr, func() { cr.Spec.DeploymentPlan.Size = &newSize }, resources.Update); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// updateOnConflict applies the change to the latest version of the Broker till the update does not conflict
+func (r *BrokerAutoscalerReconciler) updateOnConflict(ctx context.Context, cr *v1beta2.Broker, change func(), update func(rtclient.Client, rtclient.Object) error) error {
+	first := true
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			if err := r.Get(ctx, rtclient.ObjectKeyFromObject(cr), cr); err != nil {
+				return err
+			}
+		}
+		first = false
+		change()
+		return update(r.Client, cr)
+	})
+}
+
+// autoscalingNotReadyReason explains why the size should not be changed yet, the previous change has to be complete
+func autoscalingNotReadyReason(cr *v1beta2.Broker) string {
+	if !meta.IsStatusConditionTrue(cr.Status.Conditions, v1beta2.ValidConditionType) {
+		return "the Broker is not valid"
+	}
+	if meta.IsStatusConditionTrue(cr.Status.Conditions, v1beta2.ScaleDownPendingConditionType) {
+		return "a scale down is in progress"
+	}
+	if cr.Status.DeploymentPlanSize != common.GetDeploymentSize(cr) {
+		return "the previous size change is in progress"
+	}
+	return ""
+}
+
+// evaluateAutoscaling computes the size from the metrics of every broker, it scales up to the desired size
+// and scales down one step at a time so the messages of each removed broker are migrated in turn
+func evaluateAutoscaling(cr *v1beta2.Broker, endpoints []*jolokia_client.JkInfo, now time.Time) (int32, *v1beta2.AutoscalingStatus, error) {
+	autoscaling := cr.Spec.Autoscaling
+	current := common.GetDeploymentSize(cr)
+
+	if int32(len(endpoints)) < current {
+		return current, nil, fmt.Errorf("waiting for jolokia endpoints of %d brokers, found %d", current, len(endpoints))
+	}
+
+	status := &v1beta2.AutoscalingStatus{}
+	if cr.Status.Autoscaling != nil {
+		status.LastScaleTime = cr.Status.Autoscaling.LastScaleTime
+	}
+	for _, jk := range endpoints {
+		metrics, err := jk.Artemis.GetBrokerMetrics()
+		if err != nil {
+			return current, nil, fmt.Errorf("error on get broker metrics of ordinal %s, %w", jk.Ordinal, err)
+		}
+		status.MessageCount += metrics.TotalMessageCount
+		status.ConsumerCount += metrics.TotalConsumerCount
+	}
+
+	step := int32(1)
+	if cr.Spec.HA != nil {
+		step = 2
+	}
+	status.DesiredSize = autoscalingDesiredSize(autoscaling, status.MessageCount, status.ConsumerCount, step)
+
+	cooledDown := func(cooldownSeconds int32) bool {
+		return status.LastScaleTime == nil || now.Sub(status.LastScaleTime.Time) >= time.Duration(cooldownSeconds)*time.Second
+	}
+
+	newSize := current
+	if status.DesiredSize > current && cooledDown(int32OrDefault(autoscaling.ScaleUpCooldownSeconds, defaultAutoscalingUpCooldownSecs)) {
+		newSize = status.DesiredSize
+	} else if status.DesiredSize < current && cooledDown(int32OrDefault(autoscaling.ScaleDownCooldownSeconds, defaultAutoscalingDownCooldownSecs)) {
+		newSize = current - step
+	}
+
+	if newSize != current {
+		status.LastScaleTime = &metav1.Time{Time: now}
+	}
+	return newSize, status, nil
+}
+
+func autoscalingDesiredSize(autoscaling *v1beta2.AutoscalingType, messageCount int64, consumerCount int64, step int32) int32 {
+	desired := int64(0)
+	if autoscaling.TargetMessageCount > 0 {
+		desired = max(desired, ceilDiv(messageCount, autoscaling.TargetMessageCount))
+	}
+	if autoscaling.TargetConsumerCount > 0 {
+		desired = max(desired, ceilDiv(consumerCount, autoscaling.TargetConsumerCount))
+	}
+
+	minSize := int64(int32OrDefault(autoscaling.MinSize, defaultAutoscalingMinSize))
+	desired = min(max(desired, minSize), int64(autoscaling.MaxSize))
+
+	// keep primary/backup pairs whole
+	if remainder := desired % int64(step); remainder != 0 {
+		desired += int64(step) - remainder
+	}
+	return int32(desired)
+}
+
+func ceilDiv(value int64, divisor int64) int64 {
+	return (value + divisor - 1) / divisor
+}
+
+func int32OrDefault(value *int32, defaultValue int32) int32 {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
+
+func validateAutoscaling(customResource *v1beta2.Broker) (*metav1.Condition, bool) {
+	autoscaling := customResource.Spec.Autoscaling
+	if autoscaling == nil {
+		return nil, false
+	}
+
+	var message string
+	minSize := int32OrDefault(autoscaling.MinSize, defaultAutoscalingMinSize)
+	switch {
+	case common.IsRestricted(customResource):
+		message = ".Spec.Autoscaling can not be used with a restricted deployment, it has a fixed size"
+	case minSize < 1:
+		message = fmt.Sprintf(".Spec.Autoscaling.MinSize %d is invalid, it must be at least 1", minSize)
+	case autoscaling.MaxSize < minSize:
+		message = fmt.Sprintf(".Spec.Autoscaling.MaxSize %d is invalid, it must not be less than MinSize %d", autoscaling.MaxSize, minSize)
+	case autoscaling.TargetMessageCount <= 0 && autoscaling.TargetConsumerCount <= 0:
+		message = ".Spec.Autoscaling requires a positive TargetMessageCount or TargetConsumerCount"
+	case customResource.Spec.HA != nil && (minSize%2 != 0 || autoscaling.MaxSize%2 != 0):
+		message = ".Spec.Autoscaling MinSize and MaxSize must be multiples of 2 with .Spec.HA"
+	}
+
+	if message != "" {
+		return &metav1.Condition{
+			Type:    v1beta2.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta2.ValidConditionFailedInvalidAutoscaling,
+			Message: message,
+		}, false
+	}
+	return nil, false
+}
+
+func (r *BrokerAutoscalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("brokerautoscaler").
+		For(&v1beta2.Broker{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
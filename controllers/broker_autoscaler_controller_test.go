@@ -0,0 +1,200 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	pointer "k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	artemis_client "github.com/arkmq-org/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
+)
+
+func autoscalingEndpoint(mockCtrl *gomock.Controller, ordinal string, messages int64, consumers int64) *jolokia_client.JkInfo {
+	j := jolokia.NewMockIJolokia(mockCtrl)
+	j.EXPECT().
+		Bulk(gomock.Eq([]jolokia.Request{{Type: "read", MBean: "org.apache.activemq.artemis:broker=\"a\"", Attribute: []string{"TotalMessageCount", "TotalConsumerCount"}}})).
+		Return([]*jolokia.ResponseData{{Status: 200, RawValue: []byte(fmt.Sprintf(`{"TotalMessageCount":%d,"TotalConsumerCount":%d}`, messages, consumers))}}, nil).
+		AnyTimes()
+	return &jolokia_client.JkInfo{Artemis: artemis_client.GetArtemisWithJolokia(j, "a"), IP: "IP", Ordinal: ordinal}
+}
+
+func TestAutoscalingDesiredSize(t *testing.T) {
+	autoscaling := &v1beta2.AutoscalingType{MaxSize: 6, TargetMessageCount: 1000, TargetConsumerCount: 10}
+
+	assert.Equal(t, int32(1), autoscalingDesiredSize(autoscaling, 0, 0, 1))
+	assert.Equal(t, int32(3), autoscalingDesiredSize(autoscaling, 2001, 5, 1))
+	assert.Equal(t, int32(4), autoscalingDesiredSize(autoscaling, 100, 35, 1))
+	assert.Equal(t, int32(6), autoscalingDesiredSize(autoscaling, 1000000, 0, 1))
+	assert.Equal(t, int32(4), autoscalingDesiredSize(autoscaling, 2001, 5, 2))
+
+	autoscaling.MinSize = pointer.To(int32(2))
+	assert.Equal(t, int32(2), autoscalingDesiredSize(autoscaling, 0, 0, 1))
+}
+
+func TestEvaluateAutoscaling(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	now := time.Now()
+	cr := newTestBroker("a", 2, func(candidate *v1beta2.Broker) {
+		candidate.Spec.Autoscaling = &v1beta2.AutoscalingType{MaxSize: 4, TargetMessageCount: 1000}
+	})
+
+	// not all brokers reachable
+	_, _, err := evaluateAutoscaling(cr, []*jolokia_client.JkInfo{autoscalingEndpoint(mockCtrl, "0", 10, 0)}, now)
+	assert.Error(t, err)
+
+	// scale up to the desired size
+	endpoints := []*jolokia_client.JkInfo{autoscalingEndpoint(mockCtrl, "0", 1500000, 1), autoscalingEndpoint(mockCtrl, "1", 2500, 2)}
+	size, status, err := evaluateAutoscaling(cr, endpoints, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), size)
+	assert.Equal(t, int64(1502500), status.MessageCount)
+	assert.Equal(t, int64(3), status.ConsumerCount)
+	assert.Equal(t, int32(4), status.DesiredSize)
+	assert.Equal(t, now, status.LastScaleTime.Time)
+
+	// scale down waits for the cooldown
+	cr.Status.Autoscaling = status
+	endpoints = []*jolokia_client.JkInfo{autoscalingEndpoint(mockCtrl, "0", 0, 0), autoscalingEndpoint(mockCtrl, "1", 0, 0)}
+	size, status, err = evaluateAutoscaling(cr, endpoints, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), size)
+	assert.Equal(t, int32(1), status.DesiredSize)
+	assert.Equal(t, now, status.LastScaleTime.Time)
+
+	// then one step at a time
+	cr.Spec.Autoscaling.ScaleDownCooldownSeconds = pointer.To(int32(30))
+	size, status, err = evaluateAutoscaling(cr, endpoints, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), size)
+	assert.Equal(t, now.Add(time.Minute), status.LastScaleTime.Time)
+}
+
+func TestAutoscalingNotReadyReason(t *testing.T) {
+	cr := newTestBroker("a", 2, func(candidate *v1beta2.Broker) {
+		candidate.Spec.Autoscaling = &v1beta2.AutoscalingType{MaxSize: 4, TargetMessageCount: 1000}
+	})
+	assert.Contains(t, autoscalingNotReadyReason(cr), "not valid")
+
+	cr.Status.Conditions = []metav1.Condition{{Type: v1beta2.ValidConditionType, Status: metav1.ConditionTrue}}
+	assert.Contains(t, autoscalingNotReadyReason(cr), "in progress")
+
+	cr.Status.DeploymentPlanSize = 2
+	assert.Equal(t, "", autoscalingNotReadyReason(cr))
+
+	cr.Status.Conditions = append(cr.Status.Conditions, metav1.Condition{Type: v1beta2.ScaleDownPendingConditionType, Status: metav1.ConditionTrue})
+	assert.Contains(t, autoscalingNotReadyReason(cr), "scale down")
+}
+
+func TestValidateAutoscaling(t *testing.T) {
+	condition, _ := validateAutoscaling(newTestBroker("a", 1, nil))
+	assert.Nil(t, condition)
+
+	condition, _ = validateAutoscaling(newTestBroker("a", 1, func(candidate *v1beta2.Broker) {
+		candidate.Spec.Autoscaling = &v1beta2.AutoscalingType{MaxSize: 3, TargetConsumerCount: 5}
+	}))
+	assert.Nil(t, condition)
+
+	condition, _ = validateAutoscaling(newTestBroker("a", 1, func(candidate *v1beta2.Broker) { candidate.Spec.Autoscaling = &v1beta2.AutoscalingType{MaxSize: 3} }))
+	assert.NotNil(t, condition)
+	assert.Equal(t, v1beta2.ValidConditionFailedInvalidAutoscaling, condition.Reason)
+
+	condition, _ = validateAutoscaling(newTestBroker("a", 1, func(candidate *v1beta2.Broker) {
+		candidate.Spec.Autoscaling = &v1beta2.AutoscalingType{MinSize: pointer.To(int32(4)), MaxSize: 3, TargetConsumerCount: 5}
+	}))
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "MaxSize")
+
+	cr := newTestBroker("a", 2, func(candidate *v1beta2.Broker) {
+		candidate.Spec.Autoscaling = &v1beta2.AutoscalingType{MaxSize: 3, TargetConsumerCount: 5}
+	})
+	cr.Spec.HA = &v1beta2.HAType{Policy: v1beta2.HAPolicies.Replication}
+	condition, _ = validateAutoscaling(cr)
+	assert.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "multiples of 2")
+}
+
+func TestAutoscalerKeepsCooldownOnConflict(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	cr := newTestBroker("a", 1, func(candidate *v1beta2.Broker) {
+		candidate.Spec.Autoscaling = &v1beta2.AutoscalingType{MaxSize: 4, TargetMessageCount: 1000}
+	})
+	cr.Status.DeploymentPlanSize = 1
+	cr.Status.Conditions = []metav1.Condition{{Type: v1beta2.ValidConditionType, Status: metav1.ConditionTrue}}
+
+	conflicts := 1
+	client := fake.NewClientBuilder().WithScheme(renderScheme()).WithObjects(cr).WithStatusSubresource(cr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c rtclient.Client, subResourceName string, obj rtclient.Object, opts ...rtclient.SubResourceUpdateOption) error {
+				if conflicts > 0 {
+					conflicts--
+					return apierrors.NewConflict(schema.GroupResource{}, obj.GetName(), fmt.Errorf("modified"))
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).Build()
+
+	r := NewBrokerAutoscalerReconciler(client, renderScheme(), ctrl.Log)
+	r.getEndpoints = func(cr *v1beta2.Broker, c rtclient.Client) []*jolokia_client.JkInfo {
+		endpoints := []*jolokia_client.JkInfo{}
+		for i := int32(0); i < common.GetDeploymentSize(cr); i++ {
+			endpoints = append(endpoints, autoscalingEndpoint(mockCtrl, fmt.Sprint(i), 1500, 0))
+		}
+		return endpoints
+	}
+	request := ctrl.Request{NamespacedName: rtclient.ObjectKeyFromObject(cr)}
+	_, err := r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+
+	scaled := &v1beta2.Broker{}
+	assert.NoError(t, client.Get(context.TODO(), request.NamespacedName, scaled))
+	assert.Equal(t, int32(2), *scaled.Spec.DeploymentPlan.Size)
+	if assert.NotNil(t, scaled.Status.Autoscaling) {
+		assert.NotNil(t, scaled.Status.Autoscaling.LastScaleTime)
+	}
+
+	// the stored scale time holds the next change till the cooldown expires
+	scaled.Status.DeploymentPlanSize = 2
+	assert.NoError(t, client.Status().Update(context.TODO(), scaled))
+	r.getEndpoints = func(cr *v1beta2.Broker, c rtclient.Client) []*jolokia_client.JkInfo {
+		return []*jolokia_client.JkInfo{autoscalingEndpoint(mockCtrl, "0", 5000, 0), autoscalingEndpoint(mockCtrl, "1", 5000, 0)}
+	}
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Get(context.TODO(), request.NamespacedName, scaled))
+	assert.Equal(t, int32(2), *scaled.Spec.DeploymentPlan.Size)
+	assert.Equal(t, int32(4), scaled.Status.Autoscaling.DesiredSize)
+}
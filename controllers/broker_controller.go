@@ -31,13 +31,18 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
 
 	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	brokermetrics "github.com/arkmq-org/activemq-artemis-operator/pkg/metrics"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
 )
 
 // BrokerReconciler reconciles a Broker object (arkmq.org/v1beta2)
@@ -46,6 +51,8 @@ type BrokerReconciler struct {
 	Scheme        *runtime.Scheme
 	log           logr.Logger
 	isOnOpenShift bool
+	// the jolokia endpoints of the brokers when set, replaced in tests
+	getEndpoints func(cr *v1beta2.Broker, client rtclient.Client) []*jolokia_client.JkInfo
 }
 
 func NewBrokerReconciler(cluster cluster.Cluster, logger logr.Logger, isOpenShift bool) *BrokerReconciler {
@@ -84,12 +91,16 @@ func (r *BrokerReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			reqLogger.V(1).Info("Broker Controller Reconcile encountered a IsNotFound, for request NamespacedName " + request.NamespacedName.String())
+			brokermetrics.DeleteCertificateMetrics(request.Name, request.Namespace)
+			jolokia.ForgetBroker(request.Name, request.Namespace)
 			return result, nil
 		}
 		reqLogger.Error(err, "unable to retrieve the Broker")
 		return result, err
 	}
 
+	ensureImageCatalog(ctx, r.Client, reqLogger)
+
 	var reconcileBlocked bool = false
 	if val, present := customResource.Annotations[common.BlockReconcileAnnotation]; present {
 		if boolVal, err := strconv.ParseBool(val); err == nil {
@@ -99,6 +110,14 @@ func (r *BrokerReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 
 	namer := MakeNamers(customResource)
 	reconciler := NewActiveMQArtemisReconcilerImpl(customResource, r.toArtemisParent())
+	reconciler.planOnly = isPlanRequested(customResource)
+	if r.getEndpoints != nil {
+		reconciler.getEndpoints = r.getEndpoints
+	}
+	reconciler.renderedPropertiesSecrets = renderedPropertiesSecrets(customResource, r.Client)
+	if !reconciler.planOnly {
+		customResource.Status.Plan = nil
+	}
 
 	var requeueRequest bool = false
 	var valid bool = false
@@ -120,14 +139,19 @@ func (r *BrokerReconciler) Reconcile(ctx context.Context, request ctrl.Request)
 		requeueRequest = true
 	}
 
-	if !requeueRequest && !reconcileBlocked && hasExtraMounts(customResource) {
+	if !requeueRequest && !reconcileBlocked && (hasExtraMounts(customResource) || len(reconciler.renderedPropertiesSecrets) > 0) {
 		reqLogger.V(1).Info("resource has extraMounts, requeuing")
 		requeueRequest = true
 	}
 
+	if !requeueRequest && !reconcileBlocked && len(reconciler.certificateSecrets) > 0 {
+		reqLogger.V(1).Info("resource has certificates, requeuing")
+		requeueRequest = true
+	}
+
 	if requeueRequest {
 		reqLogger.V(1).Info("requeue reconcile")
-		result = ctrl.Result{RequeueAfter: common.GetReconcileResyncPeriod()}
+		result = ctrl.Result{RequeueAfter: reconciler.requeueAfter()}
 	}
 
 	if valid && err == nil && crStatusUpdateErr == nil {
@@ -147,10 +171,19 @@ func (r *BrokerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&netv1.Ingress{}).
 		Owns(&policyv1.PodDisruptionBudget{})
 
+	if common.IsImageCatalogAvailable() {
+		watchImageCatalogs(builder, mgr.GetClient(), &v1beta2.BrokerList{}, r.log)
+	}
+
 	if r.isOnOpenShift {
 		builder.Owns(&routev1.Route{})
 	}
 
+	if common.IsGatewayAPIAvailable() {
+		builder.Owns(&gatewayv1alpha2.TLSRoute{}).
+			Owns(&gatewayv1beta1.HTTPRoute{})
+	}
+
 	return builder.Complete(r)
 }
 
@@ -181,6 +214,10 @@ func EqualBrokerCRStatus(s1, s2 *v1beta2.BrokerStatus) bool {
 		len(s2.ExternalConfigs) != len(s1.ExternalConfigs) ||
 		brokerExternalConfigsModified(s2.ExternalConfigs, s1.ExternalConfigs) ||
 		!reflect.DeepEqual(s1.PodStatus, s2.PodStatus) ||
+		!reflect.DeepEqual(s1.BrokerConnections, s2.BrokerConnections) ||
+		!reflect.DeepEqual(s1.Upgrade, s2.Upgrade) ||
+		!reflect.DeepEqual(s1.Plan, s2.Plan) ||
+		!reflect.DeepEqual(s1.ExternalAddresses, s2.ExternalAddresses) ||
 		len(s1.Conditions) != len(s2.Conditions) ||
 		conditionsModified(s2.Conditions, s1.Conditions) {
 
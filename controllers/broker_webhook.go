@@ -0,0 +1,166 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+//+kubebuilder:webhook:path=/mutate-arkmq-org-v1beta2-broker,mutating=true,failurePolicy=fail,sideEffects=None,groups=arkmq.org,resources=brokers,verbs=create;update,versions=v1beta2,name=mbroker.arkmq.org,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-arkmq-org-v1beta2-broker,mutating=false,failurePolicy=fail,sideEffects=None,groups=arkmq.org,resources=brokers,verbs=create;update,versions=v1beta2,name=vbroker.arkmq.org,admissionReviewVersions=v1
+
+// BrokerWebhook validates and defaults Broker (arkmq.org/v1beta2) resources at admission with the validation of the
+// reconciler, a spec that can only become valid later, like a missing secret, is admitted with a warning
+type BrokerWebhook struct {
+	client        rtclient.Client
+	isOnOpenShift bool
+	log           logr.Logger
+}
+
+func NewBrokerWebhook(client rtclient.Client, isOnOpenShift bool, logger logr.Logger) *BrokerWebhook {
+	return &BrokerWebhook{
+		client:        client,
+		isOnOpenShift: isOnOpenShift,
+		log:           logger,
+	}
+}
+
+func (w *BrokerWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&v1beta2.Broker{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// Default makes the implicit defaults of the reconciler visible in the stored spec
+func (w *BrokerWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	broker, ok := obj.(*v1beta2.Broker)
+	if !ok {
+		return fmt.Errorf("expected a Broker but got a %T", obj)
+	}
+	defaultBroker(broker, w.isOnOpenShift)
+	return nil
+}
+
+func defaultBroker(broker *v1beta2.Broker, isOnOpenShift bool) {
+	deploymentPlan := &broker.Spec.DeploymentPlan
+	// restricted brokers ignore the size and are never clustered
+	if !common.IsRestricted(broker) {
+		if deploymentPlan.Size == nil {
+			size := common.DefaultDeploymentSize
+			deploymentPlan.Size = &size
+		}
+		if deploymentPlan.Clustered == nil {
+			deploymentPlan.Clustered = common.NewTrue()
+		}
+	}
+	if deploymentPlan.MessageMigration == nil {
+		messageMigration := defaultMessageMigration
+		deploymentPlan.MessageMigration = &messageMigration
+	}
+
+	exposeMode := v1beta2.ExposeModes.Ingress
+	if isOnOpenShift {
+		exposeMode = v1beta2.ExposeModes.Route
+	}
+	for i := range broker.Spec.Acceptors {
+		acceptor := &broker.Spec.Acceptors[i]
+		if acceptor.Protocols == "" {
+			acceptor.Protocols = "all"
+		}
+		if acceptor.Expose && acceptor.ExposeMode == nil {
+			acceptor.ExposeMode = &exposeMode
+		}
+	}
+	for i := range broker.Spec.Connectors {
+		connector := &broker.Spec.Connectors[i]
+		if connector.Expose && connector.ExposeMode == nil {
+			connector.ExposeMode = &exposeMode
+		}
+	}
+	if broker.Spec.Console.Expose && broker.Spec.Console.ExposeMode == nil {
+		broker.Spec.Console.ExposeMode = &exposeMode
+	}
+}
+
+func (w *BrokerWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	broker, ok := obj.(*v1beta2.Broker)
+	if !ok {
+		return nil, fmt.Errorf("expected a Broker but got a %T", obj)
+	}
+	warnings, message := w.validateBroker(broker)
+	if message != "" {
+		return warnings, apierrors.NewForbidden(v1beta2.GroupVersion.WithResource("brokers").GroupResource(), broker.Name, fmt.Errorf("%s", message))
+	}
+	return warnings, nil
+}
+
+// ValidateUpdate does not reject an update of a Broker that was already invalid for the same reason, the operator and
+// the users can still change its metadata
+func (w *BrokerWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	broker, ok := newObj.(*v1beta2.Broker)
+	if !ok {
+		return nil, fmt.Errorf("expected a Broker but got a %T", newObj)
+	}
+	warnings, message := w.validateBroker(broker)
+	if message == "" {
+		return warnings, nil
+	}
+	if old, ok := oldObj.(*v1beta2.Broker); ok {
+		if _, oldMessage := w.validateBroker(old); oldMessage == message {
+			return append(warnings, message), nil
+		}
+	}
+	return warnings, apierrors.NewForbidden(v1beta2.GroupVersion.WithResource("brokers").GroupResource(), broker.Name, fmt.Errorf("%s", message))
+}
+
+func (w *BrokerWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateBroker returns the warnings and the message of an invalid Broker
+func (w *BrokerWebhook) validateBroker(broker *v1beta2.Broker) (admission.Warnings, string) {
+	if err := common.ValidateResourceName(broker.Name); err != nil {
+		return nil, err.Error()
+	}
+
+	// the reconciler validation sets the status, it works on a copy
+	customResource := broker.DeepCopy()
+	reconciler := NewActiveMQArtemisReconcilerImpl(customResource, &ActiveMQArtemisReconciler{log: w.log, isOnOpenShift: w.isOnOpenShift})
+	valid, retry := reconciler.validate(customResource, w.client, *MakeNamers(customResource))
+
+	condition := meta.FindStatusCondition(customResource.Status.Conditions, v1beta2.ValidConditionType)
+	if condition == nil || condition.Status == metav1.ConditionTrue || condition.Message == "" {
+		return nil, ""
+	}
+	if !valid && !retry {
+		return nil, condition.Message
+	}
+	return admission.Warnings{condition.Message}, ""
+}
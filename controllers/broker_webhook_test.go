@@ -0,0 +1,118 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func webhookBroker(name string) *v1beta2.Broker {
+	return &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"}}
+}
+
+func TestBrokerWebhookDefault(t *testing.T) {
+	webhook := NewBrokerWebhook(newTestClient(), false, ctrl.Log)
+	broker := webhookBroker("defaults")
+	broker.Spec.Acceptors = []v1beta2.AcceptorType{{Name: "amqp", Port: 5672, Expose: true}, {Name: "core", Port: 61616}}
+	broker.Spec.Console.Expose = true
+
+	assert.NoError(t, webhook.Default(context.TODO(), broker))
+
+	assert.Equal(t, int32(1), *broker.Spec.DeploymentPlan.Size)
+	assert.True(t, *broker.Spec.DeploymentPlan.Clustered)
+	assert.True(t, *broker.Spec.DeploymentPlan.MessageMigration)
+	assert.Equal(t, "all", broker.Spec.Acceptors[0].Protocols)
+	assert.Equal(t, v1beta2.ExposeModes.Ingress, *broker.Spec.Acceptors[0].ExposeMode)
+	assert.Nil(t, broker.Spec.Acceptors[1].ExposeMode)
+	assert.Equal(t, v1beta2.ExposeModes.Ingress, *broker.Spec.Console.ExposeMode)
+
+	restricted := webhookBroker("restricted")
+	restricted.Spec.Restricted = common.NewTrue()
+	restricted.Spec.DeploymentPlan.Clustered = nil
+	defaultBroker(restricted, true)
+	assert.Nil(t, restricted.Spec.DeploymentPlan.Size)
+	assert.Nil(t, restricted.Spec.DeploymentPlan.Clustered)
+
+	size := int32(3)
+	sized := webhookBroker("sized")
+	sized.Spec.DeploymentPlan.Size = &size
+	sized.Spec.Console.Expose = true
+	defaultBroker(sized, true)
+	assert.Equal(t, int32(3), *sized.Spec.DeploymentPlan.Size)
+	assert.Equal(t, v1beta2.ExposeModes.Route, *sized.Spec.Console.ExposeMode)
+}
+
+func TestBrokerWebhookValidate(t *testing.T) {
+	webhook := NewBrokerWebhook(newTestClient(), false, ctrl.Log)
+
+	warnings, err := webhook.ValidateCreate(context.TODO(), webhookBroker("valid"))
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	duplicatePorts := webhookBroker("duplicate")
+	duplicatePorts.Spec.Acceptors = []v1beta2.AcceptorType{{Name: "a", Port: 61616}, {Name: "b", Port: 61616}}
+	_, err = webhook.ValidateCreate(context.TODO(), duplicatePorts)
+	assert.True(t, apierrors.IsForbidden(err))
+	assert.ErrorContains(t, err, "duplicate port 61616")
+
+	// the secret can be created after the broker
+	missingSecret := webhookBroker("missing")
+	missingSecret.Spec.DeploymentPlan.ExtraMounts.Secrets = []string{"not-yet-bp"}
+	warnings, err = webhook.ValidateCreate(context.TODO(), missingSecret)
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "missing required secret not-yet-bp")
+
+	// an update of an invalid broker that does not change the reason is admitted
+	updated := duplicatePorts.DeepCopy()
+	updated.Annotations = map[string]string{"touched": "true"}
+	warnings, err = webhook.ValidateUpdate(context.TODO(), duplicatePorts, updated)
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 1)
+
+	_, err = webhook.ValidateUpdate(context.TODO(), webhookBroker("duplicate"), duplicatePorts)
+	assert.True(t, apierrors.IsForbidden(err))
+}
+
+func TestBrokerAppWebhookValidate(t *testing.T) {
+	webhook := &BrokerAppWebhook{}
+	app := &v1beta2.BrokerApp{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "test"}}
+	app.Spec.Capabilities = []v1beta2.AppCapabilityType{{SubscriberOf: []v1beta2.AppAddressType{{Address: "orders::orders"}}}}
+
+	_, err := webhook.ValidateCreate(context.TODO(), app)
+	assert.NoError(t, err)
+
+	app.Spec.Capabilities[0].SubscriberOf[0].Address = "orders"
+	_, err = webhook.ValidateCreate(context.TODO(), app)
+	assert.True(t, apierrors.IsForbidden(err))
+	assert.ErrorContains(t, err, "FQQN")
+}
+
+func TestBrokerServiceWebhookValidate(t *testing.T) {
+	webhook := &BrokerServiceWebhook{}
+	_, err := webhook.ValidateCreate(context.TODO(), &v1beta2.BrokerService{ObjectMeta: metav1.ObjectMeta{Name: "service"}})
+	assert.NoError(t, err)
+
+	_, err = webhook.ValidateCreate(context.TODO(), &v1beta2.BrokerService{ObjectMeta: metav1.ObjectMeta{Name: "..service"}})
+	assert.True(t, apierrors.IsForbidden(err))
+}
@@ -0,0 +1,413 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources/secrets"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+// BrokerAddressesSecretName is the broker properties secret of a Broker rendered from the matching BrokerAddress resources
+func BrokerAddressesSecretName(brokerName string) string {
+	return fmt.Sprintf("%s-brokeraddresses%s", brokerName, common.BrokerPropsSuffix)
+}
+
+// BrokerSecuritySecretName is the broker properties secret of a Broker rendered from the matching BrokerSecurity resources
+func BrokerSecuritySecretName(brokerName string) string {
+	return fmt.Sprintf("%s-brokersecurity%s", brokerName, common.BrokerPropsSuffix)
+}
+
+// renderedPropertiesSecrets returns the rendered BrokerAddress and BrokerSecurity secrets of the Broker that exist
+// and are not already listed in its extra mounts, the Broker spec is not updated
+func renderedPropertiesSecrets(cr *v1beta2.Broker, client rtclient.Client) []string {
+	var rendered []string
+	for _, secretName := range []string{BrokerAddressesSecretName(cr.Name), BrokerSecuritySecretName(cr.Name)} {
+		if slices.Contains(cr.Spec.DeploymentPlan.ExtraMounts.Secrets, secretName) {
+			continue
+		}
+		secret := &corev1.Secret{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: cr.Namespace}, secret); err == nil {
+			rendered = append(rendered, secretName)
+		}
+	}
+	return rendered
+}
+
+// brokerSelector converts the selector of a BrokerAddress or BrokerSecurity, an empty selector matches all the Brokers
+func brokerSelector(selector *metav1.LabelSelector) (labels.Selector, error) {
+	if selector == nil {
+		return labels.Everything(), nil
+	}
+	return metav1.LabelSelectorAsSelector(selector)
+}
+
+func isBrokerMatched(selector *metav1.LabelSelector, broker *v1beta2.Broker) bool {
+	brokerLabels, err := brokerSelector(selector)
+	return err == nil && brokerLabels.Matches(labels.Set(broker.Labels))
+}
+
+// syncRenderedPropertiesSecret creates the secret once there is some data and keeps it once created, removing
+// the mount would restart the brokers
+func syncRenderedPropertiesSecret(client rtclient.Client, scheme *runtime.Scheme, broker *v1beta2.Broker, secretName string, data map[string][]byte) error {
+	key := types.NamespacedName{Name: secretName, Namespace: broker.Namespace}
+	existing := &corev1.Secret{}
+	if err := resources.Retrieve(key, client, existing); err != nil {
+		if !apierrors.IsNotFound(err) || len(data) == 0 {
+			return rtclient.IgnoreNotFound(err)
+		}
+		return resources.Create(broker, client, scheme, secrets.NewSecret(key, data, nil))
+	}
+	if equality.Semantic.DeepEqual(existing.Data, data) || (len(existing.Data) == 0 && len(data) == 0) {
+		return nil
+	}
+	existing.Data = data
+	return resources.Update(client, existing)
+}
+
+// matchedBrokerStatuses reports whether the rendered secret with the key of a resource is applied by each Broker
+func matchedBrokerStatuses(client rtclient.Client, brokers []v1beta2.Broker, secretNameOf func(string) string, key string) []v1beta2.MatchedBrokerStatus {
+	statuses := []v1beta2.MatchedBrokerStatus{}
+	for i := range brokers {
+		broker := &brokers[i]
+		status := v1beta2.MatchedBrokerStatus{Name: broker.Name}
+
+		secretName := secretNameOf(broker.Name)
+		secret := &corev1.Secret{}
+		if err := client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: broker.Namespace}, secret); err != nil {
+			status.Message = fmt.Sprintf("unable to retrieve secret %s, %v", secretName, err)
+		} else if _, found := secret.Data[key]; !found {
+			status.Message = fmt.Sprintf("waiting for secret %s to be rendered", secretName)
+		} else {
+			appliedVersion := ""
+			for _, ec := range broker.Status.ExternalConfigs {
+				if ec.Name == secretName {
+					appliedVersion = ec.ResourceVersion
+				}
+			}
+			if appliedVersion == secret.ResourceVersion {
+				status.Applied = true
+			} else if condition := meta.FindStatusCondition(broker.Status.Conditions, v1beta2.ConfigAppliedConditionType); condition != nil && condition.Status == metav1.ConditionFalse {
+				status.Message = condition.Message
+			} else {
+				status.Message = fmt.Sprintf("waiting for the brokers to apply secret %s", secretName)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// matchedBrokersCondition sets the Deployed condition from the state on the matched Brokers, it returns true
+// while some Brokers have not applied the resource
+func matchedBrokersCondition(conditions *[]metav1.Condition, brokers []v1beta2.MatchedBrokerStatus) (pending bool) {
+	condition := metav1.Condition{
+		Type:   v1beta2.DeployedConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta2.DeployedConditionProvisionedReason,
+	}
+	waiting := []string{}
+	for _, broker := range brokers {
+		if !broker.Applied {
+			waiting = append(waiting, broker.Name)
+		}
+	}
+	if len(brokers) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1beta2.DeployedConditionNoMatchingBrokerReason
+		condition.Message = "no Broker matches the broker selector"
+	} else if len(waiting) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = v1beta2.DeployedConditionProvisioningPendingReason
+		condition.Message = fmt.Sprintf("not applied by %s", strings.Join(waiting, ", "))
+		pending = true
+	}
+	meta.SetStatusCondition(conditions, condition)
+	common.SetReadyCondition(conditions)
+	return pending
+}
+
+func selectorCondition(conditions *[]metav1.Condition, selector *metav1.LabelSelector) bool {
+	if _, err := brokerSelector(selector); err != nil {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:    v1beta2.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta2.ValidConditionSpecSelectorError,
+			Message: fmt.Sprintf("failed to evaluate Spec.BrokerSelector %v", err),
+		})
+		meta.RemoveStatusCondition(conditions, v1beta2.DeployedConditionType)
+		common.SetReadyCondition(conditions)
+		return false
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:   v1beta2.ValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta2.ValidConditionSuccessReason,
+	})
+	return true
+}
+
+// isAffectedBroker checks whether a change of a resource changes the rendered secret of a Broker, the resource
+// matches the Broker or the secret still renders the resource
+func isAffectedBroker(client rtclient.Client, broker *v1beta2.Broker, matched bool, secretName string, key string) bool {
+	if matched {
+		return true
+	}
+	secret := &corev1.Secret{}
+	if err := client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: broker.Namespace}, secret); err != nil {
+		return false
+	}
+	_, rendered := secret.Data[key]
+	return rendered
+}
+
+// enqueueAffectingResources enqueues the resources of a list that match the changed Broker or that its rendered
+// secret renders, the other resources do not change the secret of the Broker
+func enqueueAffectingResources(client rtclient.Client, list rtclient.ObjectList, selectorOf func(rtclient.Object) *metav1.LabelSelector, secretNameOf func(string) string, log logr.Logger) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj rtclient.Object) []reconcile.Request {
+		broker, ok := obj.(*v1beta2.Broker)
+		if !ok {
+			return nil
+		}
+		return affectingResources(ctx, client, list, selectorOf, secretNameOf(broker.Name), broker, log)
+	})
+}
+
+func affectingResources(ctx context.Context, client rtclient.Client, list rtclient.ObjectList, selectorOf func(rtclient.Object) *metav1.LabelSelector, secretName string, broker *v1beta2.Broker, log logr.Logger) []reconcile.Request {
+	resources := list.DeepCopyObject().(rtclient.ObjectList)
+	if err := client.List(ctx, resources, rtclient.InNamespace(broker.Namespace)); err != nil {
+		log.Error(err, "Failed to list resources for broker watch", "broker", broker.Name)
+		return nil
+	}
+	secret := &corev1.Secret{}
+	_ = client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: broker.Namespace}, secret)
+
+	var requests []reconcile.Request
+	_ = meta.EachListItem(resources, func(item runtime.Object) error {
+		o := item.(rtclient.Object)
+		if _, rendered := secret.Data[o.GetName()+".properties"]; rendered || isBrokerMatched(selectorOf(o), broker) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: o.GetNamespace(),
+					Name:      o.GetName(),
+				},
+			})
+		}
+		return nil
+	})
+	return requests
+}
+
+// renderBrokerAddress renders a BrokerAddress as addressConfigurations broker properties, the routing types of the
+// address are those of all the BrokerAddress resources of the address
+func renderBrokerAddress(address *v1beta2.BrokerAddress, routingTypes []string) []byte {
+	buf := &bytes.Buffer{}
+	prefix := fmt.Sprintf("addressConfigurations.\"%s\".", address.Spec.Address)
+	fmt.Fprintf(buf, "# %s\n", address.Name)
+	fmt.Fprintf(buf, "%sroutingTypes=%s\n", prefix, strings.Join(routingTypes, ","))
+
+	for _, queue := range address.Spec.Queues {
+		queuePrefix := fmt.Sprintf("%squeueConfigs.\"%s\".", prefix, queue.Name)
+		fmt.Fprintf(buf, "%saddress=%s\n", queuePrefix, address.Spec.Address)
+		fmt.Fprintf(buf, "%sroutingType=%s\n", queuePrefix, brokerQueueRoutingType(&queue))
+		if queue.FilterString != "" {
+			fmt.Fprintf(buf, "%sfilterString=%s\n", queuePrefix, queue.FilterString)
+		}
+		if queue.Durable != nil {
+			fmt.Fprintf(buf, "%sdurable=%t\n", queuePrefix, *queue.Durable)
+		}
+		if queue.MaxConsumers != nil {
+			fmt.Fprintf(buf, "%smaxConsumers=%d\n", queuePrefix, *queue.MaxConsumers)
+		}
+		if queue.Exclusive != nil {
+			fmt.Fprintf(buf, "%sexclusive=%t\n", queuePrefix, *queue.Exclusive)
+		}
+		if queue.LastValueKey != "" {
+			fmt.Fprintf(buf, "%slastValueKey=%s\n", queuePrefix, queue.LastValueKey)
+		}
+		if queue.NonDestructive != nil {
+			fmt.Fprintf(buf, "%snonDestructive=%t\n", queuePrefix, *queue.NonDestructive)
+		}
+		if queue.PurgeOnNoConsumers != nil {
+			fmt.Fprintf(buf, "%spurgeOnNoConsumers=%t\n", queuePrefix, *queue.PurgeOnNoConsumers)
+		}
+		if queue.ConsumersBeforeDispatch != nil {
+			fmt.Fprintf(buf, "%sconsumersBeforeDispatch=%d\n", queuePrefix, *queue.ConsumersBeforeDispatch)
+		}
+		if queue.DelayBeforeDispatch != nil {
+			fmt.Fprintf(buf, "%sdelayBeforeDispatch=%d\n", queuePrefix, *queue.DelayBeforeDispatch)
+		}
+		if queue.RingSize != nil {
+			fmt.Fprintf(buf, "%sringSize=%d\n", queuePrefix, *queue.RingSize)
+		}
+	}
+	return buf.Bytes()
+}
+
+func brokerQueueRoutingType(queue *v1beta2.BrokerQueueType) string {
+	if queue.RoutingType == "" {
+		return string(v1beta2.RoutingTypeMulticast)
+	}
+	return string(queue.RoutingType)
+}
+
+// renderBrokerAddressesSecretData renders the BrokerAddress resources that match a Broker, one key for each
+func renderBrokerAddressesSecretData(addresses []v1beta2.BrokerAddress, broker *v1beta2.Broker) map[string][]byte {
+	matched := []*v1beta2.BrokerAddress{}
+	routingTypes := map[string][]string{}
+	for i := range addresses {
+		address := &addresses[i]
+		if address.DeletionTimestamp != nil || !isBrokerMatched(address.Spec.BrokerSelector, broker) {
+			continue
+		}
+		matched = append(matched, address)
+		types := routingTypes[address.Spec.Address]
+		for _, routingType := range address.Spec.RoutingTypes {
+			types = append(types, string(routingType))
+		}
+		for _, queue := range address.Spec.Queues {
+			types = append(types, brokerQueueRoutingType(&queue))
+		}
+		routingTypes[address.Spec.Address] = types
+	}
+
+	data := map[string][]byte{}
+	for _, address := range matched {
+		types := routingTypes[address.Spec.Address]
+		if len(types) == 0 {
+			types = []string{string(v1beta2.RoutingTypeMulticast)}
+		}
+		sort.Strings(types)
+		data[address.Name+".properties"] = renderBrokerAddress(address, slices.Compact(types))
+	}
+	return data
+}
+
+// BrokerAddressReconciler renders the BrokerAddress (arkmq.org/v1beta2) resources into the broker properties of
+// the matched Brokers
+type BrokerAddressReconciler struct {
+	rtclient.Client
+	Scheme *runtime.Scheme
+	log    logr.Logger
+}
+
+func NewBrokerAddressReconciler(client rtclient.Client, scheme *runtime.Scheme, logger logr.Logger) *BrokerAddressReconciler {
+	return &BrokerAddressReconciler{
+		Client: client,
+		Scheme: scheme,
+		log:    logger,
+	}
+}
+
+//+kubebuilder:rbac:groups=arkmq.org,namespace=arkmq-org-broker-operator,resources=brokeraddresses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=arkmq.org,namespace=arkmq-org-broker-operator,resources=brokeraddresses/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=arkmq.org,namespace=arkmq-org-broker-operator,resources=brokeraddresses/finalizers,verbs=update
+
+func (r *BrokerAddressReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "Reconciling", "BrokerAddress")
+
+	address := &v1beta2.BrokerAddress{}
+	found := true
+	if err := r.Get(ctx, request.NamespacedName, address); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		found = false
+	}
+
+	brokers := &v1beta2.BrokerList{}
+	if err := r.List(ctx, brokers, rtclient.InNamespace(request.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+	addresses := &v1beta2.BrokerAddressList{}
+	if err := r.List(ctx, addresses, rtclient.InNamespace(request.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the secret of an affected Broker is rendered from all the addresses that match it
+	key := request.Name + ".properties"
+	for i := range brokers.Items {
+		broker := &brokers.Items[i]
+		secretName := BrokerAddressesSecretName(broker.Name)
+		if !isAffectedBroker(r.Client, broker, found && isBrokerMatched(address.Spec.BrokerSelector, broker), secretName, key) {
+			continue
+		}
+		data := renderBrokerAddressesSecretData(addresses.Items, broker)
+		if err := syncRenderedPropertiesSecret(r.Client, r.Scheme, broker, secretName, data); err != nil {
+			reqLogger.Error(err, "unable to render the addresses of the Broker", "broker", broker.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	if !found {
+		return ctrl.Result{}, nil
+	}
+
+	status := address.Status.DeepCopy()
+	result := ctrl.Result{}
+	if selectorCondition(&status.Conditions, address.Spec.BrokerSelector) {
+		matched := []v1beta2.Broker{}
+		for _, broker := range brokers.Items {
+			if isBrokerMatched(address.Spec.BrokerSelector, &broker) {
+				matched = append(matched, broker)
+			}
+		}
+		status.Brokers = matchedBrokerStatuses(r.Client, matched, BrokerAddressesSecretName, address.Name+".properties")
+		if matchedBrokersCondition(&status.Conditions, status.Brokers) {
+			result.RequeueAfter = common.GetReconcileResyncPeriod()
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(&address.Status, status) {
+		address.Status = *status
+		if err := resources.UpdateStatus(r.Client, address); err != nil && !apierrors.IsConflict(err) {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (r *BrokerAddressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta2.BrokerAddress{}).
+		Watches(&v1beta2.Broker{}, enqueueAffectingResources(mgr.GetClient(), &v1beta2.BrokerAddressList{}, func(o rtclient.Object) *metav1.LabelSelector {
+			return o.(*v1beta2.BrokerAddress).Spec.BrokerSelector
+		}, BrokerAddressesSecretName, r.log), builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}))).
+		Complete(r)
+}
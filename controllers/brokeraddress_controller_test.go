@@ -0,0 +1,172 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRenderBrokerAddressesSecretData(t *testing.T) {
+	durable := true
+	broker := newTestBroker("orders", 1, func(candidate *v1beta2.Broker) { candidate.Labels = map[string]string{"app": "orders"} })
+	addresses := []v1beta2.BrokerAddress{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders-queue"},
+			Spec: v1beta2.BrokerAddressSpec{
+				Address: "orders",
+				Queues:  []v1beta2.BrokerQueueType{{Name: "orders", RoutingType: v1beta2.RoutingTypeAnycast, Durable: &durable}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "orders-topic"},
+			Spec: v1beta2.BrokerAddressSpec{
+				BrokerSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "orders"}},
+				Address:        "orders",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "other"},
+			Spec: v1beta2.BrokerAddressSpec{
+				BrokerSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}},
+				Address:        "other",
+			},
+		},
+	}
+
+	data := renderBrokerAddressesSecretData(addresses, broker)
+	assert.Len(t, data, 2)
+	assert.Equal(t, "# orders-queue\n"+
+		"addressConfigurations.\"orders\".routingTypes=ANYCAST\n"+
+		"addressConfigurations.\"orders\".queueConfigs.\"orders\".address=orders\n"+
+		"addressConfigurations.\"orders\".queueConfigs.\"orders\".routingType=ANYCAST\n"+
+		"addressConfigurations.\"orders\".queueConfigs.\"orders\".durable=true\n",
+		string(data["orders-queue.properties"]))
+	assert.Equal(t, "# orders-topic\naddressConfigurations.\"orders\".routingTypes=ANYCAST\n",
+		string(data["orders-topic.properties"]))
+}
+
+func TestBrokerAddressReconcile(t *testing.T) {
+	broker := newTestBroker("orders", 1, func(candidate *v1beta2.Broker) { candidate.Labels = map[string]string{"app": "orders"} })
+	address := &v1beta2.BrokerAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "test"},
+		Spec: v1beta2.BrokerAddressSpec{
+			BrokerSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "orders"}},
+			Address:        "orders",
+		},
+	}
+	unmatched := &v1beta2.BrokerAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "test"},
+		Spec: v1beta2.BrokerAddressSpec{
+			BrokerSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}},
+			Address:        "other",
+		},
+	}
+	cl := newTestClient(broker, address, unmatched)
+	r := NewBrokerAddressReconciler(cl, cl.Scheme(), ctrl.Log)
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "orders", Namespace: "test"}}
+	result, err := r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.NotZero(t, result.RequeueAfter)
+
+	secret := &corev1.Secret{}
+	assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "orders-brokeraddresses-bp", Namespace: "test"}, secret))
+	assert.Contains(t, secret.Data, "orders.properties")
+	assert.Equal(t, "orders", secret.OwnerReferences[0].Name)
+
+	assert.NoError(t, cl.Get(context.TODO(), request.NamespacedName, address))
+	assert.Len(t, address.Status.Brokers, 1)
+	assert.False(t, address.Status.Brokers[0].Applied)
+	assert.Equal(t, v1beta2.DeployedConditionProvisioningPendingReason, meta.FindStatusCondition(address.Status.Conditions, v1beta2.DeployedConditionType).Reason)
+
+	// the broker applied the secret
+	assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "orders", Namespace: "test"}, broker))
+	broker.Status.ExternalConfigs = []v1beta2.ExternalConfigStatus{{Name: secret.Name, ResourceVersion: secret.ResourceVersion}}
+	assert.NoError(t, cl.Status().Update(context.TODO(), broker))
+
+	result, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.NoError(t, cl.Get(context.TODO(), request.NamespacedName, address))
+	assert.True(t, address.Status.Brokers[0].Applied)
+	assert.True(t, meta.IsStatusConditionTrue(address.Status.Conditions, v1beta2.ReadyConditionType))
+
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "other", Namespace: "test"}})
+	assert.NoError(t, err)
+	assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "other", Namespace: "test"}, unmatched))
+	assert.Empty(t, unmatched.Status.Brokers)
+	assert.Equal(t, v1beta2.DeployedConditionNoMatchingBrokerReason, meta.FindStatusCondition(unmatched.Status.Conditions, v1beta2.DeployedConditionType).Reason)
+
+	// deleting the address empties the secret, which stays mounted
+	assert.NoError(t, cl.Delete(context.TODO(), address))
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "orders-brokeraddresses-bp", Namespace: "test"}, secret))
+	assert.Empty(t, secret.Data)
+
+	assert.Equal(t, []string{"orders-brokeraddresses-bp"}, renderedPropertiesSecrets(broker, cl))
+	assert.Empty(t, broker.Spec.DeploymentPlan.ExtraMounts.Secrets)
+}
+
+func TestBrokerAddressReconcileAffectedBrokers(t *testing.T) {
+	orders := newTestBroker("orders", 1, func(candidate *v1beta2.Broker) { candidate.Labels = map[string]string{"app": "orders"} })
+	other := newTestBroker("other", 1, func(candidate *v1beta2.Broker) { candidate.Labels = map[string]string{"app": "other"} })
+	address := &v1beta2.BrokerAddress{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "test"},
+		Spec: v1beta2.BrokerAddressSpec{
+			BrokerSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "orders"}},
+			Address:        "orders",
+		},
+	}
+	cl := newTestClient(orders, other, address)
+	r := NewBrokerAddressReconciler(cl, cl.Scheme(), ctrl.Log)
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "orders", Namespace: "test"}}
+	_, err := r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	secret := &corev1.Secret{}
+	assert.True(t, apierrors.IsNotFound(cl.Get(context.TODO(), types.NamespacedName{Name: BrokerAddressesSecretName("other"), Namespace: "test"}, secret)))
+
+	// the broker watch only enqueues the addresses that match the broker or that its secret renders
+	mapped := func(broker *v1beta2.Broker) []reconcile.Request {
+		return affectingResources(context.TODO(), cl, &v1beta2.BrokerAddressList{}, func(o client.Object) *metav1.LabelSelector {
+			return o.(*v1beta2.BrokerAddress).Spec.BrokerSelector
+		}, BrokerAddressesSecretName(broker.Name), broker, ctrl.Log)
+	}
+	assert.Equal(t, []reconcile.Request{request}, mapped(orders))
+	assert.Empty(t, mapped(other))
+
+	// a broker that is no longer matched is rendered again without the address
+	orders.Labels = map[string]string{"app": "other"}
+	assert.NoError(t, cl.Update(context.TODO(), orders))
+	assert.Equal(t, []reconcile.Request{request}, mapped(orders))
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: BrokerAddressesSecretName("orders"), Namespace: "test"}, secret))
+	assert.Empty(t, secret.Data)
+	assert.Empty(t, mapped(orders))
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+var imageDigestRegExp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// BrokerImageCatalogReconciler validates the BrokerImageCatalogs (arkmq.org/v1beta2) and loads their versions and
+// mirrors for the version resolver of the brokers
+type BrokerImageCatalogReconciler struct {
+	rtclient.Client
+	Scheme *runtime.Scheme
+	log    logr.Logger
+}
+
+func NewBrokerImageCatalogReconciler(client rtclient.Client, scheme *runtime.Scheme, logger logr.Logger) *BrokerImageCatalogReconciler {
+	return &BrokerImageCatalogReconciler{
+		Client: client,
+		Scheme: scheme,
+		log:    logger,
+	}
+}
+
+//+kubebuilder:rbac:groups=arkmq.org,resources=brokerimagecatalogs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=arkmq.org,resources=brokerimagecatalogs/status,verbs=get;update;patch
+
+func (r *BrokerImageCatalogReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.log.WithValues("Request.Name", request.Name, "Reconciling", "BrokerImageCatalog")
+
+	// the catalogs are merged so each change reloads all of them
+	catalogs, err := loadImageCatalog(ctx, r.Client)
+	if err != nil {
+		reqLogger.Error(err, "unable to load the broker image catalogs")
+		return ctrl.Result{}, err
+	}
+
+	for i := range catalogs {
+		catalog := &catalogs[i]
+		if catalog.Name != request.Name {
+			continue
+		}
+		current := &v1beta2.BrokerImageCatalog{}
+		if err := r.Get(ctx, request.NamespacedName, current); err != nil {
+			return ctrl.Result{}, rtclient.IgnoreNotFound(err)
+		}
+		if !reflect.DeepEqual(current.Status, catalog.Status) {
+			reqLogger.V(1).Info("catalog status update", "versions", catalog.Status.Versions)
+			return ctrl.Result{}, resources.UpdateStatus(r.Client, catalog)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// loadImageCatalog merges the valid catalogs in the order of their names, the first catalog with a version has
+// precedence, and returns the catalogs with their new status
+func loadImageCatalog(ctx context.Context, client rtclient.Client) ([]v1beta2.BrokerImageCatalog, error) {
+	list := &v1beta2.BrokerImageCatalogList{}
+	if err := client.List(ctx, list); err != nil {
+		return nil, err
+	}
+	catalogs := list.Items
+	sort.Slice(catalogs, func(i, j int) bool {
+		return catalogs[i].Name < catalogs[j].Name
+	})
+
+	var images []common.CatalogImages
+	var mirrors []v1beta2.RegistryMirror
+	for i := range catalogs {
+		catalog := &catalogs[i]
+		catalogImages, condition := validateImageCatalog(catalog)
+		condition.ObservedGeneration = catalog.Generation
+		meta.SetStatusCondition(&catalog.Status.Conditions, condition)
+
+		catalog.Status.Versions = nil
+		if condition.Status != metav1.ConditionTrue {
+			continue
+		}
+		for _, entry := range catalogImages {
+			catalog.Status.Versions = append(catalog.Status.Versions, entry.Version.String())
+		}
+		images = append(images, catalogImages...)
+		mirrors = append(mirrors, catalog.Spec.Mirrors...)
+	}
+
+	common.SetImageCatalog(images, mirrors)
+	return catalogs, nil
+}
+
+func validateImageCatalog(catalog *v1beta2.BrokerImageCatalog) ([]common.CatalogImages, metav1.Condition) {
+	invalid := func(reason string, format string, args ...interface{}) ([]common.CatalogImages, metav1.Condition) {
+		return nil, metav1.Condition{
+			Type:    v1beta2.ValidConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: fmt.Sprintf(format, args...),
+		}
+	}
+
+	var images []common.CatalogImages
+	for _, entry := range catalog.Spec.Versions {
+		parsed, err := semver.Parse(entry.Version)
+		if err != nil {
+			return invalid(v1beta2.BrokerImageCatalogValidConditionInvalidVersionReason, "version %q is not a full version, %v", entry.Version, err)
+		}
+		if entry.Image == "" {
+			return invalid(v1beta2.BrokerImageCatalogValidConditionInvalidImageReason, "version %s has no image", entry.Version)
+		}
+		for _, digest := range []string{entry.ImageDigest, entry.InitImageDigest} {
+			if digest != "" && !imageDigestRegExp.MatchString(digest) {
+				return invalid(v1beta2.BrokerImageCatalogValidConditionInvalidImageReason, "version %s has digest %q, expected sha256:<hex>", entry.Version, digest)
+			}
+		}
+		if entry.InitImageDigest != "" && entry.InitImage == "" {
+			return invalid(v1beta2.BrokerImageCatalogValidConditionInvalidImageReason, "version %s has an init image digest without an init image", entry.Version)
+		}
+		images = append(images, common.CatalogImages{
+			Version:   parsed,
+			Image:     common.ImageWithDigest(entry.Image, entry.ImageDigest),
+			InitImage: common.ImageWithDigest(entry.InitImage, entry.InitImageDigest),
+		})
+	}
+
+	for _, mirror := range catalog.Spec.Mirrors {
+		if mirror.Source == "" || mirror.Mirror == "" {
+			return invalid(v1beta2.BrokerImageCatalogValidConditionInvalidMirrorReason, "a mirror needs a source and a mirror, found %q and %q", mirror.Source, mirror.Mirror)
+		}
+	}
+
+	return images, metav1.Condition{
+		Type:   v1beta2.ValidConditionType,
+		Status: metav1.ConditionTrue,
+		Reason: v1beta2.ValidConditionSuccessReason,
+	}
+}
+
+// ensureImageCatalog loads the catalogs before the first broker is reconciled, so the brokers do not start with
+// the images of the operator, without catalogs to read the images of the operator are used
+func ensureImageCatalog(ctx context.Context, client rtclient.Client, log logr.Logger) {
+	if common.IsImageCatalogLoaded() || !common.IsImageCatalogAvailable() {
+		return
+	}
+	if _, err := loadImageCatalog(ctx, client); err != nil {
+		log.V(1).Info("unable to load the broker image catalogs, using the images of the operator", "error", err.Error())
+	}
+}
+
+// imageCatalogReloaded passes the catalog events that follow a reload of the catalogs, the catalog reconciler updates
+// the status of a changed catalog once it reloaded them
+var imageCatalogReloaded = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldCatalog, oldOk := e.ObjectOld.(*v1beta2.BrokerImageCatalog)
+		newCatalog, newOk := e.ObjectNew.(*v1beta2.BrokerImageCatalog)
+		return oldOk && newOk && !reflect.DeepEqual(oldCatalog.Status, newCatalog.Status)
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// resourcesOfImageCatalog enqueues all the resources of a list, the version of any broker can resolve to a version
+// of the catalog, the catalogs are only loaded by the catalog reconciler
+func resourcesOfImageCatalog(client rtclient.Client, list rtclient.ObjectList, log logr.Logger) handler.MapFunc {
+	return func(ctx context.Context, obj rtclient.Object) []reconcile.Request {
+		resources := list.DeepCopyObject().(rtclient.ObjectList)
+		if err := client.List(ctx, resources); err != nil {
+			log.Error(err, "Failed to list resources for catalog watch", "catalog", obj.GetName())
+			return nil
+		}
+		var requests []reconcile.Request
+		_ = meta.EachListItem(resources, func(item runtime.Object) error {
+			o := item.(rtclient.Object)
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()},
+			})
+			return nil
+		})
+		return requests
+	}
+}
+
+// watchImageCatalogs reconciles all the resources of a list once the catalog reconciler reloaded a changed catalog
+func watchImageCatalogs(b *builder.Builder, client rtclient.Client, list rtclient.ObjectList, log logr.Logger) {
+	b.Watches(&v1beta2.BrokerImageCatalog{},
+		handler.EnqueueRequestsFromMapFunc(resourcesOfImageCatalog(client, list, log)),
+		builder.WithPredicates(imageCatalogReloaded))
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BrokerImageCatalogReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta2.BrokerImageCatalog{}).
+		Complete(r)
+}
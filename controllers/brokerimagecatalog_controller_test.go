@@ -0,0 +1,127 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+func TestBrokerImageCatalog(t *testing.T) {
+	t.Cleanup(common.ResetImageCatalog)
+
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	catalog := &v1beta2.BrokerImageCatalog{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-patches"},
+		Spec: v1beta2.BrokerImageCatalogSpec{
+			Versions: []v1beta2.BrokerImageCatalogVersion{{
+				Version:     "2.53.1",
+				Image:       "quay.io/arkmq-org/arkmq-org-broker-kubernetes:artemis.2.53.1",
+				ImageDigest: digest,
+				InitImage:   "quay.io/arkmq-org/arkmq-org-broker-init:artemis.2.53.1",
+			}},
+			Mirrors: []v1beta2.RegistryMirror{{Source: "quay.io/arkmq-org", Mirror: "registry.example.com/arkmq-org"}},
+		},
+	}
+	invalid := &v1beta2.BrokerImageCatalog{
+		ObjectMeta: metav1.ObjectMeta{Name: "b-invalid"},
+		Spec: v1beta2.BrokerImageCatalogSpec{
+			Versions: []v1beta2.BrokerImageCatalogVersion{{Version: "2.54", Image: "quay.io/arkmq-org/arkmq-org-broker-kubernetes:artemis.2.54.0"}},
+		},
+	}
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "patched", Namespace: "test", UID: types.UID("patched-uid")},
+		Spec:       v1beta2.BrokerSpec{Version: "2.53"},
+	}
+	scheme := renderScheme()
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(catalog, invalid, broker).
+		WithStatusSubresource(catalog, invalid, broker).Build()
+
+	catalogReconciler := NewBrokerImageCatalogReconciler(client, scheme, ctrl.Log)
+	for _, name := range []string{catalog.Name, invalid.Name} {
+		_, err := catalogReconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: catalog.Name}, catalog))
+	assert.True(t, meta.IsStatusConditionTrue(catalog.Status.Conditions, v1beta2.ValidConditionType))
+	assert.Equal(t, []string{"2.53.1"}, catalog.Status.Versions)
+
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: invalid.Name}, invalid))
+	valid := meta.FindStatusCondition(invalid.Status.Conditions, v1beta2.ValidConditionType)
+	if assert.NotNil(t, valid) {
+		assert.Equal(t, metav1.ConditionFalse, valid.Status)
+		assert.Equal(t, v1beta2.BrokerImageCatalogValidConditionInvalidVersionReason, valid.Reason)
+	}
+	assert.Empty(t, invalid.Status.Versions)
+
+	// the broker resolves the patch version of the catalog from the mirror
+	common.ResetImageCatalog()
+	reconciler := &BrokerReconciler{Client: client, Scheme: scheme, log: ctrl.Log}
+	key := types.NamespacedName{Name: broker.Name, Namespace: broker.Namespace}
+	_, err := reconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.Get(context.TODO(), key, broker))
+	assert.Equal(t, "2.53.1", broker.Status.Version.BrokerVersion)
+	assert.Equal(t, "registry.example.com/arkmq-org/arkmq-org-broker-kubernetes:artemis.2.53.1@"+digest, broker.Status.Version.Image)
+	assert.Equal(t, "registry.example.com/arkmq-org/arkmq-org-broker-init:artemis.2.53.1", broker.Status.Version.InitImage)
+
+	ss := &appsv1.StatefulSet{}
+	assert.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: "patched-ss", Namespace: "test"}, ss))
+	assert.Equal(t, broker.Status.Version.Image, ss.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, broker.Status.Version.InitImage, ss.Spec.Template.Spec.InitContainers[0].Image)
+
+	// the catalog watch only enqueues the brokers, the catalog reconciler reloads the removed catalog
+	assert.NoError(t, client.Delete(context.TODO(), catalog))
+	requests := resourcesOfImageCatalog(client, &v1beta2.BrokerList{}, ctrl.Log)(context.TODO(), catalog)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, key, requests[0].NamespacedName)
+	resolved, err := common.ResolveBrokerVersionFromCR(broker)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.53.1", resolved)
+
+	_, err = catalogReconciler.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: catalog.Name}})
+	assert.NoError(t, err)
+	resolved, err = common.ResolveBrokerVersionFromCR(broker)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.53.0", resolved)
+}
+
+func TestImageCatalogReloaded(t *testing.T) {
+	catalog := &v1beta2.BrokerImageCatalog{ObjectMeta: metav1.ObjectMeta{Name: "a-patches", Generation: 2}}
+	assert.False(t, imageCatalogReloaded.Create(event.CreateEvent{Object: catalog}))
+	assert.True(t, imageCatalogReloaded.Delete(event.DeleteEvent{Object: catalog}))
+
+	// a spec change is passed once the catalog reconciler updated the status
+	reloaded := catalog.DeepCopy()
+	assert.False(t, imageCatalogReloaded.Update(event.UpdateEvent{ObjectOld: catalog, ObjectNew: reloaded}))
+	reloaded.Status.Versions = []string{"2.53.1"}
+	assert.True(t, imageCatalogReloaded.Update(event.UpdateEvent{ObjectOld: catalog, ObjectNew: reloaded}))
+}
@@ -0,0 +1,345 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/namer"
+)
+
+const brokerOperationPendingRequeue = 10 * time.Second
+
+// BrokerOperationReconciler executes a BrokerOperation (arkmq.org/v1beta2) once on each target broker
+type BrokerOperationReconciler struct {
+	rtclient.Client
+	Scheme *runtime.Scheme
+	log    logr.Logger
+	// the jolokia endpoints of the brokers, replaced in tests
+	getEndpoints func(cr *v1beta2.Broker, client rtclient.Client) []*jolokia_client.JkInfo
+}
+
+func NewBrokerOperationReconciler(client rtclient.Client, scheme *runtime.Scheme, logger logr.Logger) *BrokerOperationReconciler {
+	return &BrokerOperationReconciler{
+		Client:       client,
+		Scheme:       scheme,
+		log:          logger,
+		getEndpoints: jolokiaEndpointsOf,
+	}
+}
+
+//+kubebuilder:rbac:groups=arkmq.org,namespace=arkmq-org-broker-operator,resources=brokeroperations,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=arkmq.org,namespace=arkmq-org-broker-operator,resources=brokeroperations/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=arkmq.org,namespace=arkmq-org-broker-operator,resources=brokeroperations/finalizers,verbs=update
+
+func (r *BrokerOperationReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "Reconciling", "BrokerOperation")
+
+	op := &v1beta2.BrokerOperation{}
+	if err := r.Get(ctx, request.NamespacedName, op); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		reqLogger.Error(err, "unable to retrieve the BrokerOperation")
+		return ctrl.Result{}, err
+	}
+
+	// executed once, the status is the audit record
+	if op.Status.CompletionTime != nil {
+		return ctrl.Result{}, nil
+	}
+
+	result := ctrl.Result{}
+	if message := validateBrokerOperation(&op.Spec); message != "" {
+		r.complete(op, v1beta2.BrokerOperationCompletedConditionInvalid, message)
+	} else {
+		broker := &v1beta2.Broker{}
+		err := r.Get(ctx, types.NamespacedName{Name: op.Spec.Broker.Name, Namespace: op.Namespace}, broker)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return result, err
+		}
+		if err != nil {
+			meta.SetStatusCondition(&op.Status.Conditions, metav1.Condition{
+				Type:    v1beta2.BrokerOperationCompletedConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  v1beta2.BrokerOperationCompletedConditionBrokerNotFound,
+				Message: fmt.Sprintf("waiting for Broker %s", op.Spec.Broker.Name),
+			})
+			result.RequeueAfter = brokerOperationPendingRequeue
+		} else {
+			pending, err := r.executeBrokerOperation(ctx, op, broker, time.Now())
+			if err != nil {
+				return result, err
+			}
+			if pending {
+				reqLogger.V(1).Info("waiting for brokers to execute the operation on")
+				result.RequeueAfter = brokerOperationPendingRequeue
+			}
+		}
+	}
+
+	return result, resources.UpdateStatus(r.Client, op)
+}
+
+func validateBrokerOperation(spec *v1beta2.BrokerOperationSpec) string {
+	switch spec.Operation {
+	case v1beta2.BrokerOperationPurge, v1beta2.BrokerOperationRetryDLQ:
+		if spec.Queue == "" {
+			return fmt.Sprintf("operation %s requires a queue", spec.Operation)
+		}
+	case v1beta2.BrokerOperationPause, v1beta2.BrokerOperationResume:
+		if (spec.Queue == "") == (spec.Address == "") {
+			return fmt.Sprintf("operation %s requires either a queue or an address", spec.Operation)
+		}
+	case v1beta2.BrokerOperationMoveMessages:
+		if spec.Queue == "" || spec.TargetQueue == "" {
+			return fmt.Sprintf("operation %s requires a queue and a targetQueue", spec.Operation)
+		}
+	case v1beta2.BrokerOperationCloseConnections:
+		if spec.User == "" {
+			return fmt.Sprintf("operation %s requires a user", spec.Operation)
+		}
+	default:
+		return fmt.Sprintf("operation %q is not supported", spec.Operation)
+	}
+	for _, ordinal := range spec.Ordinals {
+		if ordinal < 0 {
+			return fmt.Sprintf("ordinal %d is invalid", ordinal)
+		}
+	}
+	return ""
+}
+
+// executeBrokerOperation executes the operation on each target broker without a result, it returns true
+// while some target brokers are not available yet. A broker is claimed in the stored status before the
+// operation is sent and its result is stored right after, so the operation is sent at most once
+func (r *BrokerOperationReconciler) executeBrokerOperation(ctx context.Context, op *v1beta2.BrokerOperation, broker *v1beta2.Broker, now time.Time) (pending bool, err error) {
+	size := common.GetDeploymentSize(broker)
+	targets := op.Spec.Ordinals
+	if len(targets) == 0 {
+		for ordinal := int32(0); ordinal < size; ordinal++ {
+			targets = append(targets, ordinal)
+		}
+	}
+	for _, ordinal := range targets {
+		if ordinal >= size {
+			r.complete(op, v1beta2.BrokerOperationCompletedConditionInvalid, fmt.Sprintf("ordinal %d is invalid, Broker %s has %d brokers", ordinal, broker.Name, size))
+			return false, nil
+		}
+	}
+
+	// a claim without a result was left by a reconcile that did not complete, the operation may have been applied
+	if _, err = r.updateStatus(ctx, op, func() bool {
+		unknown := false
+		for i := range op.Status.Results {
+			if op.Status.Results[i].InProgress {
+				op.Status.Results[i].InProgress = false
+				op.Status.Results[i].Message = "the outcome of the operation was not recorded, it is not sent again"
+				unknown = true
+			}
+		}
+		return unknown
+	}); err != nil {
+		return false, err
+	}
+
+	endpoints := map[string]*jolokia_client.JkInfo{}
+	for _, jk := range r.getEndpoints(broker, r.Client) {
+		endpoints[jk.Ordinal] = jk
+	}
+
+	waiting := []string{}
+	for _, ordinal := range targets {
+		if findBrokerOperationResult(op, ordinal) != nil {
+			continue
+		}
+		podName := namer.CrToSS(broker.Name) + "-" + strconv.Itoa(int(ordinal))
+		jk, found := endpoints[strconv.Itoa(int(ordinal))]
+		if !found {
+			waiting = append(waiting, podName)
+			continue
+		}
+
+		claimed, err := r.updateStatus(ctx, op, func() bool {
+			if op.Status.CompletionTime != nil || findBrokerOperationResult(op, ordinal) != nil {
+				return false
+			}
+			if op.Status.StartTime == nil {
+				op.Status.StartTime = &metav1.Time{Time: now}
+			}
+			op.Status.Results = append(op.Status.Results, v1beta2.BrokerOperationResult{
+				Ordinal:    ordinal,
+				Pod:        podName,
+				Time:       metav1.Time{Time: now},
+				InProgress: true,
+			})
+			return true
+		})
+		if err != nil {
+			return false, err
+		}
+		if !claimed {
+			// the latest status has a result or a claim for the broker, it is evaluated again
+			return true, nil
+		}
+
+		count, message, err := runBrokerOperation(jk.Artemis, &op.Spec)
+		result := v1beta2.BrokerOperationResult{
+			Ordinal:   ordinal,
+			Pod:       podName,
+			Succeeded: err == nil,
+			Count:     count,
+			Message:   message,
+			Time:      metav1.Time{Time: now},
+		}
+		if err != nil {
+			result.Message = err.Error()
+		}
+		r.log.Info("executed broker operation", "operation", op.Spec.Operation, "pod", podName, "succeeded", result.Succeeded, "message", result.Message)
+
+		if _, err = r.updateStatus(ctx, op, func() bool {
+			if claim := findBrokerOperationResult(op, ordinal); claim != nil {
+				*claim = result
+			} else {
+				op.Status.Results = append(op.Status.Results, result)
+			}
+			return true
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	if len(waiting) > 0 {
+		meta.SetStatusCondition(&op.Status.Conditions, metav1.Condition{
+			Type:    v1beta2.BrokerOperationCompletedConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  v1beta2.BrokerOperationCompletedConditionPending,
+			Message: fmt.Sprintf("waiting for the jolokia endpoint of %s", strings.Join(waiting, ", ")),
+		})
+		return true, nil
+	}
+
+	failed := []string{}
+	for _, result := range op.Status.Results {
+		if !result.Succeeded {
+			failed = append(failed, result.Pod)
+		}
+	}
+	if len(failed) > 0 {
+		r.complete(op, v1beta2.BrokerOperationCompletedConditionFailed, fmt.Sprintf("the operation failed on %s", strings.Join(failed, ", ")))
+	} else {
+		r.complete(op, v1beta2.BrokerOperationCompletedConditionSucceeded, fmt.Sprintf("the operation succeeded on %d brokers", len(op.Status.Results)))
+	}
+	return false, nil
+}
+
+func findBrokerOperationResult(op *v1beta2.BrokerOperation, ordinal int32) *v1beta2.BrokerOperationResult {
+	for i := range op.Status.Results {
+		if op.Status.Results[i].Ordinal == ordinal {
+			return &op.Status.Results[i]
+		}
+	}
+	return nil
+}
+
+// updateStatus applies the change to the latest BrokerOperation and stores it till the update does not
+// conflict, the change returns false when it does not apply to the latest status
+func (r *BrokerOperationReconciler) updateStatus(ctx context.Context, op *v1beta2.BrokerOperation, change func() bool) (applied bool, err error) {
+	first := true
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			if err := r.Get(ctx, rtclient.ObjectKeyFromObject(op), op); err != nil {
+				return err
+			}
+		}
+		first = false
+		if applied = change(); !applied {
+			return nil
+		}
+		return resources.UpdateStatus(r.Client, op)
+	})
+	return applied, err
+}
+
+func (r *BrokerOperationReconciler) complete(op *v1beta2.BrokerOperation, reason string, message string) {
+	op.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+	meta.SetStatusCondition(&op.Status.Conditions, metav1.Condition{
+		Type:    v1beta2.BrokerOperationCompletedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// runBrokerOperation executes the operation on a broker, it returns the number of affected messages when known
+func runBrokerOperation(broker *artemis.Artemis, spec *v1beta2.BrokerOperationSpec) (*int64, string, error) {
+	var count int64
+	var err error
+	switch spec.Operation {
+	case v1beta2.BrokerOperationPurge:
+		count, err = broker.PurgeQueue(spec.Queue)
+		return &count, fmt.Sprintf("removed %d messages from %s", count, spec.Queue), err
+	case v1beta2.BrokerOperationRetryDLQ:
+		count, err = broker.RetryMessages(spec.Queue)
+		return &count, fmt.Sprintf("retried %d messages from %s", count, spec.Queue), err
+	case v1beta2.BrokerOperationMoveMessages:
+		count, err = broker.MoveMessages(spec.Queue, spec.Filter, spec.TargetQueue)
+		return &count, fmt.Sprintf("moved %d messages from %s to %s", count, spec.Queue, spec.TargetQueue), err
+	case v1beta2.BrokerOperationPause:
+		if spec.Address != "" {
+			return nil, "paused address " + spec.Address, broker.PauseAddress(spec.Address)
+		}
+		return nil, "paused queue " + spec.Queue, broker.PauseQueue(spec.Queue)
+	case v1beta2.BrokerOperationResume:
+		if spec.Address != "" {
+			return nil, "resumed address " + spec.Address, broker.ResumeAddress(spec.Address)
+		}
+		return nil, "resumed queue " + spec.Queue, broker.ResumeQueue(spec.Queue)
+	case v1beta2.BrokerOperationCloseConnections:
+		closed, err := broker.CloseConnectionsForUser(spec.User)
+		if !closed {
+			return nil, "no connections of user " + spec.User, err
+		}
+		return nil, "closed the connections of user " + spec.User, err
+	}
+	return nil, "", fmt.Errorf("operation %q is not supported", spec.Operation)
+}
+
+func (r *BrokerOperationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta2.BrokerOperation{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
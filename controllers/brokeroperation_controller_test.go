@@ -0,0 +1,253 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/artemis"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia_client"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func brokerOperationEndpoint(j jolokia.IJolokia, ordinal string) *jolokia_client.JkInfo {
+	return &jolokia_client.JkInfo{Artemis: artemis.GetArtemisWithJolokia(j, "amq"), IP: "op-ss-" + ordinal, Ordinal: ordinal}
+}
+
+func reconcileBrokerOperation(t *testing.T, r *BrokerOperationReconciler) (*v1beta2.BrokerOperation, ctrl.Result) {
+	key := types.NamespacedName{Name: "op", Namespace: "test"}
+	result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	assert.NoError(t, err)
+	op := &v1beta2.BrokerOperation{}
+	assert.NoError(t, r.Get(context.TODO(), key, op))
+	return op, result
+}
+
+func TestValidateBrokerOperation(t *testing.T) {
+	assert.Empty(t, validateBrokerOperation(&v1beta2.BrokerOperationSpec{Operation: v1beta2.BrokerOperationPurge, Queue: "q"}))
+	assert.Empty(t, validateBrokerOperation(&v1beta2.BrokerOperationSpec{Operation: v1beta2.BrokerOperationPause, Address: "a"}))
+	assert.Empty(t, validateBrokerOperation(&v1beta2.BrokerOperationSpec{Operation: v1beta2.BrokerOperationCloseConnections, User: "alice"}))
+
+	assert.Contains(t, validateBrokerOperation(&v1beta2.BrokerOperationSpec{Operation: v1beta2.BrokerOperationRetryDLQ}), "requires a queue")
+	assert.Contains(t, validateBrokerOperation(&v1beta2.BrokerOperationSpec{Operation: v1beta2.BrokerOperationResume, Queue: "q", Address: "a"}), "either a queue or an address")
+	assert.Contains(t, validateBrokerOperation(&v1beta2.BrokerOperationSpec{Operation: v1beta2.BrokerOperationMoveMessages, Queue: "q"}), "targetQueue")
+	assert.Contains(t, validateBrokerOperation(&v1beta2.BrokerOperationSpec{Operation: v1beta2.BrokerOperationCloseConnections}), "requires a user")
+	assert.Contains(t, validateBrokerOperation(&v1beta2.BrokerOperationSpec{Operation: "restart"}), "not supported")
+	assert.Contains(t, validateBrokerOperation(&v1beta2.BrokerOperationSpec{Operation: v1beta2.BrokerOperationPurge, Queue: "q", Ordinals: []int32{-1}}), "ordinal -1")
+}
+
+func TestBrokerOperationExecutesOncePerOrdinal(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	size := int32(3)
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: "test"},
+		Spec:       v1beta2.BrokerSpec{DeploymentPlan: v1beta2.DeploymentPlanType{Size: &size}},
+	}
+	op := &v1beta2.BrokerOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: "test"},
+		Spec: v1beta2.BrokerOperationSpec{
+			Broker:    corev1.LocalObjectReference{Name: "op"},
+			Ordinals:  []int32{0, 2},
+			Operation: v1beta2.BrokerOperationPurge,
+			Queue:     "DLQ",
+		},
+	}
+
+	queueMBean := `org.apache.activemq.artemis:broker="amq",component=addresses,address="DLQ",subcomponent=queues,routing-type="anycast",queue="DLQ"`
+
+	j0 := jolokia.NewMockIJolokia(mockCtrl)
+	j0.EXPECT().Search(gomock.Any()).Return([]string{queueMBean}, nil).Times(1)
+	j0.EXPECT().Exec(queueMBean, gomock.Any()).Return(&jolokia.ResponseData{Status: 200, RawValue: []byte("4")}, nil).Times(1)
+
+	j2 := jolokia.NewMockIJolokia(mockCtrl)
+	j2.EXPECT().Search(gomock.Any()).Return(nil, errors.New("connection refused")).Times(1)
+
+	// ordinal 2 is not available yet
+	cl := newTestClient(broker, op)
+	r := NewBrokerOperationReconciler(cl, cl.Scheme(), ctrl.Log)
+	r.getEndpoints = func(cr *v1beta2.Broker, client client.Client) []*jolokia_client.JkInfo {
+		return []*jolokia_client.JkInfo{brokerOperationEndpoint(j0, "0")}
+	}
+	current, result := reconcileBrokerOperation(t, r)
+	assert.NotZero(t, result.RequeueAfter)
+	assert.Nil(t, current.Status.CompletionTime)
+	assert.NotNil(t, current.Status.StartTime)
+	condition := meta.FindStatusCondition(current.Status.Conditions, v1beta2.BrokerOperationCompletedConditionType)
+	assert.Equal(t, v1beta2.BrokerOperationCompletedConditionPending, condition.Reason)
+	assert.Contains(t, condition.Message, "op-ss-2")
+	assert.Len(t, current.Status.Results, 1)
+	assert.True(t, current.Status.Results[0].Succeeded)
+	assert.Equal(t, int64(4), *current.Status.Results[0].Count)
+	assert.Equal(t, "op-ss-0", current.Status.Results[0].Pod)
+
+	// ordinal 0 is not executed again
+	r.getEndpoints = func(cr *v1beta2.Broker, client client.Client) []*jolokia_client.JkInfo {
+		return []*jolokia_client.JkInfo{brokerOperationEndpoint(j0, "0"), brokerOperationEndpoint(j2, "2")}
+	}
+	current, result = reconcileBrokerOperation(t, r)
+	assert.Zero(t, result.RequeueAfter)
+	assert.NotNil(t, current.Status.CompletionTime)
+	assert.Len(t, current.Status.Results, 2)
+	assert.False(t, current.Status.Results[1].Succeeded)
+	assert.Contains(t, current.Status.Results[1].Message, "connection refused")
+	condition = meta.FindStatusCondition(current.Status.Conditions, v1beta2.BrokerOperationCompletedConditionType)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, v1beta2.BrokerOperationCompletedConditionFailed, condition.Reason)
+	assert.Contains(t, condition.Message, "op-ss-2")
+
+	// completed operations are left alone
+	_, result = reconcileBrokerOperation(t, r)
+	assert.Zero(t, result.RequeueAfter)
+}
+
+func TestBrokerOperationInvalidOrdinal(t *testing.T) {
+	size := int32(1)
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: "test"},
+		Spec:       v1beta2.BrokerSpec{DeploymentPlan: v1beta2.DeploymentPlanType{Size: &size}},
+	}
+	op := &v1beta2.BrokerOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: "test"},
+		Spec: v1beta2.BrokerOperationSpec{
+			Broker:    corev1.LocalObjectReference{Name: "op"},
+			Ordinals:  []int32{1},
+			Operation: v1beta2.BrokerOperationCloseConnections,
+			User:      "alice",
+		},
+	}
+
+	cl := newTestClient(broker, op)
+	r := NewBrokerOperationReconciler(cl, cl.Scheme(), ctrl.Log)
+	r.getEndpoints = func(cr *v1beta2.Broker, client client.Client) []*jolokia_client.JkInfo {
+		return nil
+	}
+	current, _ := reconcileBrokerOperation(t, r)
+	assert.NotNil(t, current.Status.CompletionTime)
+	assert.Empty(t, current.Status.Results)
+	condition := meta.FindStatusCondition(current.Status.Conditions, v1beta2.BrokerOperationCompletedConditionType)
+	assert.Equal(t, v1beta2.BrokerOperationCompletedConditionInvalid, condition.Reason)
+	assert.Contains(t, condition.Message, "ordinal 1")
+}
+
+func TestBrokerOperationWaitsForBroker(t *testing.T) {
+	op := &v1beta2.BrokerOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: "test"},
+		Spec: v1beta2.BrokerOperationSpec{
+			Broker:    corev1.LocalObjectReference{Name: "missing"},
+			Operation: v1beta2.BrokerOperationResume,
+			Address:   "orders",
+		},
+	}
+
+	cl := newTestClient(op)
+	r := NewBrokerOperationReconciler(cl, cl.Scheme(), ctrl.Log)
+	current, result := reconcileBrokerOperation(t, r)
+	assert.NotZero(t, result.RequeueAfter)
+	assert.Nil(t, current.Status.CompletionTime)
+	condition := meta.FindStatusCondition(current.Status.Conditions, v1beta2.BrokerOperationCompletedConditionType)
+	assert.Equal(t, v1beta2.BrokerOperationCompletedConditionBrokerNotFound, condition.Reason)
+}
+
+func TestBrokerOperationClaimsBeforeExecuting(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	size := int32(2)
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: "test"},
+		Spec:       v1beta2.BrokerSpec{DeploymentPlan: v1beta2.DeploymentPlanType{Size: &size}},
+	}
+	op := &v1beta2.BrokerOperation{
+		ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: "test"},
+		Spec: v1beta2.BrokerOperationSpec{
+			Broker:    corev1.LocalObjectReference{Name: "op"},
+			Operation: v1beta2.BrokerOperationResume,
+			Queue:     "orders",
+		},
+		// the claim of ordinal 1 was stored and the reconcile stopped before its result
+		Status: v1beta2.BrokerOperationStatus{
+			Results: []v1beta2.BrokerOperationResult{{Ordinal: 1, Pod: "op-ss-1", InProgress: true}},
+		},
+	}
+
+	queueMBean := `org.apache.activemq.artemis:broker="amq",component=addresses,address="orders",subcomponent=queues,routing-type="anycast",queue="orders"`
+
+	j0 := jolokia.NewMockIJolokia(mockCtrl)
+	j0.EXPECT().Search(gomock.Any()).Return([]string{queueMBean}, nil).Times(1)
+	j0.EXPECT().Exec(queueMBean, gomock.Any()).Return(&jolokia.ResponseData{Status: 200}, nil).Times(1)
+	j1 := jolokia.NewMockIJolokia(mockCtrl)
+
+	s := runtime.NewScheme()
+	_ = v1beta2.AddToScheme(s)
+	conflicts := 1
+	updates := []v1beta2.BrokerOperationStatus{}
+	fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(broker, op).WithStatusSubresource(&v1beta2.BrokerOperation{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if conflicts > 0 {
+					conflicts--
+					return apierrors.NewConflict(schema.GroupResource{}, obj.GetName(), errors.New("modified"))
+				}
+				updates = append(updates, *obj.(*v1beta2.BrokerOperation).Status.DeepCopy())
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).Build()
+	r := NewBrokerOperationReconciler(fakeClient, s, ctrl.Log)
+	r.getEndpoints = func(cr *v1beta2.Broker, client client.Client) []*jolokia_client.JkInfo {
+		return []*jolokia_client.JkInfo{brokerOperationEndpoint(j0, "0"), brokerOperationEndpoint(j1, "1")}
+	}
+
+	current, result := reconcileBrokerOperation(t, r)
+	assert.Zero(t, result.RequeueAfter)
+	assert.NotNil(t, current.Status.CompletionTime)
+
+	// the unknown outcome and the claim are stored before the operation is sent
+	if assert.Len(t, updates, 4) {
+		assert.False(t, updates[0].Results[0].InProgress)
+		claim := updates[1].Results
+		assert.Len(t, claim, 2)
+		assert.True(t, claim[1].InProgress)
+		assert.Equal(t, int32(0), claim[1].Ordinal)
+	}
+
+	if assert.Len(t, current.Status.Results, 2) {
+		assert.False(t, current.Status.Results[0].Succeeded)
+		assert.False(t, current.Status.Results[0].InProgress)
+		assert.Contains(t, current.Status.Results[0].Message, "not recorded")
+		assert.True(t, current.Status.Results[1].Succeeded)
+		assert.False(t, current.Status.Results[1].InProgress)
+	}
+	condition := meta.FindStatusCondition(current.Status.Conditions, v1beta2.BrokerOperationCompletedConditionType)
+	assert.Equal(t, v1beta2.BrokerOperationCompletedConditionFailed, condition.Reason)
+	assert.Contains(t, condition.Message, "op-ss-1")
+}
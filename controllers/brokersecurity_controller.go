@@ -0,0 +1,155 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/resources"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+// renderBrokerSecurity renders the security settings of a BrokerSecurity as securityRoles broker properties
+func renderBrokerSecurity(security *v1beta2.BrokerSecurity) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "# %s\n", security.Name)
+	for _, setting := range security.Spec.SecuritySettings {
+		for _, permission := range setting.Permissions {
+			for _, role := range permission.Roles {
+				fmt.Fprintf(buf, "securityRoles.\"%s\".\"%s\".%s=true\n", setting.Match, role, permission.Operation)
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// renderBrokerSecuritySecretData renders the BrokerSecurity resources that match a Broker, one key for each
+func renderBrokerSecuritySecretData(securities []v1beta2.BrokerSecurity, broker *v1beta2.Broker) map[string][]byte {
+	data := map[string][]byte{}
+	for i := range securities {
+		security := &securities[i]
+		if security.DeletionTimestamp != nil || !isBrokerMatched(security.Spec.BrokerSelector, broker) {
+			continue
+		}
+		data[security.Name+".properties"] = renderBrokerSecurity(security)
+	}
+	return data
+}
+
+// BrokerSecurityReconciler renders the BrokerSecurity (arkmq.org/v1beta2) resources into the broker properties of
+// the matched Brokers
+type BrokerSecurityReconciler struct {
+	rtclient.Client
+	Scheme *runtime.Scheme
+	log    logr.Logger
+}
+
+func NewBrokerSecurityReconciler(client rtclient.Client, scheme *runtime.Scheme, logger logr.Logger) *BrokerSecurityReconciler {
+	return &BrokerSecurityReconciler{
+		Client: client,
+		Scheme: scheme,
+		log:    logger,
+	}
+}
+
+//+kubebuilder:rbac:groups=arkmq.org,namespace=arkmq-org-broker-operator,resources=brokersecurities,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=arkmq.org,namespace=arkmq-org-broker-operator,resources=brokersecurities/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=arkmq.org,namespace=arkmq-org-broker-operator,resources=brokersecurities/finalizers,verbs=update
+
+func (r *BrokerSecurityReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name, "Reconciling", "BrokerSecurity")
+
+	security := &v1beta2.BrokerSecurity{}
+	found := true
+	if err := r.Get(ctx, request.NamespacedName, security); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		found = false
+	}
+
+	brokers := &v1beta2.BrokerList{}
+	if err := r.List(ctx, brokers, rtclient.InNamespace(request.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+	securities := &v1beta2.BrokerSecurityList{}
+	if err := r.List(ctx, securities, rtclient.InNamespace(request.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the secret of an affected Broker is rendered from all the securities that match it
+	key := request.Name + ".properties"
+	for i := range brokers.Items {
+		broker := &brokers.Items[i]
+		secretName := BrokerSecuritySecretName(broker.Name)
+		if !isAffectedBroker(r.Client, broker, found && isBrokerMatched(security.Spec.BrokerSelector, broker), secretName, key) {
+			continue
+		}
+		data := renderBrokerSecuritySecretData(securities.Items, broker)
+		if err := syncRenderedPropertiesSecret(r.Client, r.Scheme, broker, secretName, data); err != nil {
+			reqLogger.Error(err, "unable to render the security of the Broker", "broker", broker.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	if !found {
+		return ctrl.Result{}, nil
+	}
+
+	status := security.Status.DeepCopy()
+	result := ctrl.Result{}
+	if selectorCondition(&status.Conditions, security.Spec.BrokerSelector) {
+		matched := []v1beta2.Broker{}
+		for _, broker := range brokers.Items {
+			if isBrokerMatched(security.Spec.BrokerSelector, &broker) {
+				matched = append(matched, broker)
+			}
+		}
+		status.Brokers = matchedBrokerStatuses(r.Client, matched, BrokerSecuritySecretName, security.Name+".properties")
+		if matchedBrokersCondition(&status.Conditions, status.Brokers) {
+			result.RequeueAfter = common.GetReconcileResyncPeriod()
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(&security.Status, status) {
+		security.Status = *status
+		if err := resources.UpdateStatus(r.Client, security); err != nil && !apierrors.IsConflict(err) {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (r *BrokerSecurityReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta2.BrokerSecurity{}).
+		Watches(&v1beta2.Broker{}, enqueueAffectingResources(mgr.GetClient(), &v1beta2.BrokerSecurityList{}, func(o rtclient.Object) *metav1.LabelSelector {
+			return o.(*v1beta2.BrokerSecurity).Spec.BrokerSelector
+		}, BrokerSecuritySecretName, r.log), builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}))).
+		Complete(r)
+}
@@ -0,0 +1,76 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// +kubebuilder:docs-gen:collapse=Apache License
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestBrokerSecurityReconcile(t *testing.T) {
+	security := &v1beta2.BrokerSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "test"},
+		Spec: v1beta2.BrokerSecuritySpec{
+			SecuritySettings: []v1beta2.BrokerSecuritySettingType{{
+				Match: "orders",
+				Permissions: []v1beta2.BrokerPermissionType{
+					{Operation: "send", Roles: []string{"producers"}},
+					{Operation: "consume", Roles: []string{"consumers", "admins"}},
+				},
+			}},
+		},
+	}
+	invalid := &v1beta2.BrokerSecurity{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "test"},
+		Spec: v1beta2.BrokerSecuritySpec{
+			BrokerSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Bad"}}},
+		},
+	}
+	cl := newTestClient(newTestBroker("a", 1, nil), newTestBroker("b", 1, nil), security, invalid)
+	r := NewBrokerSecurityReconciler(cl, cl.Scheme(), ctrl.Log)
+
+	request := ctrl.Request{NamespacedName: types.NamespacedName{Name: "orders", Namespace: "test"}}
+	_, err := r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+
+	// an empty selector matches all the brokers
+	for _, name := range []string{"a", "b"} {
+		secret := &corev1.Secret{}
+		assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: BrokerSecuritySecretName(name), Namespace: "test"}, secret))
+		assert.Equal(t, "# orders\n"+
+			"securityRoles.\"orders\".\"producers\".send=true\n"+
+			"securityRoles.\"orders\".\"consumers\".consume=true\n"+
+			"securityRoles.\"orders\".\"admins\".consume=true\n",
+			string(secret.Data["orders.properties"]))
+		assert.NotContains(t, secret.Data, "invalid.properties")
+	}
+	assert.NoError(t, cl.Get(context.TODO(), request.NamespacedName, security))
+	assert.Len(t, security.Status.Brokers, 2)
+
+	_, err = r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "invalid", Namespace: "test"}})
+	assert.NoError(t, err)
+	assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Name: "invalid", Namespace: "test"}, invalid))
+	valid := meta.FindStatusCondition(invalid.Status.Conditions, v1beta2.ValidConditionType)
+	assert.Equal(t, v1beta2.ValidConditionSpecSelectorError, valid.Reason)
+	assert.False(t, meta.IsStatusConditionTrue(invalid.Status.Conditions, v1beta2.ReadyConditionType))
+}
@@ -0,0 +1,59 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	broker "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+)
+
+//+kubebuilder:webhook:path=/validate-arkmq-org-v1beta2-brokerservice,mutating=false,failurePolicy=fail,sideEffects=None,groups=arkmq.org,resources=brokerservices,verbs=create;update,versions=v1beta2,name=vbrokerservice.arkmq.org,admissionReviewVersions=v1
+
+// BrokerServiceWebhook validates BrokerService resources at admission with the validation of the reconciler
+type BrokerServiceWebhook struct{}
+
+func (w *BrokerServiceWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&broker.BrokerService{}).
+		WithValidator(w).
+		Complete()
+}
+
+func (w *BrokerServiceWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	service, ok := obj.(*broker.BrokerService)
+	if !ok {
+		return nil, fmt.Errorf("expected a BrokerService but got a %T", obj)
+	}
+	processor := &BrokerServiceInstanceReconciler{instance: service, status: service.Status.DeepCopy()}
+	if err := processor.validateSpec(); err != nil {
+		return nil, apierrors.NewForbidden(broker.GroupVersion.WithResource("brokerservices").GroupResource(), service.Name, err)
+	}
+	return nil, nil
+}
+
+func (w *BrokerServiceWebhook) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	return w.ValidateCreate(ctx, newObj)
+}
+
+func (w *BrokerServiceWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -47,6 +47,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"software.sslmate.com/src/go-pkcs12"
 
 	cmv1 "github.com/cert-manager/cert-manager/pkg/apis/certmanager/v1"
@@ -1201,6 +1202,30 @@ func (m *NillCluster) Start(ctx context.Context) error {
 	return nil
 }
 
+// newTestClient is a fake client with the resources of the operator and their status subresource
+func newTestClient(objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = brokerv1beta1.AddToScheme(s)
+	_ = brokerv1beta2.AddToScheme(s)
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).
+		WithStatusSubresource(&brokerv1beta1.ActiveMQArtemis{}, &brokerv1beta1.ActiveMQArtemisAddress{},
+			&brokerv1beta2.Broker{}, &brokerv1beta2.BrokerAddress{}, &brokerv1beta2.BrokerSecurity{}, &brokerv1beta2.BrokerOperation{}).
+		Build()
+}
+
+// newTestBroker is a Broker of the test namespace, customFunc sets the fields under test
+func newTestBroker(name string, size int32, customFunc func(candidate *brokerv1beta2.Broker)) *brokerv1beta2.Broker {
+	broker := &brokerv1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec:       brokerv1beta2.BrokerSpec{DeploymentPlan: brokerv1beta2.DeploymentPlanType{Size: &size}},
+	}
+	if customFunc != nil {
+		customFunc(broker)
+	}
+	return broker
+}
+
 func CloneStringMap(original map[string]string) map[string]string {
 	copy := make(map[string]string)
 	for key, value := range original {
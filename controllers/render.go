@@ -0,0 +1,216 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+// renderRounds bounds the reconciles of a render, an app binds to a service that creates a broker that needs a
+// second pass to see its own secrets
+const renderRounds = 3
+
+// RenderResult holds the resources the operator would create for the input of a render and the problems that stop
+// a resource from being rendered, a Valid condition that is false or a reconcile error
+type RenderResult struct {
+	Resources []rtclient.Object
+	Problems  []string
+}
+
+// Render reconciles the Broker, BrokerService and BrokerApp resources of the input with a client that holds the
+// input, like the in memory client of the render command. The other input resources, like secrets, are dependencies.
+// The resources are returned sorted by kind and name, without the fields that only a server sets
+func Render(client rtclient.Client, input []rtclient.Object, scheme *runtime.Scheme, isOnOpenShift bool, logger logr.Logger) (*RenderResult, error) {
+	inputKeys := map[string]bool{}
+	for _, obj := range input {
+		key, err := renderKey(obj, scheme)
+		if err != nil {
+			return nil, err
+		}
+		inputKeys[key] = true
+	}
+
+	brokerReconciler := &BrokerReconciler{Client: client, Scheme: scheme, log: logger, isOnOpenShift: isOnOpenShift}
+	serviceReconciler := NewBrokerServiceReconciler(client, scheme, nil, logger)
+	appReconciler := NewBrokerAppReconciler(client, scheme, nil, logger)
+
+	// the errors of the last round, an error of an earlier round may be fixed by a later reconcile
+	reconcileErrors := map[string]error{}
+	track := func(kind string, name string, err error) {
+		key := fmt.Sprintf("%s %s", kind, name)
+		if err != nil {
+			logger.V(1).Info("render reconcile error", kind, name, "error", err)
+			reconcileErrors[key] = err
+		} else {
+			delete(reconcileErrors, key)
+		}
+	}
+
+	ctx := context.TODO()
+	for round := 0; round < renderRounds; round++ {
+		apps := &v1beta2.BrokerAppList{}
+		if err := client.List(ctx, apps); err != nil {
+			return nil, err
+		}
+		for _, app := range apps.Items {
+			_, err := appReconciler.Reconcile(ctx, renderRequest(&app))
+			track("BrokerApp", app.Name, err)
+		}
+		services := &v1beta2.BrokerServiceList{}
+		if err := client.List(ctx, services); err != nil {
+			return nil, err
+		}
+		for _, service := range services.Items {
+			_, err := serviceReconciler.Reconcile(ctx, renderRequest(&service))
+			track("BrokerService", service.Name, err)
+		}
+		brokers := &v1beta2.BrokerList{}
+		if err := client.List(ctx, brokers); err != nil {
+			return nil, err
+		}
+		for _, broker := range brokers.Items {
+			_, err := brokerReconciler.Reconcile(ctx, renderRequest(&broker))
+			track("Broker", broker.Name, err)
+		}
+	}
+
+	result := &RenderResult{}
+	for key, err := range reconcileErrors {
+		result.Problems = append(result.Problems, fmt.Sprintf("%s failed to reconcile, %v", key, err))
+	}
+	if err := renderInvalid(ctx, client, result); err != nil {
+		return nil, err
+	}
+	sort.Strings(result.Problems)
+
+	lists := []rtclient.ObjectList{
+		&v1beta2.BrokerList{},
+		&corev1.SecretList{},
+		&corev1.ConfigMapList{},
+		&appsv1.StatefulSetList{},
+		&corev1.ServiceList{},
+		&netv1.IngressList{},
+		&policyv1.PodDisruptionBudgetList{},
+	}
+	if isOnOpenShift {
+		lists = append(lists, &routev1.RouteList{})
+	}
+	if common.IsGatewayAPIAvailable() {
+		lists = append(lists, &gatewayv1alpha2.TLSRouteList{}, &gatewayv1beta1.HTTPRouteList{})
+	}
+	for _, list := range lists {
+		if err := client.List(ctx, list); err != nil {
+			return nil, err
+		}
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(objs, func(i, j int) bool {
+			return objs[i].(rtclient.Object).GetNamespace()+"/"+objs[i].(rtclient.Object).GetName() <
+				objs[j].(rtclient.Object).GetNamespace()+"/"+objs[j].(rtclient.Object).GetName()
+		})
+		for _, o := range objs {
+			obj := o.(rtclient.Object)
+			key, err := renderKey(obj, scheme)
+			if err != nil {
+				return nil, err
+			}
+			if inputKeys[key] {
+				continue
+			}
+			gvk, _ := apiutil.GVKForObject(obj, scheme)
+			obj.GetObjectKind().SetGroupVersionKind(gvk)
+			obj.SetResourceVersion("")
+			obj.SetManagedFields(nil)
+			obj.SetUID("")
+			ownerReferences := obj.GetOwnerReferences()
+			for i := range ownerReferences {
+				ownerReferences[i].UID = ""
+			}
+			obj.SetOwnerReferences(ownerReferences)
+			if broker, ok := obj.(*v1beta2.Broker); ok {
+				broker.Status = v1beta2.BrokerStatus{}
+			}
+			result.Resources = append(result.Resources, obj)
+		}
+	}
+	return result, nil
+}
+
+// renderInvalid collects the Valid conditions that are not true, the resources that could not be rendered
+func renderInvalid(ctx context.Context, client rtclient.Client, result *RenderResult) error {
+	brokers := &v1beta2.BrokerList{}
+	if err := client.List(ctx, brokers); err != nil {
+		return err
+	}
+	for _, broker := range brokers.Items {
+		result.addInvalid("Broker", broker.Name, broker.Status.Conditions)
+	}
+	services := &v1beta2.BrokerServiceList{}
+	if err := client.List(ctx, services); err != nil {
+		return err
+	}
+	for _, service := range services.Items {
+		result.addInvalid("BrokerService", service.Name, service.Status.Conditions)
+	}
+	apps := &v1beta2.BrokerAppList{}
+	if err := client.List(ctx, apps); err != nil {
+		return err
+	}
+	for _, app := range apps.Items {
+		result.addInvalid("BrokerApp", app.Name, app.Status.Conditions)
+	}
+	return nil
+}
+
+func (result *RenderResult) addInvalid(kind string, name string, conditions []metav1.Condition) {
+	if condition := meta.FindStatusCondition(conditions, v1beta2.ValidConditionType); condition != nil && condition.Status == metav1.ConditionFalse {
+		result.Problems = append(result.Problems, fmt.Sprintf("%s %s is not valid, %s", kind, name, condition.Message))
+	}
+}
+
+func renderRequest(obj rtclient.Object) ctrl.Request {
+	return ctrl.Request{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}
+}
+
+func renderKey(obj rtclient.Object, scheme *runtime.Scheme) (string, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s/%s", gvk.GroupKind(), obj.GetNamespace(), obj.GetName()), nil
+}
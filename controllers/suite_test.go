@@ -559,6 +559,33 @@ func createControllerManager(disableMetrics bool, watchNamespace string) {
 	err = addressReconciler.SetupWithManager(k8Manager, managerCtx)
 	Expect(err).ToNot(HaveOccurred(), "failed to create address reconciler")
 
+	addressPropertiesReconciler := NewAddressPropertiesReconciler(
+		k8Manager.GetClient(),
+		k8Manager.GetScheme(),
+		ctrl.Log,
+	)
+
+	err = addressPropertiesReconciler.SetupWithManager(k8Manager)
+	Expect(err).ShouldNot(HaveOccurred(), "failed to create address properties reconciler")
+
+	brokerAddressReconciler := NewBrokerAddressReconciler(
+		k8Manager.GetClient(),
+		k8Manager.GetScheme(),
+		ctrl.Log,
+	)
+
+	err = brokerAddressReconciler.SetupWithManager(k8Manager)
+	Expect(err).ShouldNot(HaveOccurred(), "failed to create broker address reconciler")
+
+	brokerSecurityReconciler := NewBrokerSecurityReconciler(
+		k8Manager.GetClient(),
+		k8Manager.GetScheme(),
+		ctrl.Log,
+	)
+
+	err = brokerSecurityReconciler.SetupWithManager(k8Manager)
+	Expect(err).ShouldNot(HaveOccurred(), "failed to create broker security reconciler")
+
 	scaleDownRconciler := &ActiveMQArtemisScaledownReconciler{
 		Client: k8Manager.GetClient(),
 		Scheme: k8Manager.GetScheme(),
@@ -29,6 +29,8 @@ require (
 	golang.org/x/crypto v0.47.0
 	k8s.io/apiextensions-apiserver v0.29.7
 	k8s.io/utils v0.0.0-20230726121419-3b25d923346b
+	sigs.k8s.io/gateway-api v0.7.0
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
@@ -87,8 +89,6 @@ require (
 	k8s.io/component-base v0.29.7 // indirect
 	k8s.io/klog/v2 v2.110.1 // indirect
 	k8s.io/kube-openapi v0.0.0-20231010175941-2dd684a91f00 // indirect
-	sigs.k8s.io/gateway-api v0.7.0 // indirect
 	sigs.k8s.io/json v0.0.0-20221116044647-bc3834ca7abd // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.4.1 // indirect
-	sigs.k8s.io/yaml v1.3.0 // indirect
 )
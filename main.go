@@ -50,10 +50,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	routev1 "github.com/openshift/api/route/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/log"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/render"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/sdkk8sutil"
 	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia"
 
 	brokerv1alpha1 "github.com/arkmq-org/activemq-artemis-operator/api/v1alpha1"
 	brokerv1beta1 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
@@ -91,6 +95,8 @@ func printVersion() {
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(routev1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
 
 	utilruntime.Must(brokerv2alpha1.AddToScheme(scheme))
 	utilruntime.Must(brokerv2alpha2.AddToScheme(scheme))
@@ -112,6 +118,10 @@ func (l *TraceLogWarnings) HandleWarningHeader(code int, agent string, message s
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == render.Command {
+		os.Exit(render.Run(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var leaseDurationSeconds int64
@@ -147,6 +157,8 @@ func main() {
 
 	printVersion()
 
+	jolokia.LoadPoliciesFromEnv(os.LookupEnv)
+
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
 	if err != nil {
@@ -257,6 +269,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	if _, err := common.DetectGatewayAPIWith(cfg); err != nil {
+		setupLog.Error(err, "can't determine gateway api availability")
+		os.Exit(1)
+	}
+
+	if _, err := common.DetectImageCatalogWith(cfg); err != nil {
+		setupLog.Error(err, "can't determine broker image catalog availability")
+		os.Exit(1)
+	}
+
 	brokerReconciler := controllers.NewActiveMQArtemisReconciler(
 		mgr,
 		ctrl.Log.WithName("ActiveMQArtemisReconciler"),
@@ -287,6 +309,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	addressPropertiesReconciler := controllers.NewAddressPropertiesReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		ctrl.Log.WithName("AddressPropertiesReconciler"))
+
+	if err = addressPropertiesReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "AddressProperties")
+		os.Exit(1)
+	}
+
 	scaledownReconciler := controllers.NewActiveMQArtemisScaledownReconciler(
 		mgr.GetClient(),
 		mgr.GetScheme(),
@@ -331,6 +363,76 @@ func main() {
 		os.Exit(1)
 	}
 
+	autoscalerReconciler := controllers.NewBrokerAutoscalerReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		ctrl.Log.WithName("BrokerAutoscalerReconciler"))
+
+	if err = autoscalerReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BrokerAutoscaler")
+		os.Exit(1)
+	}
+
+	operationReconciler := controllers.NewBrokerOperationReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		ctrl.Log.WithName("BrokerOperationReconciler"))
+
+	if err = operationReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BrokerOperation")
+		os.Exit(1)
+	}
+
+	brokerAddressReconciler := controllers.NewBrokerAddressReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		ctrl.Log.WithName("BrokerAddressReconciler"))
+
+	if err = brokerAddressReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BrokerAddress")
+		os.Exit(1)
+	}
+
+	brokerSecurityReconciler := controllers.NewBrokerSecurityReconciler(
+		mgr.GetClient(),
+		mgr.GetScheme(),
+		ctrl.Log.WithName("BrokerSecurityReconciler"))
+
+	if err = brokerSecurityReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BrokerSecurity")
+		os.Exit(1)
+	}
+
+	// the webhooks need a serving certificate and a webhook configuration, see config/webhook
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		if err = controllers.NewBrokerWebhook(mgr.GetClient(), isOpenshift, ctrl.Log.WithName("BrokerWebhook")).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Broker")
+			os.Exit(1)
+		}
+		if err = (&controllers.BrokerServiceWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BrokerService")
+			os.Exit(1)
+		}
+		if err = (&controllers.BrokerAppWebhook{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BrokerApp")
+			os.Exit(1)
+		}
+	}
+
+	if common.IsImageCatalogAvailable() {
+		catalogReconciler := controllers.NewBrokerImageCatalogReconciler(
+			mgr.GetClient(),
+			mgr.GetScheme(),
+			ctrl.Log.WithName("BrokerImageCatalogReconciler"))
+
+		if err = catalogReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "BrokerImageCatalog")
+			os.Exit(1)
+		}
+	} else {
+		setupLog.Info("BrokerImageCatalogs can not be read, the brokers use the images of the operator")
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,63 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+
+	// BrokerCertificateExpiryDays tracks the days before the earliest certificate of each secret of a broker expires
+	BrokerCertificateExpiryDays = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "broker_certificate_expiry_days",
+			Help: "Days before the earliest certificate of a secret that the broker consumes expires, negative once expired",
+		},
+		[]string{"broker", "namespace", "secret", "secret_namespace"},
+	)
+)
+
+func init() {
+	// Register with controller-runtime's metrics registry
+	metrics.Registry.MustRegister(
+		BrokerCertificateExpiryDays,
+	)
+}
+
+// CertificateExpiry is the days to expiry of a secret
+type CertificateExpiry struct {
+	Secret          string
+	SecretNamespace string
+	Days            float64
+}
+
+// UpdateCertificateMetrics replaces the gauges of a broker, the secrets that it no longer consumes are removed
+func UpdateCertificateMetrics(name, namespace string, expiries []CertificateExpiry) {
+	BrokerCertificateExpiryDays.DeletePartialMatch(prometheus.Labels{"broker": name, "namespace": namespace})
+
+	for _, expiry := range expiries {
+		labels := prometheus.Labels{"broker": name, "namespace": namespace, "secret": expiry.Secret, "secret_namespace": expiry.SecretNamespace}
+		BrokerCertificateExpiryDays.With(labels).Set(expiry.Days)
+	}
+}
+
+// DeleteCertificateMetrics removes all the gauges of a broker when it's deleted
+func DeleteCertificateMetrics(name, namespace string) {
+	BrokerCertificateExpiryDays.DeletePartialMatch(prometheus.Labels{"broker": name, "namespace": namespace})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Certificate Metrics", func() {
+	BeforeEach(func() {
+		// Clean up metrics before each test
+		BrokerCertificateExpiryDays.Reset()
+	})
+
+	It("UpdateCertificateMetrics sets a gauge for each secret", func() {
+		UpdateCertificateMetrics("broker", "test-ns", []CertificateExpiry{
+			{Secret: "tls", SecretNamespace: "test-ns", Days: 12.5},
+			{Secret: "ca", SecretNamespace: "operator-ns", Days: -1},
+		})
+
+		val := testutil.ToFloat64(BrokerCertificateExpiryDays.With(prometheus.Labels{
+			"broker":           "broker",
+			"namespace":        "test-ns",
+			"secret":           "tls",
+			"secret_namespace": "test-ns",
+		}))
+		Expect(val).To(Equal(12.5))
+		Expect(testutil.CollectAndCount(BrokerCertificateExpiryDays)).To(Equal(2))
+	})
+
+	It("UpdateCertificateMetrics removes the secrets no longer consumed", func() {
+		UpdateCertificateMetrics("broker", "test-ns", []CertificateExpiry{
+			{Secret: "old", SecretNamespace: "test-ns", Days: 1},
+		})
+		UpdateCertificateMetrics("other", "test-ns", []CertificateExpiry{
+			{Secret: "old", SecretNamespace: "test-ns", Days: 1},
+		})
+		UpdateCertificateMetrics("broker", "test-ns", []CertificateExpiry{
+			{Secret: "new", SecretNamespace: "test-ns", Days: 90},
+		})
+
+		Expect(testutil.CollectAndCount(BrokerCertificateExpiryDays)).To(Equal(2))
+	})
+
+	It("DeleteCertificateMetrics removes the gauges of the broker", func() {
+		UpdateCertificateMetrics("delete-me", "test-ns", []CertificateExpiry{
+			{Secret: "tls", SecretNamespace: "test-ns", Days: 1},
+		})
+		UpdateCertificateMetrics("keep-me", "test-ns", []CertificateExpiry{
+			{Secret: "tls", SecretNamespace: "test-ns", Days: 1},
+		})
+
+		DeleteCertificateMetrics("delete-me", "test-ns")
+
+		Expect(testutil.CollectAndCount(BrokerCertificateExpiryDays)).To(Equal(1))
+	})
+})
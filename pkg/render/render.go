@@ -0,0 +1,211 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	routev1 "github.com/openshift/api/route/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	"sigs.k8s.io/yaml"
+
+	brokerv1alpha1 "github.com/arkmq-org/activemq-artemis-operator/api/v1alpha1"
+	brokerv1beta1 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta1"
+	brokerv1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	brokerv2alpha1 "github.com/arkmq-org/activemq-artemis-operator/api/v2alpha1"
+	brokerv2alpha2 "github.com/arkmq-org/activemq-artemis-operator/api/v2alpha2"
+	brokerv2alpha3 "github.com/arkmq-org/activemq-artemis-operator/api/v2alpha3"
+	brokerv2alpha4 "github.com/arkmq-org/activemq-artemis-operator/api/v2alpha4"
+	brokerv2alpha5 "github.com/arkmq-org/activemq-artemis-operator/api/v2alpha5"
+	"github.com/arkmq-org/activemq-artemis-operator/controllers"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+// Command is the subcommand of the operator binary that renders a yaml file
+const Command = "render"
+
+// scheme holds the types of the operator, the input is decoded with it
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(routev1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1beta1.AddToScheme(scheme))
+
+	utilruntime.Must(brokerv2alpha1.AddToScheme(scheme))
+	utilruntime.Must(brokerv2alpha2.AddToScheme(scheme))
+	utilruntime.Must(brokerv2alpha3.AddToScheme(scheme))
+	utilruntime.Must(brokerv2alpha4.AddToScheme(scheme))
+	utilruntime.Must(brokerv2alpha5.AddToScheme(scheme))
+	utilruntime.Must(brokerv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(brokerv1beta1.AddToScheme(scheme))
+	utilruntime.Must(brokerv1beta2.AddToScheme(scheme))
+}
+
+// Run prints the resources the operator would create for the Broker, BrokerService and BrokerApp resources of
+// the input, the other input resources, like the secrets they reference, are only read
+func Run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet(Command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("f", "-", "The yaml file with the resources to render, - reads from stdin.")
+	namespace := flags.String("namespace", "default", "The namespace of the resources without one.")
+	isOnOpenShift := flags.Bool("openshift", false, "Render for OpenShift, with routes in place of ingresses.")
+	isGatewayAPI := flags.Bool("gateway-api", false, "Render with the Gateway API available, for the gateway expose mode.")
+	operatorNamespace := flags.String("operator-namespace", "", "The namespace of the operator secrets that a BrokerService or a restricted Broker needs.")
+	opts := zap.Options{}
+	opts.BindFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	logger := zap.New(zap.UseFlagOptions(&opts), zap.WriteTo(stderr))
+	ctrl.SetLogger(logger)
+
+	if *operatorNamespace != "" {
+		common.SetOperatorNameSpace(*operatorNamespace)
+	}
+	common.SetGatewayAPIAvailable(*isGatewayAPI)
+
+	in := stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to open %s, %v\n", *file, err)
+			return 1
+		}
+		defer f.Close()
+		in = f
+	}
+
+	input, err := decodeInput(in, *namespace)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to decode %s, %v\n", *file, err)
+		return 1
+	}
+
+	result, err := Render(input, *isOnOpenShift, logger.WithName(Command))
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to render, %v\n", err)
+		return 1
+	}
+
+	for _, obj := range result.Resources {
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to marshal %s, %v\n", obj.GetName(), err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "---\n%s", out)
+	}
+
+	for _, problem := range result.Problems {
+		fmt.Fprintln(stderr, problem)
+	}
+	if len(result.Problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Render reconciles the input against an in memory client, see controllers.Render
+func Render(input []rtclient.Object, isOnOpenShift bool, logger logr.Logger) (*controllers.RenderResult, error) {
+	objects := make([]rtclient.Object, 0, len(input))
+	for _, obj := range input {
+		obj = obj.DeepCopyObject().(rtclient.Object)
+		setUID(obj)
+		objects = append(objects, obj)
+	}
+
+	// the owner of a resource is matched by uid, the fake client does not set one
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, client rtclient.WithWatch, obj rtclient.Object, opts ...rtclient.CreateOption) error {
+				setUID(obj)
+				return client.Create(ctx, obj, opts...)
+			},
+		}).
+		WithStatusSubresource(&brokerv1beta2.Broker{}, &brokerv1beta2.BrokerService{}, &brokerv1beta2.BrokerApp{}).
+		WithIndex(&brokerv1beta2.BrokerApp{}, common.AppServiceAnnotation, func(obj rtclient.Object) []string {
+			if val, ok := obj.GetAnnotations()[common.AppServiceAnnotation]; ok {
+				return []string{val}
+			}
+			return nil
+		}).Build()
+
+	return controllers.Render(client, input, scheme, isOnOpenShift, logger)
+}
+
+// decodeInput decodes the documents of a multi document yaml to typed objects of the operator scheme
+func decodeInput(in io.Reader, namespace string) ([]rtclient.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(in))
+
+	var objects []rtclient.Object
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		decoded, _, err := decoder.Decode(doc, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := decoded.(rtclient.Object)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object %v", decoded.GetObjectKind().GroupVersionKind())
+		}
+		// a catalog is cluster scoped
+		if _, catalog := obj.(*brokerv1beta2.BrokerImageCatalog); !catalog && obj.GetNamespace() == "" {
+			obj.SetNamespace(namespace)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+func setUID(obj rtclient.Object) {
+	if obj.GetUID() == "" {
+		obj.SetUID(uuid.NewUUID())
+	}
+}
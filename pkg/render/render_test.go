@@ -0,0 +1,223 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/controllers"
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+func TestDecodeInput(t *testing.T) {
+	input := `apiVersion: arkmq.org/v1beta2
+kind: Broker
+metadata:
+  name: broker
+spec:
+  brokerProperties:
+  - globalMaxSize=512M
+---
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  namespace: other
+stringData:
+  user: admin
+---
+apiVersion: arkmq.org/v1beta2
+kind: BrokerImageCatalog
+metadata:
+  name: catalog
+`
+	objects, err := decodeInput(strings.NewReader(input), "test")
+	assert.NoError(t, err)
+	if assert.Len(t, objects, 3) {
+		broker, ok := objects[0].(*v1beta2.Broker)
+		if assert.True(t, ok) {
+			assert.Equal(t, "test", broker.Namespace)
+			assert.Equal(t, []string{"globalMaxSize=512M"}, broker.Spec.BrokerProperties)
+		}
+		secret, ok := objects[1].(*corev1.Secret)
+		if assert.True(t, ok) {
+			assert.Equal(t, "other", secret.Namespace)
+		}
+		catalog, ok := objects[2].(*v1beta2.BrokerImageCatalog)
+		if assert.True(t, ok) {
+			// a catalog is cluster scoped and keeps no namespace
+			assert.Empty(t, catalog.Namespace)
+		}
+	}
+
+	objects, err = decodeInput(strings.NewReader(""), "test")
+	assert.NoError(t, err)
+	assert.Empty(t, objects)
+
+	_, err = decodeInput(strings.NewReader("apiVersion: example.com/v1\nkind: Unknown\nmetadata:\n  name: x\n"), "test")
+	assert.Error(t, err)
+}
+
+func TestRunInvalidBroker(t *testing.T) {
+	stdin := strings.NewReader("apiVersion: arkmq.org/v1beta2\nkind: Broker\nmetadata:\n  name: render\nspec:\n  brokerProperties:\n  - globalMaxSise=512M\n")
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(t, 1, Run([]string{"--namespace", "test"}, stdin, stdout, stderr))
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "Broker render is not valid")
+
+	assert.Equal(t, 2, Run([]string{"--unknown"}, strings.NewReader(""), stdout, stderr))
+}
+
+func renderTLSSecret(t *testing.T, name string, namespace string) *corev1.Secret {
+	key, err := rsa.GenerateKey(crand.Reader, 2048)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}
+	der, err := x509.CreateCertificate(crand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		},
+	}
+}
+
+func TestRenderBroker(t *testing.T) {
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "render", Namespace: "test"},
+		Spec: v1beta2.BrokerSpec{
+			BrokerProperties: []string{"globalMaxSize=512M"},
+			IngressDomain:    "example.com",
+			Acceptors:        []v1beta2.AcceptorType{{Name: "amqp", Port: 5672, Expose: true}},
+			Console:          v1beta2.ConsoleType{Expose: false},
+		},
+	}
+
+	result, err := Render([]rtclient.Object{broker}, false, ctrl.Log)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Problems)
+
+	kinds := map[string][]string{}
+	var propsSecret *corev1.Secret
+	for _, obj := range result.Resources {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		kinds[kind] = append(kinds[kind], obj.GetName())
+		assert.Empty(t, obj.GetResourceVersion())
+		if secret, ok := obj.(*corev1.Secret); ok && strings.HasPrefix(secret.Name, "render-props") {
+			propsSecret = secret
+		}
+		if ss, ok := obj.(*appsv1.StatefulSet); ok {
+			assert.Equal(t, "render-ss", ss.Name)
+		}
+		if ingress, ok := obj.(*netv1.Ingress); ok {
+			assert.Contains(t, ingress.Name, "amqp")
+		}
+	}
+	assert.NotContains(t, kinds, "Broker")
+	assert.Equal(t, []string{"render-ss"}, kinds["StatefulSet"])
+	assert.Len(t, kinds["Ingress"], 1)
+	assert.NotEmpty(t, kinds["Service"])
+	if assert.NotNil(t, propsSecret) {
+		assert.Contains(t, string(propsSecret.Data[controllers.BrokerPropertiesName]), "globalMaxSize=512M")
+	}
+}
+
+func TestRenderInvalidBroker(t *testing.T) {
+	broker := &v1beta2.Broker{
+		ObjectMeta: metav1.ObjectMeta{Name: "render", Namespace: "test"},
+		Spec:       v1beta2.BrokerSpec{BrokerProperties: []string{"globalMaxSise=512M"}},
+	}
+
+	result, err := Render([]rtclient.Object{broker}, false, ctrl.Log)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Resources)
+	if assert.Len(t, result.Problems, 1) {
+		assert.Contains(t, result.Problems[0], "Broker render is not valid")
+		assert.Contains(t, result.Problems[0], "did you mean globalMaxSize")
+	}
+}
+
+func TestRenderBrokerServiceAndApp(t *testing.T) {
+	service := &v1beta2.BrokerService{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "test", Labels: map[string]string{"type": "broker"}},
+	}
+	app := &v1beta2.BrokerApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "test"},
+		Spec: v1beta2.BrokerAppSpec{
+			ServiceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "broker"}},
+			Capabilities: []v1beta2.AppCapabilityType{{
+				ProducerOf: []v1beta2.AppAddressType{{Address: "orders"}},
+			}},
+		},
+	}
+
+	common.SetOperatorNameSpace("operator")
+	defer common.UnsetOperatorNameSpace()
+	ca := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: common.GetOperatorCASecretName(), Namespace: "operator"},
+		Data:       map[string][]byte{"ca.pem": []byte("ca")},
+	}
+
+	cert := renderTLSSecret(t, common.GetOperatorCertSecretName(), "operator")
+
+	// issued by cert-manager on a cluster
+	operandCert := renderTLSSecret(t, common.DefaultOperandCertSecretName, "test")
+
+	result, err := Render([]rtclient.Object{service, app, ca, cert, operandCert}, false, ctrl.Log)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Problems)
+
+	names := map[string]bool{}
+	for _, obj := range result.Resources {
+		names[obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName()] = true
+		if secret, ok := obj.(*corev1.Secret); ok && secret.Name == controllers.AppPropertiesSecretName("svc") {
+			assert.Contains(t, secret.Annotations[common.ProvisionedAppsAnnotation], "app")
+		}
+	}
+	assert.True(t, names["Broker/svc"], names)
+	assert.True(t, names["Secret/"+controllers.AppPropertiesSecretName("svc")], names)
+	assert.True(t, names["Service/svc"], names)
+	assert.True(t, names["Secret/app-binding-secret"], names)
+	assert.True(t, names["StatefulSet/svc-ss"], names)
+	assert.NotContains(t, names, "Secret/"+ca.Name)
+}
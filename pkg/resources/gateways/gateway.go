@@ -0,0 +1,96 @@
+package gateways
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func NewTLSRouteForCR(existing *gatewayv1alpha2.TLSRoute, namespacedName types.NamespacedName, labels map[string]string, targetServiceName string, targetPort int32, gateway types.NamespacedName, listener string, domain string, brokerHost string) *gatewayv1alpha2.TLSRoute {
+
+	desired := existing
+	if desired == nil {
+		desired = &gatewayv1alpha2.TLSRoute{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: gatewayv1alpha2.GroupVersion.String(),
+				Kind:       "TLSRoute",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       gatewayv1alpha2.TLSRouteSpec{},
+		}
+	}
+	//apply desired
+	desired.ObjectMeta.Labels = labels
+	desired.ObjectMeta.Name = targetServiceName + "-tlsrte"
+	desired.ObjectMeta.Namespace = namespacedName.Namespace
+
+	desired.Spec.ParentRefs = []gatewayv1alpha2.ParentReference{parentRef(gateway, listener)}
+	desired.Spec.Hostnames = hostnames(desired.ObjectMeta.Name, namespacedName.Namespace, domain, brokerHost)
+	desired.Spec.Rules = []gatewayv1alpha2.TLSRouteRule{{
+		BackendRefs: []gatewayv1alpha2.BackendRef{backendRef(targetServiceName, targetPort)},
+	}}
+
+	return desired
+}
+
+func NewHTTPRouteForCR(existing *gatewayv1beta1.HTTPRoute, namespacedName types.NamespacedName, labels map[string]string, targetServiceName string, targetPort int32, gateway types.NamespacedName, listener string, domain string, brokerHost string) *gatewayv1beta1.HTTPRoute {
+
+	desired := existing
+	if desired == nil {
+		desired = &gatewayv1beta1.HTTPRoute{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: gatewayv1beta1.GroupVersion.String(),
+				Kind:       "HTTPRoute",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       gatewayv1beta1.HTTPRouteSpec{},
+		}
+	}
+	//apply desired
+	desired.ObjectMeta.Labels = labels
+	desired.ObjectMeta.Name = targetServiceName + "-httprte"
+	desired.ObjectMeta.Namespace = namespacedName.Namespace
+
+	desired.Spec.ParentRefs = []gatewayv1beta1.ParentReference{parentRef(gateway, listener)}
+	desired.Spec.Hostnames = hostnames(desired.ObjectMeta.Name, namespacedName.Namespace, domain, brokerHost)
+	desired.Spec.Rules = []gatewayv1beta1.HTTPRouteRule{{
+		BackendRefs: []gatewayv1beta1.HTTPBackendRef{{BackendRef: backendRef(targetServiceName, targetPort)}},
+	}}
+
+	return desired
+}
+
+func parentRef(gateway types.NamespacedName, listener string) gatewayv1beta1.ParentReference {
+	ref := gatewayv1beta1.ParentReference{
+		Name: gatewayv1beta1.ObjectName(gateway.Name),
+	}
+	if gateway.Namespace != "" {
+		namespace := gatewayv1beta1.Namespace(gateway.Namespace)
+		ref.Namespace = &namespace
+	}
+	if listener != "" {
+		sectionName := gatewayv1beta1.SectionName(listener)
+		ref.SectionName = &sectionName
+	}
+	return ref
+}
+
+func backendRef(targetServiceName string, targetPort int32) gatewayv1beta1.BackendRef {
+	port := gatewayv1beta1.PortNumber(targetPort)
+	return gatewayv1beta1.BackendRef{
+		BackendObjectReference: gatewayv1beta1.BackendObjectReference{
+			Name: gatewayv1beta1.ObjectName(targetServiceName),
+			Port: &port,
+		},
+	}
+}
+
+func hostnames(name string, namespace string, domain string, brokerHost string) []gatewayv1beta1.Hostname {
+	if brokerHost != "" {
+		return []gatewayv1beta1.Hostname{gatewayv1beta1.Hostname(brokerHost)}
+	} else if domain != "" {
+		return []gatewayv1beta1.Hostname{gatewayv1beta1.Hostname(name + "-" + namespace + "." + domain)}
+	}
+	return nil
+}
@@ -105,3 +105,53 @@ func NewPingServiceDefinitionForCR2(client client.Client, serviceName string, na
 
 	return svc
 }
+
+func NewExposedServiceDefinitionForCR(svcName types.NamespacedName, nameSuffix string, portNumber int32, serviceType corev1.ServiceType, nodePort int32, sourceRanges []string, annotations map[string]string, selectorLabels map[string]string, labels map[string]string, svc *corev1.Service) *corev1.Service {
+
+	if svc == nil {
+		svc = &corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Service",
+			},
+			ObjectMeta: metav1.ObjectMeta{},
+			Spec:       corev1.ServiceSpec{},
+		}
+	}
+
+	// keep the node port that kubernetes assigned
+	if nodePort == 0 {
+		for _, existing := range svc.Spec.Ports {
+			if existing.Name == nameSuffix {
+				nodePort = existing.NodePort
+			}
+		}
+	}
+
+	// apply desired
+	port := corev1.ServicePort{
+		Name:       nameSuffix,
+		Protocol:   "TCP",
+		Port:       portNumber,
+		TargetPort: intstr.FromInt(int(portNumber)),
+		NodePort:   nodePort,
+	}
+
+	svc.ObjectMeta.Labels = labels
+	svc.ObjectMeta.Annotations = annotations
+	svc.ObjectMeta.Name = svcName.Name
+	svc.ObjectMeta.Namespace = svcName.Namespace
+
+	svc.Spec.Type = serviceType
+	svc.Spec.Ports = []corev1.ServicePort{port}
+	svc.Spec.Selector = selectorLabels
+	svc.Spec.SessionAffinity = "None"
+	svc.Spec.PublishNotReadyAddresses = true
+	if serviceType == corev1.ServiceTypeLoadBalancer {
+		svc.Spec.LoadBalancerSourceRanges = sourceRanges
+	} else {
+		svc.Spec.LoadBalancerSourceRanges = nil
+	}
+
+	return svc
+}
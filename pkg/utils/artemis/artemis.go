@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 
@@ -43,6 +44,37 @@ type IArtemis interface {
 	DeleteAddress(addressName string) (*jolokia.ResponseData, error)
 	CreateQueueFromConfig(queueConfig string, ignoreIfExists bool) (jolokia.ResponseData, error)
 	UpdateQueue(queueConfig string) (jolokia.ResponseData, error)
+	PauseQueue(queueName string) error
+	ResumeQueue(queueName string) error
+	PauseAddress(addressName string) error
+	ResumeAddress(addressName string) error
+	PurgeQueue(queueName string) (int64, error)
+	MoveMessages(queueName string, filter string, targetQueueName string) (int64, error)
+	RetryMessages(deadLetterQueueName string) (int64, error)
+	ListConsumers() ([]ConsumerInfo, error)
+	ListConnections() ([]ConnectionInfo, error)
+	CloseConnectionsForUser(userName string) (bool, error)
+	GetQueueStatistics(queueName string) (*QueueStatistics, error)
+	AddressExists(addressName string) (bool, error)
+	QueueExists(queueName string) (bool, error)
+}
+
+// BrokerMetrics are the broker wide counters read in a single request
+type BrokerMetrics struct {
+	TotalMessageCount  int64 `json:"TotalMessageCount"`
+	TotalConsumerCount int64 `json:"TotalConsumerCount"`
+}
+
+// HAState is the role and the replication state of a broker
+type HAState struct {
+	Backup      bool `json:"Backup"`
+	ReplicaSync bool `json:"ReplicaSync"`
+}
+
+// BrokerState is the json status and the HA state of a broker read in a single request
+type BrokerState struct {
+	Status string `json:"Status"`
+	HAState
 }
 
 type Artemis struct {
@@ -210,14 +242,65 @@ func (artemis *Artemis) ScaleDown() (*jolokia.ResponseData, error) {
 	return data, err
 }
 
-func (artemis *Artemis) GetTotalMessageCount() (string, error) {
-	url := "org.apache.activemq.artemis:broker=\"" + artemis.name + "\"/TotalMessageCount"
+func (artemis *Artemis) GetTotalMessageCount() (int64, error) {
+	metrics := &BrokerMetrics{}
+	if err := artemis.ReadAttributes(artemis.brokerMBean(), metrics, "TotalMessageCount"); err != nil {
+		return -1, err
+	}
+	return metrics.TotalMessageCount, nil
+}
+
+func (artemis *Artemis) IsBrokerConnectionConnected(connectionName string) (bool, error) {
+	return artemis.readBooleanAttribute("org.apache.activemq.artemis:broker=\""+artemis.name+"\",component=broker-connections,name=\""+connectionName+"\"", "Connected")
+}
+
+func (artemis *Artemis) readBooleanAttribute(mbean string, attribute string) (bool, error) {
+	url := mbean + "/" + attribute
 	resp, err := artemis.jolokia.Read(url)
-	if err != nil || resp == nil {
-		return "", err
+	if err != nil {
+		return false, err
+	}
+	if resp == nil {
+		return false, fmt.Errorf("unable to retrieve %s, no response", attribute)
 	}
 	if resp.Status != 200 {
-		return "", fmt.Errorf("unable to retrieve TotalMessageCount %v", resp.Error)
+		return false, fmt.Errorf("unable to retrieve %s %v", attribute, resp.Error)
 	}
-	return resp.Value, nil
+	return strconv.ParseBool(resp.Value)
+}
+
+func (artemis *Artemis) brokerMBean() string {
+	return "org.apache.activemq.artemis:broker=\"" + artemis.name + "\""
+}
+
+func (artemis *Artemis) GetBrokerMetrics() (*BrokerMetrics, error) {
+	metrics := &BrokerMetrics{}
+	if err := artemis.ReadAttributes(artemis.brokerMBean(), metrics, "TotalMessageCount", "TotalConsumerCount"); err != nil {
+		return nil, err
+	}
+	return metrics, nil
+}
+
+func (artemis *Artemis) GetBrokerState() (*BrokerState, error) {
+	state := &BrokerState{}
+	if err := artemis.ReadAttributes(artemis.brokerMBean(), state, "Status", "Backup", "ReplicaSync"); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// ReadAttributes reads the attributes of the mbean in a single request and decodes them into
+// value, a struct with a json field tag per attribute name
+func (artemis *Artemis) ReadAttributes(mbean string, value interface{}, attributes ...string) error {
+	resp, err := artemis.jolokia.Bulk([]jolokia.Request{{Type: "read", MBean: mbean, Attribute: attributes}})
+	if err != nil {
+		return err
+	}
+	if len(resp) != 1 || resp[0] == nil {
+		return fmt.Errorf("unable to retrieve %v, no response", attributes)
+	}
+	if resp[0].Status != 200 {
+		return fmt.Errorf("unable to retrieve %v %v", attributes, resp[0].Error)
+	}
+	return resp[0].DecodeValue(value)
 }
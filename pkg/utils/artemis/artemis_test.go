@@ -117,3 +117,127 @@ func createMockArtemis(j jolokia.IJolokia) Artemis {
 		jolokia:     j,
 	}
 }
+
+func TestIsBrokerConnectionConnected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Read(gomock.Eq("org.apache.activemq.artemis:broker=\"someBroker\",component=broker-connections,name=\"dr\"/Connected")).
+		DoAndReturn(func(_ string) (*jolokia.ResponseData, error) {
+			return &jolokia.ResponseData{
+				Status: 200,
+				Value:  "false",
+			}, nil
+		}).
+		Times(1)
+	connected, err := artemis.IsBrokerConnectionConnected("dr")
+
+	assert.False(t, connected)
+	assert.Nil(t, err)
+}
+
+func TestGetBrokerMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Bulk(gomock.Eq([]jolokia.Request{{Type: "read", MBean: "org.apache.activemq.artemis:broker=\"someBroker\"", Attribute: []string{"TotalMessageCount", "TotalConsumerCount"}}})).
+		DoAndReturn(func(_ []jolokia.Request) ([]*jolokia.ResponseData, error) {
+			return []*jolokia.ResponseData{{
+				Status:   200,
+				RawValue: []byte(`{"TotalMessageCount":2000000,"TotalConsumerCount":3}`),
+			}}, nil
+		}).
+		Times(1)
+	metrics, err := artemis.GetBrokerMetrics()
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2000000), metrics.TotalMessageCount)
+	assert.Equal(t, int64(3), metrics.TotalConsumerCount)
+}
+
+func TestGetTotalMessageCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Bulk(gomock.Eq([]jolokia.Request{{Type: "read", MBean: "org.apache.activemq.artemis:broker=\"someBroker\"", Attribute: []string{"TotalMessageCount"}}})).
+		DoAndReturn(func(_ []jolokia.Request) ([]*jolokia.ResponseData, error) {
+			return []*jolokia.ResponseData{{
+				Status:   200,
+				RawValue: []byte(`{"TotalMessageCount":1000000}`),
+			}}, nil
+		}).
+		Times(1)
+	count, err := artemis.GetTotalMessageCount()
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1000000), count)
+}
+
+func TestGetBrokerState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Bulk(gomock.Eq([]jolokia.Request{{Type: "read", MBean: "org.apache.activemq.artemis:broker=\"someBroker\"", Attribute: []string{"Status", "Backup", "ReplicaSync"}}})).
+		DoAndReturn(func(_ []jolokia.Request) ([]*jolokia.ResponseData, error) {
+			return []*jolokia.ResponseData{{
+				Status:   200,
+				RawValue: []byte(`{"Status":"{\"server\":{\"state\":\"STARTED\"}}","Backup":true,"ReplicaSync":false}`),
+			}}, nil
+		}).
+		Times(1)
+	state, err := artemis.GetBrokerState()
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"server":{"state":"STARTED"}}`, state.Status)
+	assert.True(t, state.Backup)
+	assert.False(t, state.ReplicaSync)
+}
+
+func TestGetBrokerStateWithErrorStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+
+	artemis := createMockArtemis(j)
+
+	j.
+		EXPECT().
+		Bulk(gomock.Any()).
+		DoAndReturn(func(_ []jolokia.Request) ([]*jolokia.ResponseData, error) {
+			return []*jolokia.ResponseData{{
+				Status: 404,
+				Error:  "javax.management.InstanceNotFoundException",
+			}}, nil
+		}).
+		Times(1)
+	state, err := artemis.GetBrokerState()
+
+	assert.Nil(t, state)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "InstanceNotFoundException")
+}
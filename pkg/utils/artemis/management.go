@@ -0,0 +1,217 @@
+package artemis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia"
+)
+
+// QueueStatistics are the counters of a queue read in a single request
+type QueueStatistics struct {
+	MessageCount         int64 `json:"MessageCount"`
+	ConsumerCount        int64 `json:"ConsumerCount"`
+	DeliveringCount      int64 `json:"DeliveringCount"`
+	ScheduledCount       int64 `json:"ScheduledCount"`
+	MessagesAdded        int64 `json:"MessagesAdded"`
+	MessagesAcknowledged int64 `json:"MessagesAcknowledged"`
+	MessagesExpired      int64 `json:"MessagesExpired"`
+	MessagesKilled       int64 `json:"MessagesKilled"`
+	Paused               bool  `json:"Paused"`
+}
+
+var queueStatisticsAttributes = []string{
+	"MessageCount", "ConsumerCount", "DeliveringCount", "ScheduledCount",
+	"MessagesAdded", "MessagesAcknowledged", "MessagesExpired", "MessagesKilled", "Paused",
+}
+
+// ConsumerInfo is a consumer as listed by the broker
+type ConsumerInfo struct {
+	ConsumerID      int64  `json:"consumerID"`
+	ConnectionID    string `json:"connectionID"`
+	SessionID       string `json:"sessionID"`
+	QueueName       string `json:"queueName"`
+	BrowseOnly      bool   `json:"browseOnly"`
+	CreationTime    int64  `json:"creationTime"`
+	DeliveringCount int64  `json:"deliveringCount"`
+	Filter          string `json:"filter,omitempty"`
+}
+
+// ConnectionInfo is a client connection as listed by the broker
+type ConnectionInfo struct {
+	ConnectionID   string `json:"connectionID"`
+	ClientAddress  string `json:"clientAddress"`
+	CreationTime   int64  `json:"creationTime"`
+	Implementation string `json:"implementation"`
+	SessionCount   int64  `json:"sessionCount"`
+}
+
+func (artemis *Artemis) addressMBean(addressName string) string {
+	return artemis.brokerMBean() + ",component=addresses,address=\"" + addressName + "\""
+}
+
+// the name of a queue mbean depends on the address and the routing type
+func (artemis *Artemis) searchQueueMBeans(queueName string) ([]string, error) {
+	return artemis.jolokia.Search(artemis.brokerMBean() + ",component=addresses,subcomponent=queues,queue=\"" + queueName + "\",*")
+}
+
+func (artemis *Artemis) queueMBean(queueName string) (string, error) {
+	names, err := artemis.searchQueueMBeans(queueName)
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("queue %s not found", queueName)
+	}
+	if len(names) > 1 {
+		return "", fmt.Errorf("queue %s is ambiguous, found %v", queueName, names)
+	}
+	return names[0], nil
+}
+
+// AddressExists checks whether the address is deployed on the broker
+func (artemis *Artemis) AddressExists(addressName string) (bool, error) {
+	names, err := artemis.jolokia.Search(artemis.addressMBean(addressName))
+	if err != nil {
+		return false, err
+	}
+	return len(names) > 0, nil
+}
+
+// QueueExists checks whether the queue is deployed on the broker
+func (artemis *Artemis) QueueExists(queueName string) (bool, error) {
+	names, err := artemis.searchQueueMBeans(queueName)
+	if err != nil {
+		return false, err
+	}
+	return len(names) > 0, nil
+}
+
+// execOperation invokes the operation of the mbean and decodes the returned value into value when not nil
+func (artemis *Artemis) execOperation(mbean string, operation string, value interface{}, arguments ...interface{}) error {
+	body, err := json.Marshal(jolokia.Request{Type: "exec", MBean: mbean, Operation: operation, Arguments: arguments})
+	if err != nil {
+		return err
+	}
+
+	resp, err := artemis.jolokia.Exec(mbean, string(body))
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return fmt.Errorf("unable to invoke %s, no response", operation)
+	}
+	if resp.Status != 200 {
+		return fmt.Errorf("unable to invoke %s %v", operation, resp.Error)
+	}
+	if value == nil {
+		return nil
+	}
+	return resp.DecodeValue(value)
+}
+
+// execJSONOperation invokes an operation that returns a json document as a string and decodes the document
+func (artemis *Artemis) execJSONOperation(mbean string, operation string, value interface{}, arguments ...interface{}) error {
+	document := ""
+	if err := artemis.execOperation(mbean, operation, &document, arguments...); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(document), value); err != nil {
+		return fmt.Errorf("unable to decode the result of %s, %v", operation, err)
+	}
+	return nil
+}
+
+func (artemis *Artemis) PauseQueue(queueName string) error {
+	mbean, err := artemis.queueMBean(queueName)
+	if err != nil {
+		return err
+	}
+	return artemis.execOperation(mbean, "pause()", nil)
+}
+
+func (artemis *Artemis) ResumeQueue(queueName string) error {
+	mbean, err := artemis.queueMBean(queueName)
+	if err != nil {
+		return err
+	}
+	return artemis.execOperation(mbean, "resume()", nil)
+}
+
+func (artemis *Artemis) PauseAddress(addressName string) error {
+	return artemis.execOperation(artemis.addressMBean(addressName), "pause()", nil)
+}
+
+func (artemis *Artemis) ResumeAddress(addressName string) error {
+	return artemis.execOperation(artemis.addressMBean(addressName), "resume()", nil)
+}
+
+// PurgeQueue removes all the messages of the queue and returns the number of removed messages
+func (artemis *Artemis) PurgeQueue(queueName string) (int64, error) {
+	mbean, err := artemis.queueMBean(queueName)
+	if err != nil {
+		return 0, err
+	}
+	var removed int64
+	err = artemis.execOperation(mbean, "removeAllMessages()", &removed)
+	return removed, err
+}
+
+// MoveMessages moves the messages of the queue that match the filter to the target queue, an empty
+// filter matches all messages, it returns the number of moved messages
+func (artemis *Artemis) MoveMessages(queueName string, filter string, targetQueueName string) (int64, error) {
+	mbean, err := artemis.queueMBean(queueName)
+	if err != nil {
+		return 0, err
+	}
+	var moved int64
+	err = artemis.execOperation(mbean, "moveMessages(java.lang.String,java.lang.String)", &moved, filter, targetQueueName)
+	return moved, err
+}
+
+// RetryMessages sends the messages of a dead letter queue back to their original queues and returns
+// the number of retried messages
+func (artemis *Artemis) RetryMessages(deadLetterQueueName string) (int64, error) {
+	mbean, err := artemis.queueMBean(deadLetterQueueName)
+	if err != nil {
+		return 0, err
+	}
+	var retried int64
+	err = artemis.execOperation(mbean, "retryMessages()", &retried)
+	return retried, err
+}
+
+func (artemis *Artemis) ListConsumers() ([]ConsumerInfo, error) {
+	consumers := []ConsumerInfo{}
+	if err := artemis.execJSONOperation(artemis.brokerMBean(), "listAllConsumersAsJSON()", &consumers); err != nil {
+		return nil, err
+	}
+	return consumers, nil
+}
+
+func (artemis *Artemis) ListConnections() ([]ConnectionInfo, error) {
+	connections := []ConnectionInfo{}
+	if err := artemis.execJSONOperation(artemis.brokerMBean(), "listConnectionsAsJSON()", &connections); err != nil {
+		return nil, err
+	}
+	return connections, nil
+}
+
+// CloseConnectionsForUser closes the connections of the user, it returns false when there was none
+func (artemis *Artemis) CloseConnectionsForUser(userName string) (bool, error) {
+	closed := false
+	err := artemis.execOperation(artemis.brokerMBean(), "closeConnectionsForUser(java.lang.String)", &closed, userName)
+	return closed, err
+}
+
+func (artemis *Artemis) GetQueueStatistics(queueName string) (*QueueStatistics, error) {
+	mbean, err := artemis.queueMBean(queueName)
+	if err != nil {
+		return nil, err
+	}
+	statistics := &QueueStatistics{}
+	if err := artemis.ReadAttributes(mbean, statistics, queueStatisticsAttributes...); err != nil {
+		return nil, err
+	}
+	return statistics, nil
+}
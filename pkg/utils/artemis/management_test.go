@@ -0,0 +1,164 @@
+package artemis
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/jolokia"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testQueueMBean = "org.apache.activemq.artemis:broker=\"someBroker\",component=addresses,address=\"DLA\",subcomponent=queues,routing-type=\"anycast\",queue=\"DLQ\""
+
+func expectQueueSearch(j *jolokia.MockIJolokia, names ...string) {
+	j.
+		EXPECT().
+		Search(gomock.Eq("org.apache.activemq.artemis:broker=\"someBroker\",component=addresses,subcomponent=queues,queue=\"DLQ\",*")).
+		Return(names, nil).
+		Times(1)
+}
+
+// expectExec checks the operation and arguments of the exec request and returns the raw value
+func expectExec(t *testing.T, j *jolokia.MockIJolokia, mbean string, operation string, arguments []interface{}, rawValue string) {
+	j.
+		EXPECT().
+		Exec(gomock.Eq(mbean), gomock.Any()).
+		DoAndReturn(func(_ string, body string) (*jolokia.ResponseData, error) {
+			request := jolokia.Request{}
+			assert.NoError(t, json.Unmarshal([]byte(body), &request))
+			assert.Equal(t, "exec", request.Type)
+			assert.Equal(t, mbean, request.MBean)
+			assert.Equal(t, operation, request.Operation)
+			assert.Equal(t, arguments, request.Arguments)
+			return &jolokia.ResponseData{Status: 200, RawValue: []byte(rawValue)}, nil
+		}).
+		Times(1)
+}
+
+func TestPauseAndResumeQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+	artemis := createMockArtemis(j)
+
+	expectQueueSearch(j, testQueueMBean)
+	expectExec(t, j, testQueueMBean, "pause()", nil, "null")
+	assert.NoError(t, artemis.PauseQueue("DLQ"))
+
+	expectQueueSearch(j, testQueueMBean)
+	expectExec(t, j, testQueueMBean, "resume()", nil, "null")
+	assert.NoError(t, artemis.ResumeQueue("DLQ"))
+
+	expectQueueSearch(j)
+	err := artemis.PauseQueue("DLQ")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
+
+func TestPauseAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+	artemis := createMockArtemis(j)
+
+	expectExec(t, j, "org.apache.activemq.artemis:broker=\"someBroker\",component=addresses,address=\"orders\"", "pause()", nil, "null")
+	assert.NoError(t, artemis.PauseAddress("orders"))
+}
+
+func TestPurgeMoveAndRetryMessages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+	artemis := createMockArtemis(j)
+
+	expectQueueSearch(j, testQueueMBean)
+	expectExec(t, j, testQueueMBean, "removeAllMessages()", nil, "12")
+	removed, err := artemis.PurgeQueue("DLQ")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), removed)
+
+	expectQueueSearch(j, testQueueMBean)
+	expectExec(t, j, testQueueMBean, "moveMessages(java.lang.String,java.lang.String)", []interface{}{"color='red'", "orders"}, "3")
+	moved, err := artemis.MoveMessages("DLQ", "color='red'", "orders")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), moved)
+
+	expectQueueSearch(j, testQueueMBean)
+	expectExec(t, j, testQueueMBean, "retryMessages()", nil, "7")
+	retried, err := artemis.RetryMessages("DLQ")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), retried)
+}
+
+func TestListConsumersAndConnections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+	artemis := createMockArtemis(j)
+	broker := "org.apache.activemq.artemis:broker=\"someBroker\""
+
+	consumers, _ := json.Marshal(`[{"consumerID":0,"connectionID":"c1","sessionID":"s1","queueName":"orders","browseOnly":false,"creationTime":1700000000000,"deliveringCount":2}]`)
+	expectExec(t, j, broker, "listAllConsumersAsJSON()", nil, string(consumers))
+	consumerList, err := artemis.ListConsumers()
+	assert.NoError(t, err)
+	assert.Equal(t, []ConsumerInfo{{ConnectionID: "c1", SessionID: "s1", QueueName: "orders", CreationTime: 1700000000000, DeliveringCount: 2}}, consumerList)
+
+	connections, _ := json.Marshal(`[{"connectionID":"c1","clientAddress":"/10.0.0.1:4000","creationTime":1700000000000,"implementation":"RemotingConnectionImpl","sessionCount":1}]`)
+	expectExec(t, j, broker, "listConnectionsAsJSON()", nil, string(connections))
+	connectionList, err := artemis.ListConnections()
+	assert.NoError(t, err)
+	assert.Len(t, connectionList, 1)
+	assert.Equal(t, "/10.0.0.1:4000", connectionList[0].ClientAddress)
+
+	expectExec(t, j, broker, "listConnectionsAsJSON()", nil, `"not json"`)
+	_, err = artemis.ListConnections()
+	assert.Error(t, err)
+}
+
+func TestCloseConnectionsForUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+	artemis := createMockArtemis(j)
+
+	expectExec(t, j, "org.apache.activemq.artemis:broker=\"someBroker\"", "closeConnectionsForUser(java.lang.String)", []interface{}{"alice"}, "true")
+	closed, err := artemis.CloseConnectionsForUser("alice")
+	assert.NoError(t, err)
+	assert.True(t, closed)
+
+	j.
+		EXPECT().
+		Exec(gomock.Any(), gomock.Any()).
+		Return(&jolokia.ResponseData{Status: 500, Error: "java.lang.SecurityException"}, nil).
+		Times(1)
+	_, err = artemis.CloseConnectionsForUser("alice")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "SecurityException")
+}
+
+func TestGetQueueStatistics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	j := jolokia.NewMockIJolokia(ctrl)
+	artemis := createMockArtemis(j)
+
+	expectQueueSearch(j, testQueueMBean)
+	j.
+		EXPECT().
+		Bulk(gomock.Eq([]jolokia.Request{{Type: "read", MBean: testQueueMBean, Attribute: queueStatisticsAttributes}})).
+		Return([]*jolokia.ResponseData{{
+			Status:   200,
+			RawValue: []byte(`{"MessageCount":5,"ConsumerCount":1,"DeliveringCount":1,"ScheduledCount":0,"MessagesAdded":20,"MessagesAcknowledged":15,"MessagesExpired":0,"MessagesKilled":0,"Paused":true}`),
+		}}, nil).
+		Times(1)
+	statistics, err := artemis.GetQueueStatistics("DLQ")
+	assert.NoError(t, err)
+	assert.Equal(t, &QueueStatistics{MessageCount: 5, ConsumerCount: 1, DeliveringCount: 1, MessagesAdded: 20, MessagesAcknowledged: 15, Paused: true}, statistics)
+}
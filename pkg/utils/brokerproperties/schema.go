@@ -0,0 +1,255 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package brokerproperties checks broker properties against a schema of the broker configuration beans, the
+// property paths and value types that the broker accepts, so a typo is reported before the broker loads it
+package brokerproperties
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver/v4"
+)
+
+const (
+	typeAny     = "any"
+	typeString  = "string"
+	typeBoolean = "boolean"
+	typeInt     = "int"
+	typeLong    = "long"
+	typeDouble  = "double"
+	typeSize    = "size"
+	typeEnum    = "enum:"
+
+	keySurround = "\""
+	// the broker removes a property with this value
+	removeValue = "-"
+)
+
+//go:embed schema.json
+var schemaJSON []byte
+
+// node is a property of the schema, a bean with properties, a map of named beans, a typed value or any content
+type node struct {
+	Type       string           `json:"type,omitempty"`
+	Since      string           `json:"since,omitempty"`
+	Ref        string           `json:"ref,omitempty"`
+	Properties map[string]*node `json:"properties,omitempty"`
+	Map        *node            `json:"map,omitempty"`
+}
+
+// UnmarshalJSON accepts the short form of a value node, its type
+func (n *node) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &n.Type)
+	}
+	type plain node
+	return json.Unmarshal(data, (*plain)(n))
+}
+
+type schema struct {
+	Definitions map[string]*node `json:"definitions"`
+	Properties  map[string]*node `json:"properties"`
+}
+
+var (
+	loadOnce sync.Once
+	root     *node
+	loadErr  error
+
+	booleanValues = map[string]bool{"true": true, "false": true, "yes": true, "no": true, "y": true, "n": true, "on": true, "off": true, "1": true, "0": true}
+	sizeRegex     = regexp.MustCompile(`^-?[0-9]+\s*([kKmMgGtT][iI]?)?[bB]?$`)
+)
+
+func load() (*node, error) {
+	loadOnce.Do(func() {
+		s := schema{}
+		if loadErr = json.Unmarshal(schemaJSON, &s); loadErr != nil {
+			return
+		}
+		root = &node{Properties: s.Properties}
+		loadErr = resolve(root, s.Definitions)
+	})
+	return root, loadErr
+}
+
+// resolve replaces the references to definitions, a definition is shared by all the properties that reference it
+func resolve(n *node, definitions map[string]*node) error {
+	if n.Ref != "" {
+		definition, found := definitions[n.Ref]
+		if !found {
+			return fmt.Errorf("broker properties schema has no definition %v", n.Ref)
+		}
+		n.Properties, n.Map, n.Type = definition.Properties, definition.Map, definition.Type
+		n.Ref = ""
+	}
+	if n.Map != nil {
+		if err := resolve(n.Map, definitions); err != nil {
+			return err
+		}
+	}
+	for _, child := range n.Properties {
+		if err := resolve(child, definitions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check validates a broker property of a broker version, it returns the reason why the broker would reject the key or
+// the value. A key that the schema does not know and that is not close to a known key is left to the broker, the
+// schema follows the configuration beans of the broker releases and may lag behind them
+func Check(brokerVersion string, key string, value string) string {
+	current, err := load()
+	if err != nil {
+		return err.Error()
+	}
+	version, versionErr := semver.ParseTolerant(brokerVersion)
+
+	key = unescape(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+	for _, segment := range splitKey(key) {
+		switch {
+		case current.Type == typeAny:
+			return ""
+		case current.Map != nil:
+			// the segment is the name of an entry
+			current = current.Map
+		case current.Properties != nil:
+			child, found := current.Properties[segment]
+			if !found {
+				if suggestion := suggest(segment, current.Properties); suggestion != "" {
+					return fmt.Sprintf("%v has an unknown property %v, did you mean %v", key, segment, suggestion)
+				}
+				return ""
+			}
+			if child.Since != "" && versionErr == nil {
+				if since, err := semver.ParseTolerant(child.Since); err == nil && version.LT(since) {
+					return fmt.Sprintf("%v requires broker version %v or later, the broker version is %v", key, child.Since, brokerVersion)
+				}
+			}
+			current = child
+		default:
+			return fmt.Sprintf("%v has no property %v, it is a %v value", key, segment, current.Type)
+		}
+	}
+	return checkValue(key, current.Type, value)
+}
+
+func checkValue(key string, valueType string, value string) string {
+	if value == removeValue || strings.Contains(value, "${") {
+		// removals and system property references are resolved by the broker
+		return ""
+	}
+	valid := true
+	switch {
+	case valueType == typeBoolean:
+		valid = booleanValues[strings.ToLower(value)]
+	case valueType == typeInt:
+		_, err := strconv.ParseInt(value, 10, 32)
+		valid = err == nil
+	case valueType == typeLong:
+		_, err := strconv.ParseInt(value, 10, 64)
+		valid = err == nil
+	case valueType == typeDouble:
+		_, err := strconv.ParseFloat(value, 64)
+		valid = err == nil
+	case valueType == typeSize:
+		valid = sizeRegex.MatchString(value)
+	case strings.HasPrefix(valueType, typeEnum):
+		values := strings.Split(strings.TrimPrefix(valueType, typeEnum), ",")
+		for _, v := range values {
+			if strings.EqualFold(v, value) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%v value %v is not one of %v", key, value, strings.Join(values, ", "))
+	}
+	if !valid {
+		return fmt.Sprintf("%v value %v is not a valid %v", key, value, valueType)
+	}
+	return ""
+}
+
+// unescape replaces the escapes of a properties file key
+func unescape(key string) string {
+	return strings.NewReplacer(`\ `, ` `, `\:`, `:`, `\=`, `=`, `\"`, `"`).Replace(key)
+}
+
+// splitKey splits a key on the dots that are not surrounded by quotes, the quotes are removed
+func splitKey(key string) []string {
+	segments := []string{}
+	var segment strings.Builder
+	quoted := false
+	for _, c := range key {
+		switch {
+		case string(c) == keySurround:
+			quoted = !quoted
+		case c == '.' && !quoted:
+			segments = append(segments, segment.String())
+			segment.Reset()
+		default:
+			segment.WriteRune(c)
+		}
+	}
+	return append(segments, segment.String())
+}
+
+// suggest returns the closest property name to an unknown segment, a short name needs a closer match
+func suggest(segment string, properties map[string]*node) string {
+	maxDistance := 2
+	if len(segment) < 8 {
+		maxDistance = 1
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	suggestion, best := "", maxDistance+1
+	for _, name := range names {
+		if distance := levenshtein(strings.ToLower(segment), strings.ToLower(name)); distance < best {
+			suggestion, best = name, distance
+		}
+	}
+	return suggestion
+}
+
+func levenshtein(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,63 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package brokerproperties
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckValidProperties(t *testing.T) {
+	for key, value := range map[string]string{
+		"globalMaxSize":                    "512M",
+		"journalMinFiles":                  "10",
+		"criticalAnalyzer":                 "false",
+		"addressesSettings.#.maxSizeBytes": "10485760",
+		"addressesSettings.\"my.queue\".addressFullMessagePolicy":  "page",
+		"addressConfigurations.a.queueConfigs.q.routingType":       "ANYCAST",
+		"securityRoles.\"#\".admin.send":                           "true",
+		"acceptorConfigurations.tls.params.sslEnabled":             "true",
+		"jaasConfigs.\"activemq\".modules.props.params.\"reload\"": "true",
+		"HAPolicyConfiguration":                                    "PRIMARY_ONLY",
+		"storeConfiguration.jdbcDriverClassName":                   "org.postgresql.Driver",
+		"journalFileSize":                                          "${journal.size}",
+		"notKnownAtAll":                                            "x",
+		"addressesSettings.#.pageLimitBytes":                       "1G",
+	} {
+		assert.Empty(t, Check("2.53.0", key, value), key)
+	}
+}
+
+func TestCheckSuggestsNearMissKeys(t *testing.T) {
+	assert.Equal(t, "addressesSetings.#.maxSizeBytes has an unknown property addressesSetings, did you mean addressesSettings",
+		Check("2.53.0", "addressesSetings.#.maxSizeBytes", "1"))
+	assert.Contains(t, Check("2.53.0", "addressConfigurations.a.queueConfigs.q.rouingType", "ANYCAST"), "did you mean routingType")
+	assert.Contains(t, Check("2.53.0", "haPolicyConfiguration", "PRIMARY_ONLY"), "did you mean HAPolicyConfiguration")
+}
+
+func TestCheckValueTypes(t *testing.T) {
+	assert.Equal(t, "journalMinFiles value ten is not a valid int", Check("2.53.0", "journalMinFiles", "ten"))
+	assert.Contains(t, Check("2.53.0", "criticalAnalyzer", "maybe"), "not a valid boolean")
+	assert.Contains(t, Check("2.53.0", "addressesSettings.#.addressFullMessagePolicy", "PAUSE"), "is not one of PAGE, DROP, BLOCK, FAIL")
+	assert.Contains(t, Check("2.53.0", "globalMaxSize", "lots"), "not a valid size")
+	assert.Contains(t, Check("2.53.0", "journalMinFiles.count", "1"), "has no property count")
+	assert.Empty(t, Check("2.53.0", "journalMinFiles", "-"))
+}
+
+func TestCheckBrokerVersion(t *testing.T) {
+	assert.Contains(t, Check("2.27.0", "addressesSettings.#.pageLimitBytes", "1G"), "requires broker version 2.28.0 or later")
+	assert.Empty(t, Check("2.28.0", "addressesSettings.#.pageLimitBytes", "1G"))
+}
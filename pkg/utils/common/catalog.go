@@ -0,0 +1,213 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	v1beta2 "github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/version"
+)
+
+// CatalogImages are the images of a broker version from a BrokerImageCatalog, pinned to their digests
+type CatalogImages struct {
+	Version   semver.Version
+	Image     string
+	InitImage string
+}
+
+// the broker versions and mirrors of the BrokerImageCatalogs, shared by the controllers
+var imageCatalog struct {
+	sync.RWMutex
+	loaded   bool
+	images   map[string]CatalogImages
+	versions []semver.Version
+	mirrors  []v1beta2.RegistryMirror
+}
+
+// SetImageCatalog replaces the versions and mirrors of the catalog, the versions of the catalog are resolved with
+// the versions that the operator supports
+func SetImageCatalog(images []CatalogImages, mirrors []v1beta2.RegistryMirror) {
+	imageCatalog.Lock()
+	defer imageCatalog.Unlock()
+
+	imageCatalog.loaded = true
+	imageCatalog.images = make(map[string]CatalogImages, len(images))
+	imageCatalog.versions = append([]semver.Version{}, version.SupportedActiveMQArtemisSemanticVersions()...)
+	for _, entry := range images {
+		key := entry.Version.String()
+		if _, found := imageCatalog.images[key]; found {
+			continue
+		}
+		imageCatalog.images[key] = entry
+		if !version.IsSupportedActiveMQArtemisVersion(key) {
+			imageCatalog.versions = append(imageCatalog.versions, entry.Version)
+		}
+	}
+	semver.Sort(imageCatalog.versions)
+
+	// the longest source is replaced first
+	imageCatalog.mirrors = append([]v1beta2.RegistryMirror{}, mirrors...)
+	sort.SliceStable(imageCatalog.mirrors, func(i, j int) bool {
+		return len(imageCatalog.mirrors[i].Source) > len(imageCatalog.mirrors[j].Source)
+	})
+}
+
+// IsImageCatalogLoaded is false till the catalog is first set
+func IsImageCatalogLoaded() bool {
+	imageCatalog.RLock()
+	defer imageCatalog.RUnlock()
+	return imageCatalog.loaded
+}
+
+// ResetImageCatalog removes the versions and mirrors of the catalog
+func ResetImageCatalog() {
+	imageCatalog.Lock()
+	defer imageCatalog.Unlock()
+	imageCatalog.loaded = false
+	imageCatalog.images = nil
+	imageCatalog.versions = nil
+	imageCatalog.mirrors = nil
+}
+
+// SupportedBrokerVersions are the sorted versions that the operator supports and the versions of the catalog
+func SupportedBrokerVersions() []semver.Version {
+	imageCatalog.RLock()
+	defer imageCatalog.RUnlock()
+	if imageCatalog.versions == nil {
+		return version.SupportedActiveMQArtemisSemanticVersions()
+	}
+	return imageCatalog.versions
+}
+
+func IsSupportedBrokerVersion(fullVersion string) bool {
+	if version.IsSupportedActiveMQArtemisVersion(fullVersion) {
+		return true
+	}
+	_, found := catalogImagesOf(fullVersion)
+	return found
+}
+
+func catalogImagesOf(fullVersion string) (CatalogImages, bool) {
+	imageCatalog.RLock()
+	defer imageCatalog.RUnlock()
+	images, found := imageCatalog.images[fullVersion]
+	return images, found
+}
+
+// MirrorImage replaces the registry of an image with the mirror of the catalog with the longest matching source
+func MirrorImage(image string) string {
+	imageCatalog.RLock()
+	defer imageCatalog.RUnlock()
+	for _, mirror := range imageCatalog.mirrors {
+		if rest, found := strings.CutPrefix(image, mirror.Source); found && (rest == "" || strings.ContainsAny(rest[:1], "/:@")) {
+			return mirror.Mirror + rest
+		}
+	}
+	return image
+}
+
+// ImageWithDigest pins an image to a digest, an image with a digest is not changed
+func ImageWithDigest(image string, digest string) string {
+	if digest == "" || strings.Contains(image, "@") {
+		return image
+	}
+	return image + "@" + digest
+}
+
+var isImageCatalogAPI *bool
+
+// DetectImageCatalogWith looks for the BrokerImageCatalog resource and the permission to read the catalogs of the
+// cluster, a single namespace install may have neither the CRD nor a ClusterRole
+func DetectImageCatalogWith(config *rest.Config) (bool, error) {
+	if isImageCatalogAPI == nil {
+		value, ok := os.LookupEnv("OPERATOR_IMAGE_CATALOG")
+		if ok {
+			ctrl.Log.V(1).Info("Set by env-var 'OPERATOR_IMAGE_CATALOG': " + value)
+			SetImageCatalogAvailable(strings.ToLower(value) == "true")
+			return *isImageCatalogAPI, nil
+		}
+
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return false, err
+		}
+		var available bool
+		for i := 0; i < defaultRetries; i++ {
+			if available, err = detectImageCatalog(clientset.Discovery(), clientset.AuthorizationV1().SelfSubjectAccessReviews()); err == nil {
+				break
+			}
+			time.Sleep(defaultRetryInterval)
+		}
+		if err != nil {
+			return false, err
+		}
+		SetImageCatalogAvailable(available)
+	}
+	return *isImageCatalogAPI, nil
+}
+
+func detectImageCatalog(discoveryClient discovery.DiscoveryInterface, reviews authorizationv1client.SelfSubjectAccessReviewInterface) (bool, error) {
+	resource := schema.GroupVersionResource{Group: v1beta2.GroupVersion.Group, Version: v1beta2.GroupVersion.Version, Resource: "brokerimagecatalogs"}
+	if enabled, err := discovery.IsResourceEnabled(discoveryClient, resource); err != nil || !enabled {
+		return false, err
+	}
+	for _, verb := range []string{"get", "list", "watch"} {
+		review, err := reviews.Create(context.TODO(), &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:     verb,
+					Group:    resource.Group,
+					Version:  resource.Version,
+					Resource: resource.Resource,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return false, err
+		}
+		if !review.Status.Allowed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// IsImageCatalogAvailable is true unless the detection found no catalogs to read, like the render command that
+// reads the catalogs of its input
+func IsImageCatalogAvailable() bool {
+	return isImageCatalogAPI == nil || *isImageCatalogAPI
+}
+
+func SetImageCatalogAvailable(available bool) {
+	isImageCatalogAPI = &available
+}
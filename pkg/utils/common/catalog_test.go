@@ -0,0 +1,114 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import (
+	"github.com/blang/semver/v4"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/arkmq-org/activemq-artemis-operator/api/v1beta2"
+	"github.com/arkmq-org/activemq-artemis-operator/version"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Image catalog", func() {
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	AfterEach(func() {
+		ResetImageCatalog()
+	})
+
+	It("resolves the versions of the catalog with the supported versions", func() {
+		Expect(IsImageCatalogLoaded()).To(BeFalse())
+
+		SetImageCatalog([]CatalogImages{{
+			Version:   semver.MustParse("2.53.1"),
+			Image:     ImageWithDigest("quay.io/arkmq-org/arkmq-org-broker-kubernetes:artemis.2.53.1", digest),
+			InitImage: "quay.io/arkmq-org/arkmq-org-broker-init:artemis.2.53.1",
+		}}, nil)
+		Expect(IsImageCatalogLoaded()).To(BeTrue())
+		Expect(IsSupportedBrokerVersion("2.53.1")).To(BeTrue())
+		Expect(IsSupportedBrokerVersion("2.53.2")).To(BeFalse())
+
+		cr := &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: "catalog"}, Spec: v1beta2.BrokerSpec{Version: "2.53"}}
+		resolved, err := ResolveBrokerVersionFromCR(cr)
+		Expect(err).To(BeNil())
+		Expect(resolved).To(Equal("2.53.1"))
+		Expect(ResolveImage(cr, BrokerImageKey)).To(Equal("quay.io/arkmq-org/arkmq-org-broker-kubernetes:artemis.2.53.1@" + digest))
+		Expect(ResolveImage(cr, InitImageKey)).To(Equal("quay.io/arkmq-org/arkmq-org-broker-init:artemis.2.53.1"))
+
+		// a supported version keeps the images of the operator
+		cr.Spec.Version = version.LatestVersion
+		Expect(ResolveImage(cr, BrokerImageKey)).To(Equal(version.GetDefaultKubeImage()))
+	})
+
+	It("replaces the registry of the images with the longest matching mirror", func() {
+		SetImageCatalog(nil, []v1beta2.RegistryMirror{
+			{Source: "quay.io", Mirror: "registry.example.com/quay"},
+			{Source: "quay.io/arkmq-org", Mirror: "registry.example.com/arkmq"},
+		})
+
+		Expect(MirrorImage("quay.io/arkmq-org/arkmq-org-broker-init:artemis.2.53.0")).To(Equal("registry.example.com/arkmq/arkmq-org-broker-init:artemis.2.53.0"))
+		Expect(MirrorImage("quay.io/other/image@" + digest)).To(Equal("registry.example.com/quay/other/image@" + digest))
+		Expect(MirrorImage("quay.io.example.com/image")).To(Equal("quay.io.example.com/image"))
+		Expect(MirrorImage("docker.io/library/busybox")).To(Equal("docker.io/library/busybox"))
+
+		// the images of the operator are mirrored too
+		cr := &v1beta2.Broker{ObjectMeta: metav1.ObjectMeta{Name: "mirror"}}
+		Expect(ResolveImage(cr, BrokerImageKey)).To(Equal("registry.example.com/arkmq/arkmq-org-broker-kubernetes:artemis." + version.LatestVersion))
+	})
+
+	It("pins an image to a digest", func() {
+		Expect(ImageWithDigest("quay.io/a/b:1", "")).To(Equal("quay.io/a/b:1"))
+		Expect(ImageWithDigest("quay.io/a/b:1", digest)).To(Equal("quay.io/a/b:1@" + digest))
+		Expect(ImageWithDigest("quay.io/a/b@"+digest, "sha256:other")).To(Equal("quay.io/a/b@" + digest))
+	})
+
+	It("detects the catalog resource and the permission to read it", func() {
+		clientset := fake.NewSimpleClientset()
+		reviews := clientset.AuthorizationV1().SelfSubjectAccessReviews()
+
+		// no CRD
+		Expect(detectImageCatalog(clientset.Discovery(), reviews)).To(BeFalse())
+
+		clientset.Resources = []*metav1.APIResourceList{{
+			GroupVersion: v1beta2.GroupVersion.String(),
+			APIResources: []metav1.APIResource{{Name: "brokerimagecatalogs", Kind: "BrokerImageCatalog"}},
+		}}
+		// no ClusterRole, the fake reviews are not allowed
+		Expect(detectImageCatalog(clientset.Discovery(), reviews)).To(BeFalse())
+
+		clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+			review.Status.Allowed = true
+			return true, review, nil
+		})
+		Expect(detectImageCatalog(clientset.Discovery(), reviews)).To(BeTrue())
+	})
+
+	It("is available unless the detection found no catalogs", func() {
+		defer func() { isImageCatalogAPI = nil }()
+		Expect(IsImageCatalogAvailable()).To(BeTrue())
+		SetImageCatalogAvailable(false)
+		Expect(IsImageCatalogAvailable()).To(BeFalse())
+	})
+})
@@ -42,6 +42,8 @@ import (
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	policyv1 "k8s.io/api/policy/v1"
 )
@@ -78,6 +80,12 @@ const (
 	AppServiceAnnotation            = "arkmq.org/app-service"
 	ProvisionedAppsAnnotation       = "arkmq.org/provisioned-apps"
 	BlockReconcileAnnotation        = "arkmq.org/block-reconcile"
+	PlanAnnotation                  = "arkmq.org/plan"
+	MigrateToBrokerAnnotation       = "arkmq.org/migrate-to-broker"
+	MigratedToBrokerAnnotation      = "arkmq.org/migrated-to-broker"
+	MigratedFromAnnotation          = "arkmq.org/migrated-from"
+	ApproveUpgradeAnnotation        = "arkmq.org/approve-upgrade"
+	CertificatesChecksumAnnotation  = "arkmq.org/certificates-checksum"
 
 	// BrokerService and BrokerApp controller constants
 	BrokerPropsSuffix = "-bp"
@@ -93,6 +101,7 @@ var jaasConfigSyntaxMatchRegEx = JaasConfigSyntaxMatchRegExDefault
 var ClusterDomain *string
 
 var isOpenshift *bool
+var isGatewayAPI *bool
 
 var operatorCertSecretName, operatorCASecretName, prometheusCertSecretName *string
 
@@ -151,6 +160,14 @@ type ActiveMQArtemisConfigHandler interface {
 	GetCRName() string
 	IsApplicableFor(brokerNamespacedName types.NamespacedName) bool
 	Config(initContainers []corev1.Container, outputDirRoot string, yacfgProfileVersion string, yacfgProfileName string) (value []string)
+	// BrokerProperties is the config of the brokers without init container, the keys are mounted in baseDir
+	BrokerProperties(baseDir string) map[string][]byte
+	// BrokerPropertiesOnlyModules are the login modules of the broker domain that only BrokerProperties configures
+	BrokerPropertiesOnlyModules() []string
+	// SecretsToMount are the secrets the login modules read in the broker pods
+	SecretsToMount() []string
+	// TrustSecret is the ca bundle secret the broker JVM trusts for the connections of the login modules, if any
+	TrustSecret() *string
 }
 
 func compareQuantities(resList1 corev1.ResourceList, resList2 corev1.ResourceList, keys []corev1.ResourceName) bool {
@@ -308,7 +325,7 @@ func GetClusterDomain() string {
 
 func DetermineCompactVersionToUse(customResource *v1beta2.Broker) (string, error) {
 	log := ctrl.Log.WithName("util_common")
-	resolvedFullVersion, err := ResolveBrokerVersionFromCR(customResource)
+	resolvedFullVersion, err := BrokerVersionToDeploy(customResource)
 	if err != nil {
 		log.Error(err, "failed to determine broker version from cr")
 		return "", err
@@ -327,18 +344,69 @@ func ResolveBrokerVersionFromCR(cr *v1beta2.Broker) (string, error) {
 		}
 	}
 
-	result := ResolveBrokerVersion(version.SupportedActiveMQArtemisSemanticVersions(), cr.Spec.Version)
+	result := ResolveBrokerVersion(SupportedBrokerVersions(), cr.Spec.Version)
 	if result == nil {
 		return "", errors.Errorf("did not find a matching broker in the supported list for %v", cr.Spec.Version)
 	}
+
 	return result.String(), nil
 }
 
+// BrokerVersionToDeploy returns the version of the brokers, the deployed version while the update policy holds the
+// version that .Spec.Version resolves to as .Status.Upgrade.PendingVersion
+func BrokerVersionToDeploy(cr *v1beta2.Broker) (string, error) {
+	resolvedFullVersion, err := ResolveBrokerVersionFromCR(cr)
+	if err != nil {
+		return "", err
+	}
+
+	if pending := cr.Status.Upgrade.PendingVersion; pending != "" && pending == resolvedFullVersion && cr.Status.Version.BrokerVersion != "" {
+		return cr.Status.Version.BrokerVersion, nil
+	}
+	return resolvedFullVersion, nil
+}
+
+// PendingBrokerUpgrade returns the newer version that .Spec.Version resolves to when the brokers are deployed with
+// an older version that is still supported and still matches .Spec.Version
+func PendingBrokerUpgrade(cr *v1beta2.Broker) (string, bool) {
+	if isLockedDown(cr.Spec.DeploymentPlan.Image) || isLockedDown(cr.Spec.DeploymentPlan.InitImage) {
+		return "", false
+	}
+
+	resolved := ResolveBrokerVersion(SupportedBrokerVersions(), cr.Spec.Version)
+	if resolved == nil {
+		return "", false
+	}
+
+	deployed, err := semver.Parse(cr.Status.Version.BrokerVersion)
+	if err != nil || !deployed.LT(*resolved) || !IsSupportedBrokerVersion(deployed.String()) ||
+		ResolveBrokerVersion([]semver.Version{deployed}, cr.Spec.Version) == nil {
+		return "", false
+	}
+	return resolved.String(), true
+}
+
 func DetermineImageToUse(customResource *v1beta2.Broker, imageTypeKey string) string {
 
 	log := ctrl.Log.WithName("util_common")
 	found := false
 	imageName := ""
+
+	// the images of the catalog have precedence over the images of the operator
+	if fullVersion, err := BrokerVersionToDeploy(customResource); err == nil {
+		if images, inCatalog := catalogImagesOf(fullVersion); inCatalog {
+			if imageTypeKey == BrokerImageKey {
+				imageName = images.Image
+			} else if imageTypeKey == InitImageKey {
+				imageName = images.InitImage
+			}
+			if imageName != "" {
+				log.V(1).Info("DetermineImageToUse - from catalog", "version", fullVersion, "imageName", imageName)
+				return MirrorImage(imageName)
+			}
+		}
+	}
+
 	compactVersionToUse, _ := DetermineCompactVersionToUse(customResource)
 
 	genericRelatedImageEnvVarName := ImageNamePrefix + imageTypeKey + "_" + compactVersionToUse
@@ -362,7 +430,7 @@ func DetermineImageToUse(customResource *v1beta2.Broker, imageTypeKey string) st
 		log.V(1).Info("DetermineImageToUse - from default", "env", archSpecificRelatedImageEnvVarName, "imageName", imageName)
 	}
 
-	return imageName
+	return MirrorImage(imageName)
 }
 
 func SetStatusConditionWithGeneration(cr *v1beta2.Broker, condition metav1.Condition) {
@@ -403,7 +471,7 @@ func UpdateBlockedStatus(cr *v1beta2.Broker, blocked bool) {
 func updateVersionStatus(cr *v1beta2.Broker) {
 	cr.Status.Version.Image = ResolveImage(cr, BrokerImageKey)
 	cr.Status.Version.InitImage = ResolveImage(cr, InitImageKey)
-	cr.Status.Version.BrokerVersion, _ = ResolveBrokerVersionFromCR(cr)
+	cr.Status.Version.BrokerVersion, _ = BrokerVersionToDeploy(cr)
 
 	if isLockedDown(cr.Spec.DeploymentPlan.Image) || isLockedDown(cr.Spec.DeploymentPlan.InitImage) {
 		cr.Status.Upgrade.SecurityUpdates = false
@@ -475,7 +543,7 @@ func ValidateBrokerImageVersion(customResource *v1beta2.Broker) *metav1.Conditio
 				}
 			} else {
 				if customResource.Spec.Version != "" {
-					if !version.IsSupportedActiveMQArtemisVersion(customResource.Spec.Version) {
+					if !IsSupportedBrokerVersion(customResource.Spec.Version) {
 						result = &metav1.Condition{
 							Type:    v1beta2.ValidConditionType,
 							Status:  metav1.ConditionUnknown,
@@ -619,7 +687,18 @@ func getDeploymentCondition(cr *v1beta2.Broker, client rtclient.Client, valid bo
 			Message: DeployedConditionZeroSizeMessage,
 		}
 	}
-	if len(cr.Status.PodStatus.Ready) < int(deploymentSize) {
+	if cr.Spec.HA != nil {
+		// a backup is not ready till it fails over, a pair is deployed with one ready broker
+		pairs := int(deploymentSize) / 2
+		if ready := readyHAPairs(cr, client, pairs); ready < pairs {
+			return metav1.Condition{
+				Type:    v1beta2.DeployedConditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  v1beta2.DeployedConditionNotReadyReason,
+				Message: fmt.Sprintf("%d/%d primary/backup pairs ready", ready, pairs),
+			}
+		}
+	} else if len(cr.Status.PodStatus.Ready) < int(deploymentSize) {
 		crDeployedCondition := metav1.Condition{
 			Type:    v1beta2.DeployedConditionType,
 			Status:  metav1.ConditionFalse,
@@ -643,6 +722,31 @@ func getDeploymentCondition(cr *v1beta2.Broker, client rtclient.Client, valid bo
 	}
 }
 
+// readyHAPairs counts the primary/backup pairs, formed from consecutive ordinals, that have a ready broker
+func readyHAPairs(cr *v1beta2.Broker, client rtclient.Client, pairs int) int {
+	ready := 0
+	for pair := 0; pair < pairs; pair++ {
+		for _, ordinal := range []int{2 * pair, 2*pair + 1} {
+			pod := &corev1.Pod{}
+			podNamespacedName := types.NamespacedName{Namespace: cr.Namespace, Name: namer.CrToSSOrdinal(cr.Name, ordinal)}
+			if err := client.Get(context.TODO(), podNamespacedName, pod); err == nil && IsPodReady(pod) {
+				ready++
+				break
+			}
+		}
+	}
+	return ready
+}
+
+func IsPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 // take useful diagnostic info from the PodStatus for a free form Message string
 func PodStartingStatusDigestMessage(podName string, status corev1.PodStatus) string {
 	buf := &bytes.Buffer{}
@@ -690,28 +794,21 @@ func GetDeploymentSize(cr *v1beta2.Broker) int32 {
 func GetDeployedResources(instance *v1beta2.Broker, client rtclient.Client, onOpenShift bool) (map[reflect.Type][]rtclient.Object, error) {
 	log := ctrl.Log.WithName("util_common")
 	reader := read.New(client).WithNamespace(instance.Namespace).WithOwnerObject(instance)
-	var resourceMap map[reflect.Type][]rtclient.Object
-	var err error
+	lists := []rtclient.ObjectList{
+		&corev1.ServiceList{},
+		&appsv1.StatefulSetList{},
+		&netv1.IngressList{},
+		&corev1.SecretList{},
+		&corev1.ConfigMapList{},
+		&policyv1.PodDisruptionBudgetList{},
+	}
 	if onOpenShift {
-		resourceMap, err = reader.ListAll(
-			&corev1.ServiceList{},
-			&appsv1.StatefulSetList{},
-			&routev1.RouteList{},
-			&corev1.SecretList{},
-			&corev1.ConfigMapList{},
-			&policyv1.PodDisruptionBudgetList{},
-			&netv1.IngressList{},
-		)
-	} else {
-		resourceMap, err = reader.ListAll(
-			&corev1.ServiceList{},
-			&appsv1.StatefulSetList{},
-			&netv1.IngressList{},
-			&corev1.SecretList{},
-			&corev1.ConfigMapList{},
-			&policyv1.PodDisruptionBudgetList{},
-		)
+		lists = append(lists, &routev1.RouteList{})
+	}
+	if IsGatewayAPIAvailable() {
+		lists = append(lists, &gatewayv1alpha2.TLSRouteList{}, &gatewayv1beta1.HTTPRouteList{})
 	}
+	resourceMap, err := reader.ListAll(lists...)
 	if err != nil {
 		log.Error(err, "Failed to list deployed objects.")
 		return nil, err
@@ -759,6 +856,51 @@ func DetectOpenshiftWith(config *rest.Config) (bool, error) {
 	return *isOpenshift, nil
 }
 
+// DetectGatewayAPIWith looks for the Gateway API TLSRoute and HTTPRoute resources that the gateway expose mode creates
+func DetectGatewayAPIWith(config *rest.Config) (bool, error) {
+	if isGatewayAPI == nil {
+		value, ok := os.LookupEnv("OPERATOR_GATEWAY_API")
+		if ok {
+			ctrl.Log.V(1).Info("Set by env-var 'OPERATOR_GATEWAY_API': " + value)
+			SetGatewayAPIAvailable(strings.ToLower(value) == "true")
+			return *isGatewayAPI, nil
+		}
+
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			return false, err
+		}
+
+		present := true
+		for _, resource := range []schema.GroupVersionResource{
+			{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Resource: "tlsroutes"},
+			{Group: "gateway.networking.k8s.io", Version: "v1beta1", Resource: "httproutes"},
+		} {
+			var enabled bool
+			for i := 0; i < defaultRetries; i++ {
+				if enabled, err = discovery.IsResourceEnabled(discoveryClient, resource); err == nil {
+					break
+				}
+				time.Sleep(defaultRetryInterval)
+			}
+			if err != nil {
+				return false, err
+			}
+			present = present && enabled
+		}
+		SetGatewayAPIAvailable(present)
+	}
+	return *isGatewayAPI, nil
+}
+
+func IsGatewayAPIAvailable() bool {
+	return isGatewayAPI != nil && *isGatewayAPI
+}
+
+func SetGatewayAPIAvailable(available bool) {
+	isGatewayAPI = &available
+}
+
 func GetOperandCertSecretName(cr *v1beta2.Broker, client rtclient.Client) string {
 
 	secret, _ := ResolveSecret(cr.Name, cr.Namespace, DefaultOperandCertSecretName, client)
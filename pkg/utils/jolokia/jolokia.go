@@ -2,14 +2,12 @@ package jolokia
 
 import (
 	"bytes"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"time"
 
-	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
 	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -24,6 +22,16 @@ type ResponseData struct {
 	Value     string
 	ErrorType string
 	Error     string
+	// the undecoded json of the value, see DecodeValue
+	RawValue json.RawMessage
+}
+
+// DecodeValue unmarshals the json value of the response into v
+func (r *ResponseData) DecodeValue(v interface{}) error {
+	if len(r.RawValue) == 0 {
+		return fmt.Errorf("no value in response with status %v", r.Status)
+	}
+	return json.Unmarshal(r.RawValue, v)
 }
 
 type ReadRequest struct {
@@ -32,6 +40,23 @@ type ReadRequest struct {
 	Type      string `json:"type"`
 }
 
+// Request is an entry of a bulk request, Attribute can be a name or a list of names
+type Request struct {
+	Type      string        `json:"type"`
+	MBean     string        `json:"mbean,omitempty"`
+	Attribute interface{}   `json:"attribute,omitempty"`
+	Path      string        `json:"path,omitempty"`
+	Operation string        `json:"operation,omitempty"`
+	Arguments []interface{} `json:"arguments,omitempty"`
+}
+
+type rawResponseData struct {
+	Status    int             `json:"status"`
+	Value     json.RawMessage `json:"value"`
+	ErrorType string          `json:"error_type"`
+	Error     string          `json:"error"`
+}
+
 type JolokiaError struct {
 	HttpCode int
 	Message  string
@@ -47,6 +72,9 @@ type IJolokia interface {
 	ExecWithClient(httpClient *http.Client, path, postJsonString string) (*ResponseData, error)
 	GetProtocol() string
 	GetClientWithTimeout(timeout time.Duration) *http.Client
+	Bulk(requests []Request) ([]*ResponseData, error)
+	Search(pattern string) ([]string, error)
+	List(path string) (*ResponseData, error)
 }
 
 type Jolokia struct {
@@ -107,82 +135,65 @@ func (j *Jolokia) getClient() *http.Client {
 }
 
 func (j *Jolokia) GetClientWithTimeout(timeout time.Duration) *http.Client {
-	httpClient := http.Client{
-		Transport: http.DefaultTransport,
+	return &http.Client{
+		Transport: j.getTransport(),
 		Timeout:   timeout,
 	}
-
-	if j.protocol == "https" {
-		httpClientTransport := httpClient.Transport.(*http.Transport)
-		httpClientTransport.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: true,
-			ServerName:         j.ip,
-		}
-		if common.OperatorHasCertAndTrustBundle(j.client) {
-			httpClientTransport.TLSClientConfig.InsecureSkipVerify = false
-			httpClientTransport.TLSClientConfig.GetClientCertificate =
-				func(cri *tls.CertificateRequestInfo) (*tls.Certificate, error) {
-					return common.GetOperatorClientCertificate(j.client, cri)
-				}
-		}
-		if rootCas, err := common.GetRootCAs(j.client); err == nil {
-			httpClientTransport.TLSClientConfig.RootCAs = rootCas
-		}
-	}
-
-	return &httpClient
 }
 
 func (j *Jolokia) Read(_path string) (*ResponseData, error) {
+	return j.get("read", _path)
+}
 
-	url := j.protocol + "://" + j.user + ":" + j.password + "@" + j.jolokiaURL + "/read/" + _path
-
-	jolokiaClient := j.getClient()
-
-	var respError error = nil
-	var jdata *ResponseData = nil
-
-	for {
-		req, err := http.NewRequest(http.MethodGet, url, nil)
-		if err != nil {
-			break
-		}
-		req.Header.Set("User-Agent", "activemq-artemis-management")
-		res, err := jolokiaClient.Do(req)
+// Search returns the names of the MBeans that match the pattern
+func (j *Jolokia) Search(_pattern string) ([]string, error) {
+	data, err := j.get("search", _pattern)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	if err = data.DecodeValue(&names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
 
-		if err != nil {
-			respError = err
-			break
-		}
-		defer res.Body.Close()
+// List returns the meta data of the MBeans below the path, the attributes and operations
+func (j *Jolokia) List(_path string) (*ResponseData, error) {
+	return j.get("list", _path)
+}
 
-		if !isResponseSuccessful(res.StatusCode) {
-			return nil, &JolokiaError{
-				HttpCode: res.StatusCode,
-				Message:  "error: " + res.Status,
-			}
-		}
+func (j *Jolokia) baseURL() string {
+	return j.protocol + "://" + j.user + ":" + j.password + "@" + j.jolokiaURL
+}
 
-		//decoding
-		result, _, err := decodeResponseData(res)
-		if err != nil {
-			respError = err
-			return result, err
-		}
+func (j *Jolokia) get(_operation string, _path string) (*ResponseData, error) {
 
-		jdata = result
+	url := j.baseURL() + "/" + _operation + "/" + _path
 
-		//before decoding the body, we need to check the http code
-		err = CheckResponse(res, result)
+	res, err := j.do(j.getClient(), func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, url, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
 
-		if err != nil {
-			respError = err
+	if !isResponseSuccessful(res.StatusCode) {
+		return nil, &JolokiaError{
+			HttpCode: res.StatusCode,
+			Message:  "error: " + res.Status,
 		}
+	}
 
-		break
+	//decoding
+	result, err := decodeResponseData(res)
+	if err != nil {
+		return result, err
 	}
 
-	return jdata, respError
+	//before decoding the body, we need to check the http code
+	return result, CheckResponse(res, result)
 }
 
 func (j *Jolokia) Exec(_path string, _postJsonString string) (*ResponseData, error) {
@@ -191,45 +202,70 @@ func (j *Jolokia) Exec(_path string, _postJsonString string) (*ResponseData, err
 
 func (j *Jolokia) ExecWithClient(jolokiaClient *http.Client, _path string, _postJsonString string) (*ResponseData, error) {
 
-	url := j.protocol + "://" + j.user + ":" + j.password + "@" + j.jolokiaURL + "/exec/" + _path
+	url := j.baseURL() + "/exec/" + _path
 
-	var jdata *ResponseData
-	var execErr error = nil
-	for {
+	res, err := j.do(jolokiaClient, func() (*http.Request, error) {
 		req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer([]byte(_postJsonString)))
-		if err != nil {
-			execErr = err
-			break
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
 		}
+		return req, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	//decoding
+	result, err := decodeResponseData(res)
+	if err != nil {
+		return result, err
+	}
 
-		req.Header.Set("User-Agent", "activemq-artemis-management")
-		req.Header.Set("Content-Type", "application/json")
-		res, err := jolokiaClient.Do(req)
+	return result, CheckResponse(res, result)
+}
 
-		if err != nil {
-			execErr = err
-			break
-		}
+// Bulk sends the requests in a single round trip, the responses are in the order of the requests
+// and each one must be checked for its own status
+func (j *Jolokia) Bulk(requests []Request) ([]*ResponseData, error) {
 
-		defer res.Body.Close()
+	body, err := json.Marshal(requests)
+	if err != nil {
+		return nil, err
+	}
 
-		//decoding
-		result, _, err := decodeResponseData(res)
-		if err != nil {
-			return result, err
+	res, err := j.do(j.getClient(), func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, j.baseURL()+"/", bytes.NewBuffer(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
 		}
+		return req, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
 
-		jdata = result
-
-		err = CheckResponse(res, result)
-		if err != nil {
-			execErr = err
+	if !isResponseSuccessful(res.StatusCode) {
+		return nil, &JolokiaError{
+			HttpCode: res.StatusCode,
+			Message:  "error: " + res.Status,
 		}
+	}
 
-		break
+	rawData := []rawResponseData{}
+	if err := json.NewDecoder(res.Body).Decode(&rawData); err != nil {
+		return nil, err
+	}
+	if len(rawData) != len(requests) {
+		return nil, fmt.Errorf("bulk request of %d entries returned %d responses", len(requests), len(rawData))
 	}
 
-	return jdata, execErr
+	results := make([]*ResponseData, len(rawData))
+	for i := range rawData {
+		results[i] = newResponseData(&rawData[i])
+	}
+	return results, nil
 }
 
 func CheckResponse(resp *http.Response, jdata *ResponseData) error {
@@ -256,35 +292,29 @@ func isResponseSuccessful(httpCode int) bool {
 	return httpCode >= 200 && httpCode <= 299
 }
 
-func decodeResponseData(resp *http.Response) (*ResponseData, map[string]interface{}, error) {
-	result := &ResponseData{}
-	rawData := make(map[string]interface{})
-	if err := json.NewDecoder(resp.Body).Decode(&rawData); err != nil {
-		return nil, rawData, err
+func decodeResponseData(resp *http.Response) (*ResponseData, error) {
+	rawData := &rawResponseData{}
+	if err := json.NewDecoder(resp.Body).Decode(rawData); err != nil {
+		return nil, err
 	}
+	return newResponseData(rawData), nil
+}
 
-	//fill in response data
-	if v, ok := rawData["error"]; ok {
-		if v != nil {
-			result.Error = fmt.Sprintf("%v", v)
-		}
+func newResponseData(rawData *rawResponseData) *ResponseData {
+	result := &ResponseData{
+		Status:    rawData.Status,
+		ErrorType: rawData.ErrorType,
+		Error:     rawData.Error,
 	}
-	if v, ok := rawData["error_type"]; ok {
-		if v != nil {
-			result.ErrorType = fmt.Sprintf("%v", v)
-		}
-	}
-	if v, ok := rawData["status"]; ok {
-		if v != nil {
-			result.Status = int(v.(float64))
-		}
-	}
-	if v, ok := rawData["value"]; ok {
-		if v != nil {
+
+	//fill in the value, keep the raw json for typed decoding
+	if len(rawData.Value) > 0 && string(rawData.Value) != "null" {
+		result.RawValue = rawData.Value
+		var v interface{}
+		if err := json.Unmarshal(rawData.Value, &v); err == nil {
 			result.Value = fmt.Sprintf("%v", v)
 		}
 	}
 
-	return result, rawData, nil
-
+	return result
 }
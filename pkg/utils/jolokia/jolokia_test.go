@@ -0,0 +1,105 @@
+package jolokia
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestJolokia(t *testing.T, handler http.HandlerFunc) *Jolokia {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	host, port, _ := strings.Cut(strings.TrimPrefix(server.URL, "http://"), ":")
+	return GetJolokia(nil, host, port, "/console/jolokia", "admin", "secret", "http")
+}
+
+func TestReadKeepsRawValue(t *testing.T) {
+	j := newTestJolokia(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/console/jolokia/read/broker/Counts", r.URL.Path)
+		io.WriteString(w, `{"status":200,"value":{"TotalMessageCount":12345678901,"Paused":false}}`)
+	})
+
+	data, err := j.Read("broker/Counts")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, data.Status)
+	assert.Contains(t, data.Value, "TotalMessageCount")
+
+	value := struct {
+		TotalMessageCount int64
+		Paused            bool
+	}{}
+	assert.NoError(t, data.DecodeValue(&value))
+	assert.Equal(t, int64(12345678901), value.TotalMessageCount)
+}
+
+func TestReadErrorStatus(t *testing.T) {
+	j := newTestJolokia(t, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"status":404,"error_type":"javax.management.InstanceNotFoundException","error":"not found"}`)
+	})
+
+	data, err := j.Read("broker/Missing")
+	assert.Error(t, err)
+	assert.Equal(t, 404, data.Status)
+	assert.Equal(t, "not found", data.Error)
+	assert.Empty(t, data.Value)
+	assert.Error(t, data.DecodeValue(&struct{}{}))
+}
+
+func TestBulk(t *testing.T) {
+	requests := []Request{}
+	j := newTestJolokia(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/console/jolokia/", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&requests))
+		io.WriteString(w, `[{"status":200,"value":{"Backup":false,"ReplicaSync":true}},{"status":404,"error":"no such attribute"}]`)
+	})
+
+	responses, err := j.Bulk([]Request{
+		{Type: "read", MBean: "broker", Attribute: []string{"Backup", "ReplicaSync"}},
+		{Type: "read", MBean: "broker", Attribute: "Missing"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+	assert.Equal(t, []interface{}{"Backup", "ReplicaSync"}, requests[0].Attribute)
+	assert.Len(t, responses, 2)
+
+	state := struct {
+		Backup      bool
+		ReplicaSync bool
+	}{}
+	assert.NoError(t, responses[0].DecodeValue(&state))
+	assert.True(t, state.ReplicaSync)
+	assert.Equal(t, 404, responses[1].Status)
+	assert.Equal(t, "no such attribute", responses[1].Error)
+
+	_, err = j.Bulk([]Request{{Type: "read", MBean: "broker"}})
+	assert.Error(t, err)
+}
+
+func TestSearchAndList(t *testing.T) {
+	j := newTestJolokia(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/console/jolokia/search/"):
+			io.WriteString(w, `{"status":200,"value":["org.apache.activemq.artemis:broker=\"amq\",component=addresses,address=\"a\""]}`)
+		case strings.HasPrefix(r.URL.Path, "/console/jolokia/list/"):
+			io.WriteString(w, `{"status":200,"value":{"op":{"pause":{"args":[],"ret":"void"}}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	names, err := j.Search("org.apache.activemq.artemis:component=addresses,*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`org.apache.activemq.artemis:broker="amq",component=addresses,address="a"`}, names)
+
+	data, err := j.List("org.apache.activemq.artemis")
+	assert.NoError(t, err)
+	operations := map[string]map[string]interface{}{}
+	assert.NoError(t, data.DecodeValue(&operations))
+	assert.Contains(t, operations["op"], "pause")
+}
@@ -5,9 +5,9 @@
 package jolokia
 
 import (
-	"net/http"
+	http "net/http"
 	reflect "reflect"
-	"time"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -70,19 +70,25 @@ func (m *MockIJolokia) EXPECT() *MockIJolokiaMockRecorder {
 	return m.recorder
 }
 
-// Exec mocks base method.
-func (m *MockIJolokia) Exec(path, postJsonString string) (*ResponseData, error) {
+// Bulk mocks base method.
+func (m *MockIJolokia) Bulk(requests []Request) ([]*ResponseData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Exec", path, postJsonString)
-	ret0, _ := ret[0].(*ResponseData)
+	ret := m.ctrl.Call(m, "Bulk", requests)
+	ret0, _ := ret[0].([]*ResponseData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// Bulk indicates an expected call of Bulk.
+func (mr *MockIJolokiaMockRecorder) Bulk(requests interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Bulk", reflect.TypeOf((*MockIJolokia)(nil).Bulk), requests)
+}
+
 // Exec mocks base method.
-func (m *MockIJolokia) ExecWithClient(httpClient *http.Client, path, postJsonString string) (*ResponseData, error) {
+func (m *MockIJolokia) Exec(path, postJsonString string) (*ResponseData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ExecWithClient", httpClient, path, postJsonString)
+	ret := m.ctrl.Call(m, "Exec", path, postJsonString)
 	ret0, _ := ret[0].(*ResponseData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
@@ -94,28 +100,78 @@ func (mr *MockIJolokiaMockRecorder) Exec(path, postJsonString interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exec", reflect.TypeOf((*MockIJolokia)(nil).Exec), path, postJsonString)
 }
 
-// Read mocks base method.
-func (m *MockIJolokia) Read(path string) (*ResponseData, error) {
+// ExecWithClient mocks base method.
+func (m *MockIJolokia) ExecWithClient(httpClient *http.Client, path, postJsonString string) (*ResponseData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Read", path)
+	ret := m.ctrl.Call(m, "ExecWithClient", httpClient, path, postJsonString)
 	ret0, _ := ret[0].(*ResponseData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (m *MockIJolokia) GetProtocol() string {
-	return "http"
+// ExecWithClient indicates an expected call of ExecWithClient.
+func (mr *MockIJolokiaMockRecorder) ExecWithClient(httpClient, path, postJsonString interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecWithClient", reflect.TypeOf((*MockIJolokia)(nil).ExecWithClient), httpClient, path, postJsonString)
 }
 
+// GetClientWithTimeout returns no client so that callers need not record an expectation for it.
 func (m *MockIJolokia) GetClientWithTimeout(timeout time.Duration) *http.Client {
 	return nil
 }
 
+// GetProtocol returns http so that callers need not record an expectation for it.
+func (m *MockIJolokia) GetProtocol() string {
+	return "http"
+}
+
+// List mocks base method.
+func (m *MockIJolokia) List(path string) (*ResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", path)
+	ret0, _ := ret[0].(*ResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockIJolokiaMockRecorder) List(path interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockIJolokia)(nil).List), path)
+}
+
+// Read mocks base method.
+func (m *MockIJolokia) Read(path string) (*ResponseData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", path)
+	ret0, _ := ret[0].(*ResponseData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Read indicates an expected call of Read.
 func (mr *MockIJolokiaMockRecorder) Read(path interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockIJolokia)(nil).Read), path)
 }
 
+// Search mocks base method.
+func (m *MockIJolokia) Search(pattern string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", pattern)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockIJolokiaMockRecorder) Search(pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockIJolokia)(nil).Search), pattern)
+}
+
+// SetClient is a no-op kept for callers of the previous mock.
+//
+// Deprecated: the mock does not use an http client, record expectations instead.
 func (m *MockIJolokia) SetClient(c *http.Client) {
 }
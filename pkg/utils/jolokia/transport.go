@@ -0,0 +1,360 @@
+package jolokia
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+	ctrl "sigs.k8s.io/controller-runtime"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultRetryInitialBackoff       = 250 * time.Millisecond
+	DefaultRetryMaxBackoff           = 2 * time.Second
+	DefaultRetryMaxRetries           = 2
+	DefaultCircuitBreakerThreshold   = 3
+	DefaultCircuitBreakerOpenTimeout = 30 * time.Second
+
+	// the operator cert and ca secrets are read again after this interval to pick up a renewed cert
+	certVersionRefreshInterval = time.Minute
+)
+
+type RetryPolicy struct {
+	// the delay before a failed endpoint is contacted again, doubled on each consecutive failure up to MaxBackoff,
+	// 0 leaves the retries to the resync period
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// the retries of a request that fails without a response, with the same backoff, before the failure is
+	// returned and the reconcile is requeued
+	MaxRetries int
+}
+
+type CircuitBreakerPolicy struct {
+	// the number of consecutive failed requests that marks an endpoint unreachable
+	Threshold int
+	// the time requests to an unreachable endpoint fail without a round trip, then a single request is tried
+	OpenTimeout time.Duration
+}
+
+var retryPolicy = RetryPolicy{
+	InitialBackoff: DefaultRetryInitialBackoff,
+	MaxBackoff:     DefaultRetryMaxBackoff,
+	MaxRetries:     DefaultRetryMaxRetries,
+}
+
+var circuitBreakerPolicy = CircuitBreakerPolicy{
+	Threshold:   DefaultCircuitBreakerThreshold,
+	OpenTimeout: DefaultCircuitBreakerOpenTimeout,
+}
+
+// LoadPoliciesFromEnv reads the retry and circuit breaker policies from the environment of the operator
+func LoadPoliciesFromEnv(lookupEnv func(string) (string, bool)) {
+	if value, defined := lookupEnv("JOLOKIA_RETRY_INITIAL_BACKOFF"); defined {
+		if backoff, err := time.ParseDuration(value); err == nil && backoff >= 0 {
+			retryPolicy.InitialBackoff = backoff
+		}
+	}
+	if value, defined := lookupEnv("JOLOKIA_RETRY_MAX_BACKOFF"); defined {
+		if backoff, err := time.ParseDuration(value); err == nil && backoff > 0 {
+			retryPolicy.MaxBackoff = backoff
+		}
+	}
+	if value, defined := lookupEnv("JOLOKIA_CIRCUIT_BREAKER_THRESHOLD"); defined {
+		if threshold, err := strconv.Atoi(value); err == nil {
+			circuitBreakerPolicy.Threshold = threshold
+		}
+	}
+	if value, defined := lookupEnv("JOLOKIA_CIRCUIT_BREAKER_OPEN_TIMEOUT"); defined {
+		if timeout, err := time.ParseDuration(value); err == nil && timeout > 0 {
+			circuitBreakerPolicy.OpenTimeout = timeout
+		}
+	}
+}
+
+func SetRetryPolicy(policy RetryPolicy) {
+	retryPolicy = policy
+}
+
+// SetCircuitBreakerPolicy replaces the policy, a threshold less than 1 disables the circuit breaker
+func SetCircuitBreakerPolicy(policy CircuitBreakerPolicy) {
+	circuitBreakerPolicy = policy
+}
+
+// brokerDomain is the key of the endpoints of a broker, they are the ordinal names in the domain of its
+// headless service, see common.OrdinalFQDNS
+func brokerDomain(host string) string {
+	if net.ParseIP(host) == nil {
+		if _, domain, found := strings.Cut(host, "."); found {
+			return domain
+		}
+	}
+	return host
+}
+
+// ForgetBroker closes the cached transports and drops the circuit breakers of the endpoints of a deleted broker
+func ForgetBroker(name string, namespace string) {
+	domain := strings.TrimPrefix(common.ClusterDNSWildCard(name, namespace), "*.")
+
+	transports.Lock()
+	for _, cached := range transports.entries[domain] {
+		cached.transport.CloseIdleConnections()
+	}
+	delete(transports.entries, domain)
+	transports.Unlock()
+
+	breakers.Lock()
+	delete(breakers.entries, domain)
+	breakers.Unlock()
+}
+
+// transports are cached per broker and endpoint so connections and tls sessions are reused across
+// reconciles, a transport is replaced when the operator cert or ca secret changes
+type cachedTransport struct {
+	transport   *http.Transport
+	certVersion string
+}
+
+var transports = struct {
+	sync.Mutex
+	entries map[string]map[string]*cachedTransport
+}{entries: map[string]map[string]*cachedTransport{}}
+
+func (j *Jolokia) endpoint() string {
+	return j.protocol + "://" + j.ip + ":" + j.port
+}
+
+func (j *Jolokia) getTransport() *http.Transport {
+	certVersion := operatorCertVersion(j.client, j.protocol, time.Now())
+
+	transports.Lock()
+	defer transports.Unlock()
+
+	domain, key := brokerDomain(j.ip), j.endpoint()
+	endpoints, found := transports.entries[domain]
+	if !found {
+		endpoints = map[string]*cachedTransport{}
+		transports.entries[domain] = endpoints
+	}
+	if cached, found := endpoints[key]; found {
+		if cached.certVersion == certVersion {
+			return cached.transport
+		}
+		cached.transport.CloseIdleConnections()
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if j.protocol == "https" {
+		transport.TLSClientConfig = j.newTLSConfig()
+	}
+	endpoints[key] = &cachedTransport{transport: transport, certVersion: certVersion}
+	return transport
+}
+
+func (j *Jolokia) newTLSConfig() *tls.Config {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         j.ip,
+		ClientSessionCache: tls.NewLRUClientSessionCache(0),
+	}
+	if common.OperatorHasCertAndTrustBundle(j.client) {
+		tlsConfig.InsecureSkipVerify = false
+		if cert, err := common.GetOperatorClientCertificate(j.client, nil); err == nil {
+			tlsConfig.GetClientCertificate = func(cri *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				return cert, nil
+			}
+		} else {
+			ctrl.Log.WithName("jolokia").V(1).Info("unable to load the operator client certificate", "endpoint", j.endpoint(), "error", err)
+		}
+	}
+	if rootCas, err := common.GetRootCAs(j.client); err == nil {
+		tlsConfig.RootCAs = rootCas
+	}
+	return tlsConfig
+}
+
+// the version of the operator cert and ca secrets is shared by all the endpoints
+var certVersion = struct {
+	sync.Mutex
+	value     string
+	checkedAt time.Time
+}{}
+
+// operatorCertVersion identifies the content of the operator cert and ca secrets, it is read
+// again once the refresh interval has passed
+func operatorCertVersion(client rtclient.Client, protocol string, now time.Time) string {
+	if client == nil || protocol != "https" {
+		return ""
+	}
+
+	certVersion.Lock()
+	defer certVersion.Unlock()
+
+	if !certVersion.checkedAt.IsZero() && now.Sub(certVersion.checkedAt) < certVersionRefreshInterval {
+		return certVersion.value
+	}
+
+	version := ""
+	if secret, err := common.GetOperatorClientCertSecret(client); err == nil {
+		version = secret.ResourceVersion
+	}
+	if secret, err := common.GetOperatorCASecret(client); err == nil {
+		version += "/" + secret.ResourceVersion
+	}
+	certVersion.value, certVersion.checkedAt = version, now
+	return version
+}
+
+// CircuitOpenError is returned without a round trip while an endpoint is unreachable
+type CircuitOpenError struct {
+	Host    string
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("jolokia endpoint %s is unreachable, next attempt after %s", e.Host, e.RetryAt.Format(time.RFC3339))
+}
+
+type circuitBreaker struct {
+	failures  int
+	openUntil time.Time
+}
+
+// the breakers of the endpoints by broker
+var breakers = struct {
+	sync.Mutex
+	entries map[string]map[string]*circuitBreaker
+}{entries: map[string]map[string]*circuitBreaker{}}
+
+func findBreaker(host string) *circuitBreaker {
+	return breakers.entries[brokerDomain(host)][host]
+}
+
+func (b *circuitBreaker) isOpen() bool {
+	return circuitBreakerPolicy.Threshold > 0 && b.failures >= circuitBreakerPolicy.Threshold
+}
+
+// UnreachableUntil reports whether the circuit breaker of the host is open and when the next attempt is allowed
+func UnreachableUntil(host string) (time.Time, bool) {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	if breaker := findBreaker(host); breaker != nil && breaker.isOpen() && time.Now().Before(breaker.openUntil) {
+		return breaker.openUntil, true
+	}
+	return time.Time{}, false
+}
+
+// RetryAfter reports whether the last request to the host failed and when it should be contacted again,
+// the backoff grows with the consecutive failures till the circuit breaker opens
+func RetryAfter(host string) (time.Duration, bool) {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	breaker := findBreaker(host)
+	if breaker == nil {
+		return 0, false
+	}
+	now := time.Now()
+	if breaker.isOpen() && now.Before(breaker.openUntil) {
+		return breaker.openUntil.Sub(now), true
+	}
+	if retryPolicy.InitialBackoff <= 0 {
+		return 0, false
+	}
+	return retryBackoff(breaker.failures), true
+}
+
+// retryBackoff is the initial backoff doubled for each failure after the first, up to the max backoff
+func retryBackoff(failures int) time.Duration {
+	backoff := retryPolicy.InitialBackoff
+	for i := 1; i < failures && backoff < retryPolicy.MaxBackoff; i++ {
+		backoff = min(backoff*2, retryPolicy.MaxBackoff)
+	}
+	return backoff
+}
+
+// allowRequest fails fast while the circuit of the host is open, once the open timeout expires the circuit is
+// half open, a single request is let through and the others keep failing till its result is recorded
+func allowRequest(host string, now time.Time) error {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	breaker := findBreaker(host)
+	if breaker == nil || !breaker.isOpen() {
+		return nil
+	}
+	if now.Before(breaker.openUntil) {
+		return &CircuitOpenError{Host: host, RetryAt: breaker.openUntil}
+	}
+	// the probe holds the circuit open, when its result is never recorded another probe follows the timeout
+	breaker.openUntil = now.Add(circuitBreakerPolicy.OpenTimeout)
+	return nil
+}
+
+func recordResult(host string, err error, now time.Time) {
+	breakers.Lock()
+	defer breakers.Unlock()
+
+	domain := brokerDomain(host)
+	if err == nil {
+		if endpoints, found := breakers.entries[domain]; found {
+			delete(endpoints, host)
+			if len(endpoints) == 0 {
+				delete(breakers.entries, domain)
+			}
+		}
+		return
+	}
+
+	endpoints, found := breakers.entries[domain]
+	if !found {
+		endpoints = map[string]*circuitBreaker{}
+		breakers.entries[domain] = endpoints
+	}
+	breaker, found := endpoints[host]
+	if !found {
+		breaker = &circuitBreaker{}
+		endpoints[host] = breaker
+	}
+	breaker.failures++
+	// a failed probe of a half open circuit opens it again
+	if breaker.isOpen() {
+		breaker.openUntil = now.Add(circuitBreakerPolicy.OpenTimeout)
+	}
+}
+
+// do sends a request through the circuit breaker of the endpoint, a request that fails without a response is
+// retried after the backoff of the retry policy up to its max retries, then the reconcile is requeued after the
+// backoff reported by RetryAfter. The retries of a request count as a single failure of the endpoint
+func (j *Jolokia) do(httpClient *http.Client, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	if err := allowRequest(j.ip, time.Now()); err != nil {
+		return nil, err
+	}
+
+	var res *http.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		var req *http.Request
+		if req, err = newRequest(); err != nil {
+			return nil, err
+		}
+		req.Header.Set("User-Agent", "activemq-artemis-management")
+
+		res, err = httpClient.Do(req)
+		if err == nil || attempt >= retryPolicy.MaxRetries || retryPolicy.InitialBackoff <= 0 {
+			break
+		}
+		backoff := retryBackoff(attempt + 1)
+		ctrl.Log.WithName("jolokia").V(2).Info("retrying request", "endpoint", j.endpoint(), "backoff", backoff, "error", err)
+		time.Sleep(backoff)
+	}
+	recordResult(j.ip, err, time.Now())
+	return res, err
+}
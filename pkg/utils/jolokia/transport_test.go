@@ -0,0 +1,244 @@
+package jolokia
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/arkmq-org/activemq-artemis-operator/pkg/utils/common"
+)
+
+func withPolicies(t *testing.T, retry RetryPolicy, breaker CircuitBreakerPolicy) {
+	previousRetry, previousBreaker := retryPolicy, circuitBreakerPolicy
+	SetRetryPolicy(retry)
+	SetCircuitBreakerPolicy(breaker)
+	t.Cleanup(func() {
+		SetRetryPolicy(previousRetry)
+		SetCircuitBreakerPolicy(previousBreaker)
+		breakers.Lock()
+		breakers.entries = map[string]map[string]*circuitBreaker{}
+		breakers.Unlock()
+	})
+}
+
+// an endpoint that refuses connections
+func closedEndpoint(t *testing.T) *Jolokia {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+	return GetJolokia(nil, "127.0.0.1", port, "/console/jolokia", "admin", "secret", "http")
+}
+
+func TestTransportCachedPerEndpoint(t *testing.T) {
+	j := newTestJolokia(t, func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"status":200,"value":"ok"}`)
+	})
+
+	transport := j.GetClientWithTimeout(time.Second).Transport
+	assert.Same(t, transport, j.getClient().Transport)
+
+	other := GetJolokia(nil, j.ip, j.port, "/other", "admin", "secret", "http")
+	assert.Same(t, transport, other.getClient().Transport)
+
+	_, err := j.Read("broker/Status")
+	assert.NoError(t, err)
+
+	another := newTestJolokia(t, func(w http.ResponseWriter, r *http.Request) {})
+	assert.NotSame(t, transport, another.getClient().Transport)
+}
+
+func TestForgetBroker(t *testing.T) {
+	withPolicies(t, RetryPolicy{}, CircuitBreakerPolicy{Threshold: 1, OpenTimeout: time.Minute})
+
+	deleted := GetJolokia(nil, common.OrdinalFQDNS("deleted", "test", 0), "8161", "/console/jolokia", "", "", "http")
+	kept := GetJolokia(nil, common.OrdinalFQDNS("kept", "test", 0), "8161", "/console/jolokia", "", "", "http")
+	transport := kept.getClient().Transport
+	deleted.getClient()
+	recordResult(deleted.ip, errors.New("down"), time.Now())
+	recordResult(kept.ip, errors.New("down"), time.Now())
+
+	ForgetBroker("deleted", "test")
+
+	transports.Lock()
+	assert.NotContains(t, transports.entries, brokerDomain(deleted.ip))
+	transports.Unlock()
+	_, open := UnreachableUntil(deleted.ip)
+	assert.False(t, open)
+
+	assert.Same(t, transport, kept.getClient().Transport)
+	_, open = UnreachableUntil(kept.ip)
+	assert.True(t, open)
+}
+
+func TestRetryAfter(t *testing.T) {
+	withPolicies(t, RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second}, CircuitBreakerPolicy{})
+
+	j := closedEndpoint(t)
+	_, found := RetryAfter(j.ip)
+	assert.False(t, found)
+
+	// a failed request returns without waiting, the backoff grows with each failure
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		_, err := j.Read("broker/Status")
+		assert.Error(t, err)
+		backoff, found := RetryAfter(j.ip)
+		assert.True(t, found)
+		assert.Equal(t, expected, backoff)
+	}
+
+	recordResult(j.ip, nil, time.Now())
+	_, found = RetryAfter(j.ip)
+	assert.False(t, found)
+
+	// no backoff leaves the retries to the resync period
+	SetRetryPolicy(RetryPolicy{})
+	recordResult(j.ip, errors.New("down"), time.Now())
+	_, found = RetryAfter(j.ip)
+	assert.False(t, found)
+}
+
+func TestRetryInRequest(t *testing.T) {
+	withPolicies(t, RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, MaxRetries: 2}, CircuitBreakerPolicy{})
+
+	// the connection of the first attempt is dropped without a response
+	attempts := 0
+	j := newTestJolokia(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			assert.NoError(t, err)
+			conn.Close()
+			return
+		}
+		io.WriteString(w, `{"status":200,"value":"ok"}`)
+	})
+	_, err := j.Read("broker/Status")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	_, found := RetryAfter(j.ip)
+	assert.False(t, found)
+
+	// the retries are bounded and count as a single failure
+	closed := closedEndpoint(t)
+	_, err = closed.Read("broker/Status")
+	assert.Error(t, err)
+	breakers.Lock()
+	assert.Equal(t, 1, findBreaker(closed.ip).failures)
+	breakers.Unlock()
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	withPolicies(t, RetryPolicy{}, CircuitBreakerPolicy{Threshold: 2, OpenTimeout: time.Minute})
+
+	j := closedEndpoint(t)
+
+	_, err := j.Read("broker/Status")
+	assert.Error(t, err)
+	_, open := UnreachableUntil(j.ip)
+	assert.False(t, open)
+
+	_, err = j.Read("broker/Status")
+	assert.Error(t, err)
+	retryAt, open := UnreachableUntil(j.ip)
+	assert.True(t, open)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), retryAt, 5*time.Second)
+	backoff, _ := RetryAfter(j.ip)
+	assert.WithinDuration(t, retryAt, time.Now().Add(backoff), 5*time.Second)
+
+	// no round trip while open
+	_, err = j.Read("broker/Status")
+	circuitOpen := &CircuitOpenError{}
+	assert.True(t, errors.As(err, &circuitOpen))
+	assert.Equal(t, j.ip, circuitOpen.Host)
+
+	// half open, a single probe after the timeout
+	assert.NoError(t, allowRequest(j.ip, retryAt))
+	assert.Error(t, allowRequest(j.ip, retryAt))
+
+	// a failed probe opens it again
+	recordResult(j.ip, errors.New("down"), retryAt)
+	assert.Error(t, allowRequest(j.ip, retryAt.Add(time.Second)))
+	probeAt := retryAt.Add(time.Minute)
+	assert.NoError(t, allowRequest(j.ip, probeAt))
+
+	// a successful probe closes it
+	recordResult(j.ip, nil, probeAt)
+	assert.NoError(t, allowRequest(j.ip, probeAt))
+	_, open = UnreachableUntil(j.ip)
+	assert.False(t, open)
+
+	// disabled
+	SetCircuitBreakerPolicy(CircuitBreakerPolicy{})
+	for i := 0; i < 3; i++ {
+		recordResult(j.ip, errors.New("down"), probeAt)
+	}
+	_, open = UnreachableUntil(j.ip)
+	assert.False(t, open)
+	assert.NoError(t, allowRequest(j.ip, probeAt))
+}
+
+func TestLoadPoliciesFromEnv(t *testing.T) {
+	withPolicies(t, RetryPolicy{InitialBackoff: DefaultRetryInitialBackoff, MaxBackoff: DefaultRetryMaxBackoff, MaxRetries: DefaultRetryMaxRetries},
+		CircuitBreakerPolicy{Threshold: DefaultCircuitBreakerThreshold, OpenTimeout: DefaultCircuitBreakerOpenTimeout})
+
+	env := map[string]string{
+		"JOLOKIA_RETRY_INITIAL_BACKOFF":        "1s",
+		"JOLOKIA_RETRY_MAX_BACKOFF":            "5s",
+		"JOLOKIA_CIRCUIT_BREAKER_THRESHOLD":    "0",
+		"JOLOKIA_CIRCUIT_BREAKER_OPEN_TIMEOUT": "invalid",
+	}
+	LoadPoliciesFromEnv(func(name string) (string, bool) {
+		value, found := env[name]
+		return value, found
+	})
+
+	assert.Equal(t, RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, MaxRetries: DefaultRetryMaxRetries}, retryPolicy)
+	assert.Equal(t, CircuitBreakerPolicy{Threshold: 0, OpenTimeout: DefaultCircuitBreakerOpenTimeout}, circuitBreakerPolicy)
+}
+
+func TestOperatorCertVersionCached(t *testing.T) {
+	common.SetOperatorNameSpace("operator")
+	t.Cleanup(common.UnsetOperatorNameSpace)
+	t.Cleanup(func() {
+		certVersion.Lock()
+		certVersion.value, certVersion.checkedAt = "", time.Time{}
+		certVersion.Unlock()
+	})
+
+	gets := 0
+	client := fake.NewClientBuilder().
+		WithObjects(
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: common.GetOperatorCertSecretName(), Namespace: "operator"}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: common.GetOperatorCASecretName(), Namespace: "operator"}},
+		).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c rtclient.WithWatch, key rtclient.ObjectKey, obj rtclient.Object, opts ...rtclient.GetOption) error {
+				gets++
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+
+	now := time.Now()
+	version := operatorCertVersion(client, "https", now)
+	assert.NotEmpty(t, version)
+	assert.Equal(t, 2, gets)
+
+	assert.Equal(t, version, operatorCertVersion(client, "https", now.Add(time.Second)))
+	assert.Equal(t, 2, gets)
+	assert.Empty(t, operatorCertVersion(client, "http", now))
+
+	operatorCertVersion(client, "https", now.Add(certVersionRefreshInterval))
+	assert.Equal(t, 4, gets)
+}